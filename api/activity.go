@@ -29,6 +29,12 @@ const (
 	ActivityPipelineTaskStatementUpdate ActivityType = "bb.pipeline.task.statement.update"
 	// ActivityPipelineTaskEarliestAllowedTimeUpdate is the type for updating pipeline task the earliest allowed time.
 	ActivityPipelineTaskEarliestAllowedTimeUpdate ActivityType = "bb.pipeline.task.general.earliest-allowed-time.update"
+	// ActivityPipelineTaskMaintenanceWindowOverrideUpdate is the type for overriding the maintenance window of pipeline task.
+	ActivityPipelineTaskMaintenanceWindowOverrideUpdate ActivityType = "bb.pipeline.task.general.maintenance-window-override.update"
+	// ActivityPipelineTaskApprovalCreate is the type for approving pipeline task by one of the required approvers.
+	ActivityPipelineTaskApprovalCreate ActivityType = "bb.pipeline.task.approval.create"
+	// ActivityPipelineStageRolloutHalt is the type for halting the pipeline stage rollout due to too many failed tasks.
+	ActivityPipelineStageRolloutHalt ActivityType = "bb.pipeline.stage.rollout.halt"
 
 	// Member related.
 
@@ -58,6 +64,10 @@ const (
 
 	// ActivitySQLEditorQuery is the type for executing query.
 	ActivitySQLEditorQuery ActivityType = "bb.sql-editor.query"
+	// ActivitySQLEditorAdminExecute is the type for executing statements in the admin execution mode.
+	ActivitySQLEditorAdminExecute ActivityType = "bb.sql-editor.admin-execute"
+	// ActivitySQLEditorExport is the type for exporting query results.
+	ActivitySQLEditorExport ActivityType = "bb.sql-editor.export"
 
 	// Database related.
 
@@ -150,6 +160,40 @@ type ActivityPipelineTaskEarliestAllowedTimeUpdatePayload struct {
 	TaskName  string `json:"taskName"`
 }
 
+// ActivityPipelineTaskMaintenanceWindowOverrideUpdatePayload is the API message payloads for pipeline task maintenance window override updates.
+type ActivityPipelineTaskMaintenanceWindowOverrideUpdatePayload struct {
+	TaskID   int  `json:"taskId"`
+	Override bool `json:"override"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+	TaskName  string `json:"taskName"`
+}
+
+// ActivityPipelineTaskApprovalCreatePayload is the API message payloads for approving pipeline task.
+type ActivityPipelineTaskApprovalCreatePayload struct {
+	TaskID int `json:"taskId"`
+	// ApproverGroupList is the approval groups which the approver belongs to when approving.
+	ApproverGroupList []ApprovalGroupValue `json:"approverGroupList"`
+	// RiskLevel is the risk level of the task deciding the approval rules.
+	RiskLevel RiskLevel `json:"riskLevel"`
+	// Approved is true if the approval satisfies all the approval rules, and the task is approved.
+	Approved bool `json:"approved"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+	TaskName  string `json:"taskName"`
+}
+
+// ActivityPipelineStageRolloutHaltPayload is the API message payloads for halting the pipeline stage rollout.
+type ActivityPipelineStageRolloutHaltPayload struct {
+	StageID           int    `json:"stageId"`
+	StageName         string `json:"stageName"`
+	FailedTaskCount   int    `json:"failedTaskCount"`
+	TaskCount         int    `json:"taskCount"`
+	MaxFailurePercent int    `json:"maxFailurePercent"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+}
+
 // ActivityMemberCreatePayload is the API message payloads for creating members.
 type ActivityMemberCreatePayload struct {
 	PrincipalID    int          `json:"principalId"`
@@ -203,6 +247,31 @@ type ActivitySQLEditorQueryPayload struct {
 	AdviceList   []advisor.Advice `json:"adviceList"`
 }
 
+// ActivitySQLEditorAdminExecutePayload is the API message payloads for the statements executed in the admin execution mode.
+type ActivitySQLEditorAdminExecutePayload struct {
+	// Used by activity table to display info without paying the join cost
+	Statement        string           `json:"statement"`
+	DurationNs       int64            `json:"durationNs"`
+	InstanceName     string           `json:"instanceName"`
+	DatabaseName     string           `json:"databaseName"`
+	Justification    string           `json:"justification"`
+	AffectedRowCount int64            `json:"affectedRowCount"`
+	Error            string           `json:"error"`
+	AdviceList       []advisor.Advice `json:"adviceList"`
+}
+
+// ActivitySQLEditorExportPayload is the API message payloads for the exported query result.
+type ActivitySQLEditorExportPayload struct {
+	// Used by activity table to display info without paying the join cost
+	Statement    string          `json:"statement"`
+	DurationNs   int64           `json:"durationNs"`
+	InstanceName string          `json:"instanceName"`
+	DatabaseName string          `json:"databaseName"`
+	Format       SQLExportFormat `json:"format"`
+	RowCount     int64           `json:"rowCount"`
+	Error        string          `json:"error"`
+}
+
 // Activity is the API message for an activity.
 type Activity struct {
 	ID int `jsonapi:"primary,activity"`
@@ -4,6 +4,86 @@ import (
 	"encoding/json"
 )
 
+// ColumnSensitivity is the sensitivity classification of a column.
+type ColumnSensitivity string
+
+const (
+	// ColumnSensitivityNone is the sensitivity for the column without sensitive data.
+	ColumnSensitivityNone ColumnSensitivity = "NONE"
+	// ColumnSensitivityLow is the sensitivity for the column with low sensitive data, e.g. the email.
+	ColumnSensitivityLow ColumnSensitivity = "LOW"
+	// ColumnSensitivityMedium is the sensitivity for the column with medium sensitive data, e.g. the phone number.
+	ColumnSensitivityMedium ColumnSensitivity = "MEDIUM"
+	// ColumnSensitivityHigh is the sensitivity for the column with high sensitive data, e.g. the credit card number.
+	ColumnSensitivityHigh ColumnSensitivity = "HIGH"
+)
+
+// MaskingType is the type of the data masking applied to the query result.
+type MaskingType string
+
+const (
+	// MaskingTypeNone means the data is returned as is.
+	MaskingTypeNone MaskingType = "NONE"
+	// MaskingTypePartial keeps the first and the last characters, and masks the others.
+	MaskingTypePartial MaskingType = "PARTIAL"
+	// MaskingTypeHash replaces the data with its SHA-256 hash, so the masked data can still be compared.
+	MaskingTypeHash MaskingType = "HASH"
+	// MaskingTypeFull replaces the data with a fixed mask.
+	MaskingTypeFull MaskingType = "FULL"
+)
+
+// maskingTypeStrength is the strength of the masking types, a stronger masking type reveals less data.
+var maskingTypeStrength = map[MaskingType]int{
+	MaskingTypeNone:    0,
+	MaskingTypePartial: 1,
+	MaskingTypeHash:    2,
+	MaskingTypeFull:    3,
+}
+
+// IsValid checks if the column sensitivity is valid.
+func (s ColumnSensitivity) IsValid() bool {
+	switch s {
+	case ColumnSensitivityNone, ColumnSensitivityLow, ColumnSensitivityMedium, ColumnSensitivityHigh:
+		return true
+	}
+	return false
+}
+
+// GetColumnMaskingType returns the masking type of the column with the sensitivity for the role.
+func GetColumnMaskingType(sensitivity ColumnSensitivity, role Role) MaskingType {
+	switch sensitivity {
+	case ColumnSensitivityLow:
+		if role == Developer {
+			return MaskingTypePartial
+		}
+	case ColumnSensitivityMedium:
+		switch role {
+		case Developer:
+			return MaskingTypeFull
+		case DBA:
+			return MaskingTypePartial
+		}
+	case ColumnSensitivityHigh:
+		switch role {
+		case Developer:
+			return MaskingTypeFull
+		case DBA:
+			return MaskingTypeHash
+		case Owner:
+			return MaskingTypePartial
+		}
+	}
+	return MaskingTypeNone
+}
+
+// StrongerMaskingType returns the stronger one of the two masking types.
+func StrongerMaskingType(a, b MaskingType) MaskingType {
+	if maskingTypeStrength[b] > maskingTypeStrength[a] {
+		return b
+	}
+	return a
+}
+
 // Column is the API message for a table column.
 type Column struct {
 	ID int `jsonapi:"primary,column"`
@@ -27,6 +107,8 @@ type Column struct {
 	CharacterSet string  `json:"characterSet"`
 	Collation    string  `json:"collation"`
 	Comment      string  `json:"comment"`
+	// Sensitivity is classified by the user and kept across the schema syncs.
+	Sensitivity ColumnSensitivity `json:"sensitivity"`
 }
 
 // ColumnCreate is the API message for creating a column.
@@ -48,6 +130,7 @@ type ColumnCreate struct {
 	CharacterSet string
 	Collation    string
 	Comment      string
+	Sensitivity  ColumnSensitivity
 }
 
 // ColumnFind is the API message for finding columns.
@@ -77,6 +160,9 @@ type ColumnPatch struct {
 	// Standard fields
 	// Value is assigned from the jwt subject field passed by the client.
 	UpdaterID int
+
+	// Domain specific fields
+	Sensitivity *ColumnSensitivity `jsonapi:"attr,sensitivity"`
 }
 
 // ColumnDelete is the API message for deleting a column.
@@ -69,6 +69,9 @@ type DataSource struct {
 	// HostOverride and PortOverride are only used for read-only data sources for user's read-replica instances.
 	HostOverride string `jsonapi:"attr,hostOverride"`
 	PortOverride string `jsonapi:"attr,portOverride"`
+	// PrincipalID is set for the read-only data source requested by the data source request issue.
+	// It's only used by the SQL editor queries of the principal.
+	PrincipalID *int `jsonapi:"attr,principalId"`
 }
 
 // DataSourceCreate is the API message for creating a data source.
@@ -91,6 +94,8 @@ type DataSourceCreate struct {
 	SslKey       string         `jsonapi:"attr,sslKey"`
 	HostOverride string         `jsonapi:"attr,hostOverride"`
 	PortOverride string         `jsonapi:"attr,portOverride"`
+	// PrincipalID is only set by the data source request issue.
+	PrincipalID *int
 }
 
 // DataSourceFind is the API message for finding data sources.
@@ -102,7 +107,8 @@ type DataSourceFind struct {
 	DatabaseID *int
 
 	// Domain specific fields
-	Type *DataSourceType
+	Type        *DataSourceType
+	PrincipalID *int
 }
 
 func (find *DataSourceFind) String() string {
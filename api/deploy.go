@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/bytebase/bytebase/common"
 )
@@ -42,6 +43,58 @@ type Deployment struct {
 // DeploymentSpec is the API message for deployment specification.
 type DeploymentSpec struct {
 	Selector *LabelSelector `json:"selector"`
+	// Rollout controls how the stage of this deployment is rolled out.
+	Rollout DeploymentRollout `json:"rollout"`
+}
+
+// DeploymentRollout is the API message for the rollout controls of a deployment.
+// The zero value rolls out the stage without any gating.
+type DeploymentRollout struct {
+	// MaxConcurrentDatabases is the maximum number of databases running at the same time in the stage.
+	// Zero means unlimited.
+	MaxConcurrentDatabases int `json:"maxConcurrentDatabases" jsonapi:"attr,maxConcurrentDatabases"`
+	// PauseSeconds is the pause after the previous stage finishes before the stage starts.
+	PauseSeconds int `json:"pauseSeconds" jsonapi:"attr,pauseSeconds"`
+	// MaxFailurePercent is the tolerated percentage of failed tasks in the stage.
+	// The remaining stages proceed as long as the failed tasks don't exceed the percentage, and are halted otherwise.
+	// Nil means no failure is tolerated, and any failed task blocks the remaining stages.
+	MaxFailurePercent *int `json:"maxFailurePercent,omitempty" jsonapi:"attr,maxFailurePercent,omitempty"`
+	// ValidationQuery is run against the database after the task finishes.
+	ValidationQuery string `json:"validationQuery" jsonapi:"attr,validationQuery"`
+	// ValidationExpectedResult must match the first column of the first row returned by the validation query, otherwise the task fails.
+	ValidationExpectedResult string `json:"validationExpectedResult" jsonapi:"attr,validationExpectedResult"`
+}
+
+// Scan implements database/sql Scanner interface, converts JSONB to DeploymentRollout struct.
+func (r *DeploymentRollout) Scan(src interface{}) error {
+	if bs, ok := src.([]byte); ok {
+		return json.Unmarshal(bs, r)
+	}
+	return errors.New("failed to scan rollout")
+}
+
+// IsHalted returns true if the rollout tolerates failures and the failed tasks exceed the tolerated percentage.
+func (r *DeploymentRollout) IsHalted(failedCount, totalCount int) bool {
+	if r.MaxFailurePercent == nil || failedCount <= 0 {
+		return false
+	}
+	return failedCount*100 > *r.MaxFailurePercent*totalCount
+}
+
+func (r *DeploymentRollout) validate() error {
+	if r.MaxConcurrentDatabases < 0 {
+		return common.Errorf(common.Invalid, "max concurrent databases must not be negative")
+	}
+	if r.PauseSeconds < 0 {
+		return common.Errorf(common.Invalid, "pause seconds must not be negative")
+	}
+	if r.MaxFailurePercent != nil && (*r.MaxFailurePercent < 0 || *r.MaxFailurePercent > 100) {
+		return common.Errorf(common.Invalid, "max failure percent must be between 0 and 100, got %d", *r.MaxFailurePercent)
+	}
+	if r.ValidationQuery == "" && r.ValidationExpectedResult != "" {
+		return common.Errorf(common.Invalid, "validation expected result requires a validation query")
+	}
+	return nil
 }
 
 // LabelSelector is the API message for label selector.
@@ -51,15 +104,18 @@ type LabelSelector struct {
 }
 
 // OperatorType is the type of label selector requirement operator.
-// Valid operators are In, Exists.
-// Note: NotIn and DoesNotExist are not supported initially.
+// Valid operators are In, NotIn, Exists and DoesNotExist.
 type OperatorType string
 
 const (
 	// InOperatorType is the operator type for In.
 	InOperatorType OperatorType = "In"
+	// NotInOperatorType is the operator type for NotIn.
+	NotInOperatorType OperatorType = "NotIn"
 	// ExistsOperatorType is the operator type for Exists.
 	ExistsOperatorType OperatorType = "Exists"
+	// DoesNotExistOperatorType is the operator type for DoesNotExist.
+	DoesNotExistOperatorType OperatorType = "DoesNotExist"
 )
 
 // LabelSelectorRequirement is the API message for label selector.
@@ -99,6 +155,41 @@ type DeploymentConfigUpsert struct {
 	Payload string `jsonapi:"attr,payload"`
 }
 
+// DeploymentConfigPreview is the API message for previewing the databases matched by a deployment configuration.
+type DeploymentConfigPreview struct {
+	// Related fields
+	ProjectID int
+
+	// Domain specific fields
+	// Payload is a json serialization of DeploymentSchedule. The deployment configuration of the project is previewed if it's empty.
+	Payload string `jsonapi:"attr,payload"`
+	// DatabaseName is the base database name to match the tenant databases with the database name template of the project.
+	// The databases are matched by labels only if it's empty.
+	DatabaseName string `jsonapi:"attr,databaseName"`
+}
+
+// DeploymentPreview is the API message for the databases matched by each deployment of a deployment schedule.
+type DeploymentPreview struct {
+	Deployments []*DeploymentPreviewDeployment `json:"deployments"`
+	// UnmatchedDatabaseList is the list of databases not matched by any deployment.
+	UnmatchedDatabaseList []*DeploymentPreviewDatabase `json:"unmatchedDatabaseList"`
+}
+
+// DeploymentPreviewDeployment is the API message for the databases matched by a deployment.
+// The databases are deployed in a stage unless the list is empty.
+type DeploymentPreviewDeployment struct {
+	Name         string                       `json:"name"`
+	DatabaseList []*DeploymentPreviewDatabase `json:"databaseList"`
+}
+
+// DeploymentPreviewDatabase is the API message for a database in the deployment preview.
+type DeploymentPreviewDatabase struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	InstanceName    string `json:"instanceName"`
+	EnvironmentName string `json:"environmentName"`
+}
+
 // ValidateAndGetDeploymentSchedule validates and returns the deployment schedule.
 // Note: this validation only checks whether the payloads is a valid json, however, invalid field name errors are ignored.
 func ValidateAndGetDeploymentSchedule(payload string) (*DeploymentSchedule, error) {
@@ -114,11 +205,11 @@ func ValidateAndGetDeploymentSchedule(payload string) (*DeploymentSchedule, erro
 		hasEnv := false
 		for _, e := range d.Spec.Selector.MatchExpressions {
 			switch e.Operator {
-			case InOperatorType:
+			case InOperatorType, NotInOperatorType:
 				if len(e.Values) == 0 {
 					return nil, common.Errorf(common.Invalid, "expression key %q with %q operator should have at least one value", e.Key, e.Operator)
 				}
-			case ExistsOperatorType:
+			case ExistsOperatorType, DoesNotExistOperatorType:
 				if len(e.Values) > 0 {
 					return nil, common.Errorf(common.Invalid, "expression key %q with %q operator shouldn't have values", e.Key, e.Operator)
 				}
@@ -135,6 +226,9 @@ func ValidateAndGetDeploymentSchedule(payload string) (*DeploymentSchedule, erro
 		if !hasEnv {
 			return nil, common.Errorf(common.Invalid, "deployment should contain %q label", EnvironmentKeyName)
 		}
+		if err := d.Spec.Rollout.validate(); err != nil {
+			return nil, common.Wrapf(err, common.Invalid, "deployment %q has invalid rollout", d.Name)
+		}
 	}
 	return schedule, nil
 }
@@ -7,6 +7,7 @@ import (
 )
 
 func TestGetDeploymentSchedule(t *testing.T) {
+	rolloutMaxFailurePercent := 10
 	tests := []struct {
 		name    string
 		payload string
@@ -77,6 +78,21 @@ func TestGetDeploymentSchedule(t *testing.T) {
 			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"location","operator":"Exists","values":["us-central1","europe-west1"]}]}}}]}`,
 			nil,
 			"operator shouldn't have values",
+		}, {
+			"notInOperatorWithNoValue",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"location","operator":"NotIn"}]}}}]}`,
+			nil,
+			"operator should have at least one value",
+		}, {
+			"doesNotExistOperatorWithValues",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"tier","operator":"DoesNotExist","values":["gold"]}]}}}]}`,
+			nil,
+			"operator shouldn't have values",
+		}, {
+			"environmentNotInOperator",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"NotIn","values":["prod"]}]}}}]}`,
+			nil,
+			"should must use operator",
 		}, {
 			"invalidOperator",
 			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"location","operator":"invalid"}]}}}]}`,
@@ -97,6 +113,50 @@ func TestGetDeploymentSchedule(t *testing.T) {
 			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod", "dev"]},{"key":"location","operator":"In","values":["us-central1","europe-west1"]}]}}}]}`,
 			nil,
 			"should must use operator",
+		}, {
+			"rollout",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"rollout":{"maxConcurrentDatabases":2,"pauseSeconds":60,"maxFailurePercent":10,"validationQuery":"SELECT COUNT(*) FROM t","validationExpectedResult":"1"}}}]}`,
+			&DeploymentSchedule{
+				Deployments: []*Deployment{
+					{
+						Name: "deployment1",
+						Spec: &DeploymentSpec{
+							Selector: &LabelSelector{
+								MatchExpressions: []*LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"prod"},
+									},
+								},
+							},
+							Rollout: DeploymentRollout{
+								MaxConcurrentDatabases:   2,
+								PauseSeconds:             60,
+								MaxFailurePercent:        &rolloutMaxFailurePercent,
+								ValidationQuery:          "SELECT COUNT(*) FROM t",
+								ValidationExpectedResult: "1",
+							},
+						},
+					},
+				},
+			},
+			"",
+		}, {
+			"rolloutNegativeConcurrency",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"rollout":{"maxConcurrentDatabases":-1}}}]}`,
+			nil,
+			"max concurrent databases must not be negative",
+		}, {
+			"rolloutInvalidFailurePercent",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"rollout":{"maxFailurePercent":101}}}]}`,
+			nil,
+			"max failure percent must be between 0 and 100",
+		}, {
+			"rolloutExpectedResultWithoutQuery",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"rollout":{"validationExpectedResult":"1"}}}]}`,
+			nil,
+			"validation expected result requires a validation query",
 		},
 	}
 
@@ -110,3 +170,23 @@ func TestGetDeploymentSchedule(t *testing.T) {
 		require.Equal(t, cfg, test.wantCfg)
 	}
 }
+
+func TestDeploymentRolloutIsHalted(t *testing.T) {
+	maxFailurePercent := 25
+	tests := []struct {
+		rollout     DeploymentRollout
+		failedCount int
+		totalCount  int
+		want        bool
+	}{
+		{DeploymentRollout{}, 1, 4, false},
+		{DeploymentRollout{MaxFailurePercent: &maxFailurePercent}, 0, 4, false},
+		{DeploymentRollout{MaxFailurePercent: &maxFailurePercent}, 1, 4, false},
+		{DeploymentRollout{MaxFailurePercent: &maxFailurePercent}, 2, 4, true},
+		{DeploymentRollout{MaxFailurePercent: &maxFailurePercent}, 1, 3, true},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, test.rollout.IsHalted(test.failedCount, test.totalCount), "%d/%d", test.failedCount, test.totalCount)
+	}
+}
@@ -101,6 +101,10 @@ type InstancePatch struct {
 // DataSourceFromInstanceWithType gets a typed data source from a instance.
 func DataSourceFromInstanceWithType(instance *Instance, dataSourceType DataSourceType) *DataSource {
 	for _, dataSource := range instance.DataSourceList {
+		// The data source requested by a principal is not shared with others.
+		if dataSource.PrincipalID != nil {
+			continue
+		}
 		if dataSource.Type == dataSourceType {
 			return dataSource
 		}
@@ -14,6 +14,8 @@ type InstanceUser struct {
 	// Domain specific fields
 	Name  string `jsonapi:"attr,name"`
 	Grant string `jsonapi:"attr,grant"`
+	// ExpireTs is the time when the user granted by the database grant issue expires, 0 means never.
+	ExpireTs int64 `jsonapi:"attr,expireTs"`
 }
 
 // InstanceUserUpsert is the API message for upserting an instance user.
@@ -27,6 +29,10 @@ type InstanceUserUpsert struct {
 	// Domain specific fields
 	Name  string `jsonapi:"attr,name"`
 	Grant string `jsonapi:"attr,grant"`
+	// ExpireTs keeps the existing value if nil.
+	ExpireTs *int64
+	// Password is the one-time revealed password of the user granted by the database grant issue, and it keeps the existing value if nil.
+	Password *string
 }
 
 // InstanceUserFind is the API message for finding instance users.
@@ -35,6 +41,11 @@ type InstanceUserFind struct {
 
 	// Related fields
 	InstanceID *int
+
+	// Domain specific fields
+	Name *string
+	// ExpireTsBefore finds the expiring users whose expire time is no later than it.
+	ExpireTsBefore *int64
 }
 
 func (find *InstanceUserFind) String() string {
@@ -45,6 +56,17 @@ func (find *InstanceUserFind) String() string {
 	return string(str)
 }
 
+// DatabaseGrantCredential is the API message for the credential of the temporary database user granted by the database grant issue.
+type DatabaseGrantCredential struct {
+	// ID is the database grant task ID.
+	ID int `jsonapi:"primary,databaseGrantCredential"`
+
+	// Domain specific fields
+	Username string `jsonapi:"attr,username"`
+	Password string `jsonapi:"attr,password"`
+	ExpireTs int64  `jsonapi:"attr,expireTs"`
+}
+
 // InstanceUserDelete is the API message for deleting an instance user.
 type InstanceUserDelete struct {
 	ID int
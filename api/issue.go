@@ -212,6 +212,39 @@ type PITRContext struct {
 	PointInTimeTs *int64 `json:"pointInTimeTs"`
 }
 
+// DatabaseGrantAccess is the access granted to a database by the database grant issue.
+type DatabaseGrantAccess string
+
+const (
+	// DatabaseGrantRead is the access for querying the database.
+	DatabaseGrantRead DatabaseGrantAccess = "READ"
+	// DatabaseGrantWrite is the access for querying and changing the data of the database.
+	DatabaseGrantWrite DatabaseGrantAccess = "WRITE"
+)
+
+// DatabaseGrantContext is the issue create context for granting the access of a database.
+type DatabaseGrantContext struct {
+	// DatabaseID is the ID of a database.
+	DatabaseID int `json:"databaseId"`
+	// Access is the access to grant.
+	Access DatabaseGrantAccess `json:"access"`
+	// ExpireTs is the time when the granted access is revoked.
+	// Represented in UNIX timestamp in seconds.
+	ExpireTs int64 `json:"expireTs"`
+}
+
+// DataSourceRequestContext is the issue create context for requesting a read-only data source of a database.
+type DataSourceRequestContext struct {
+	// DatabaseID is the ID of a database.
+	DatabaseID int `json:"databaseId"`
+	// HostOverride and PortOverride point the data source at the read-replica of the database instance.
+	HostOverride string `json:"hostOverride"`
+	PortOverride string `json:"portOverride"`
+	// ExpireTs is the time when the data source is deleted.
+	// Represented in UNIX timestamp in seconds.
+	ExpireTs int64 `json:"expireTs"`
+}
+
 // IssueFind is the API message for finding issues.
 type IssueFind struct {
 	ID *int
@@ -2,9 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/advisor"
 )
 
@@ -17,6 +19,9 @@ type PipelineApprovalValue string
 // AssigneeGroupValue is the value for assignee group policy.
 type AssigneeGroupValue string
 
+// ApprovalGroupValue is the value for the approver group in the approval rules.
+type ApprovalGroupValue string
+
 // BackupPlanPolicySchedule is value for backup plan policy.
 type BackupPlanPolicySchedule string
 
@@ -35,6 +40,18 @@ const (
 	PolicyTypeSQLReview PolicyType = "bb.policy.sql-review"
 	// PolicyTypeEnvironmentTier is the tier of an environment.
 	PolicyTypeEnvironmentTier PolicyType = "bb.policy.environment-tier"
+	// PolicyTypeSQLExport is the SQL editor result export policy type.
+	PolicyTypeSQLExport PolicyType = "bb.policy.sql-export"
+	// PolicyTypeQueryTimeout is the SQL editor query max execution time policy type.
+	PolicyTypeQueryTimeout PolicyType = "bb.policy.query-timeout"
+	// PolicyTypeTaskRetry is the automatic task retry policy type.
+	PolicyTypeTaskRetry PolicyType = "bb.policy.task-retry"
+	// PolicyTypeMaintenanceWindow is the maintenance window policy type.
+	PolicyTypeMaintenanceWindow PolicyType = "bb.policy.maintenance-window"
+	// PolicyTypeTaskConcurrency is the task concurrency limit policy type.
+	PolicyTypeTaskConcurrency PolicyType = "bb.policy.task-concurrency"
+	// PolicyTypeTaskRunLogRetention is the task run log retention policy type.
+	PolicyTypeTaskRunLogRetention PolicyType = "bb.policy.task-run-log-retention"
 
 	// PipelineApprovalValueManualNever means the pipeline will automatically be approved without user intervention.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -46,6 +63,15 @@ const (
 	// AssigneeGroupValueProjectOwner means the assignee can be selected from the project owners.
 	AssigneeGroupValueProjectOwner AssigneeGroupValue = "PROJECT_OWNER"
 
+	// ApprovalGroupValueWorkspaceOwner means the approver should be a workspace owner.
+	ApprovalGroupValueWorkspaceOwner ApprovalGroupValue = "WORKSPACE_OWNER"
+	// ApprovalGroupValueWorkspaceDBA means the approver should be a workspace DBA.
+	ApprovalGroupValueWorkspaceDBA ApprovalGroupValue = "WORKSPACE_DBA"
+	// ApprovalGroupValueWorkspaceOwnerOrDBA means the approver should be a workspace owner or DBA.
+	ApprovalGroupValueWorkspaceOwnerOrDBA ApprovalGroupValue = "WORKSPACE_OWNER_OR_DBA"
+	// ApprovalGroupValueProjectOwner means the approver should be an owner of the issue project.
+	ApprovalGroupValueProjectOwner ApprovalGroupValue = "PROJECT_OWNER"
+
 	// BackupPlanPolicyScheduleUnset is NEVER backup plan policy value.
 	BackupPlanPolicyScheduleUnset BackupPlanPolicySchedule = "UNSET"
 	// BackupPlanPolicyScheduleDaily is DAILY backup plan policy value.
@@ -57,15 +83,44 @@ const (
 	EnvironmentTierValueProtected EnvironmentTierValue = "PROTECTED"
 	// EnvironmentTierValueUnprotected is UNPROTECTED environment tier value.
 	EnvironmentTierValueUnprotected EnvironmentTierValue = "UNPROTECTED"
+
+	// DefaultSQLExportMaxRowCount is the default max row count of the SQL editor result export.
+	DefaultSQLExportMaxRowCount = 100000
+
+	// DefaultTaskRetryInitialBackoffSeconds is the default backoff before the first retry of a failed task.
+	DefaultTaskRetryInitialBackoffSeconds = 10
+	// DefaultTaskRetryMaxBackoffSeconds is the default max backoff between the retries of a failed task.
+	DefaultTaskRetryMaxBackoffSeconds = 300
+	// MaxTaskRetryAttempts is the max attempts of running a task in the task retry policy.
+	MaxTaskRetryAttempts = 10
+
+	// DefaultTaskRunLogRetentionDays is the default number of days to keep the task run logs.
+	DefaultTaskRunLogRetentionDays = 30
+
+	// MaxApprovalRuleCount is the max count of the approvals required by an approval rule.
+	MaxApprovalRuleCount = 10
+
+	// MaxMaintenanceWindowDurationMinutes is the max duration of a maintenance window, which is a week.
+	MaxMaintenanceWindowDurationMinutes = 7 * 24 * 60
+	// maintenanceWindowDateFormat is the format of the blackout dates in the maintenance window policy.
+	maintenanceWindowDateFormat = "2006-01-02"
+	// maintenanceWindowSearchLimit is how far the maintenance window policy searches for the next allowed time.
+	maintenanceWindowSearchLimit = 366 * 24 * time.Hour
 )
 
 var (
 	// PolicyTypes is a set of all policy types.
 	PolicyTypes = map[PolicyType]bool{
-		PolicyTypePipelineApproval: true,
-		PolicyTypeBackupPlan:       true,
-		PolicyTypeSQLReview:        true,
-		PolicyTypeEnvironmentTier:  true,
+		PolicyTypePipelineApproval:    true,
+		PolicyTypeBackupPlan:          true,
+		PolicyTypeSQLReview:           true,
+		PolicyTypeEnvironmentTier:     true,
+		PolicyTypeSQLExport:           true,
+		PolicyTypeQueryTimeout:        true,
+		PolicyTypeTaskRetry:           true,
+		PolicyTypeMaintenanceWindow:   true,
+		PolicyTypeTaskConcurrency:     true,
+		PolicyTypeTaskRunLogRetention: true,
 	}
 )
 
@@ -141,6 +196,12 @@ type PipelineApprovalPolicy struct {
 	// If there is no value provided in the AssigneeGroupList, we use the the workspace owners and DBAs (default) as the available assignee.
 	// If the AssigneeGroupValue is PROJECT_OWNER, the available assignee is the project owners.
 	AssigneeGroupList []AssigneeGroup `json:"assigneeGroupList"`
+	// ApprovalRuleList is the approvals required for the tasks in MANUAL_APPROVAL_ALWAYS mode, e.g. one project owner and one DBA.
+	// If there is no rule, the tasks are approved by a single approval from the assignee group.
+	ApprovalRuleList []ApprovalRule `json:"approvalRuleList"`
+	// RiskLevelApprovalRuleMap is the approvals required for the tasks at the risk levels, which overrides the ApprovalRuleList.
+	// The risky tasks require the approvals even if the policy value is MANUAL_APPROVAL_NEVER.
+	RiskLevelApprovalRuleMap map[RiskLevel][]ApprovalRule `json:"riskLevelApprovalRuleMap"`
 }
 
 func (pa *PipelineApprovalPolicy) String() (string, error) {
@@ -151,6 +212,17 @@ func (pa *PipelineApprovalPolicy) String() (string, error) {
 	return string(s), nil
 }
 
+// GetApprovalRuleList returns the approval rules for the tasks at the risk level.
+func (pa *PipelineApprovalPolicy) GetApprovalRuleList(riskLevel RiskLevel) []ApprovalRule {
+	if ruleList, ok := pa.RiskLevelApprovalRuleMap[riskLevel]; ok {
+		return ruleList
+	}
+	if pa.Value == PipelineApprovalValueManualAlways {
+		return pa.ApprovalRuleList
+	}
+	return nil
+}
+
 // UnmarshalPipelineApprovalPolicy will unmarshal payload to pipeline approval policy.
 func UnmarshalPipelineApprovalPolicy(payload string) (*PipelineApprovalPolicy, error) {
 	var pa PipelineApprovalPolicy
@@ -174,6 +246,83 @@ func (p *AssigneeGroup) String() (string, error) {
 	return string(s), nil
 }
 
+// ApprovalRule is the rule requiring Count approvals from the approvers in Group.
+type ApprovalRule struct {
+	Group ApprovalGroupValue `json:"group"`
+	Count int                `json:"count"`
+}
+
+// IsApprovalRuleListSatisfied returns true if the approvals satisfy every rule in ruleList.
+// approverGroupList is the groups of each approver, and an approver is counted for at most one rule.
+func IsApprovalRuleListSatisfied(ruleList []ApprovalRule, approverGroupList [][]ApprovalGroupValue) bool {
+	// Expand the rules into the slots, and match the approvers to the slots by augmenting paths,
+	// so that an approver in multiple groups fills the slot which others can't fill.
+	var slotList []ApprovalGroupValue
+	for _, rule := range ruleList {
+		for i := 0; i < rule.Count; i++ {
+			slotList = append(slotList, rule.Group)
+		}
+	}
+	if len(slotList) > len(approverGroupList) {
+		return false
+	}
+	// slotApprover is the index of the approver matched to the slot, or -1.
+	slotApprover := make([]int, len(slotList))
+	for i := range slotApprover {
+		slotApprover[i] = -1
+	}
+	var match func(approver int, visited []bool) bool
+	match = func(approver int, visited []bool) bool {
+		for slot, group := range slotList {
+			if visited[slot] || !containsApprovalGroup(approverGroupList[approver], group) {
+				continue
+			}
+			visited[slot] = true
+			if slotApprover[slot] < 0 || match(slotApprover[slot], visited) {
+				slotApprover[slot] = approver
+				return true
+			}
+		}
+		return false
+	}
+	matched := 0
+	for approver := range approverGroupList {
+		if match(approver, make([]bool, len(slotList))) {
+			matched++
+		}
+	}
+	return matched == len(slotList)
+}
+
+func validateApprovalRuleList(ruleList []ApprovalRule) error {
+	groupSeen := make(map[ApprovalGroupValue]bool)
+	for _, rule := range ruleList {
+		if rule.Group != ApprovalGroupValueWorkspaceOwner &&
+			rule.Group != ApprovalGroupValueWorkspaceDBA &&
+			rule.Group != ApprovalGroupValueWorkspaceOwnerOrDBA &&
+			rule.Group != ApprovalGroupValueProjectOwner {
+			return errors.Errorf("invalid approval rule group %q", rule.Group)
+		}
+		if groupSeen[rule.Group] {
+			return errors.Errorf("duplicate approval rule group %q", rule.Group)
+		}
+		groupSeen[rule.Group] = true
+		if rule.Count <= 0 || rule.Count > MaxApprovalRuleCount {
+			return errors.Errorf("invalid approval rule count %d for group %q, should be between 1 and %d", rule.Count, rule.Group, MaxApprovalRuleCount)
+		}
+	}
+	return nil
+}
+
+func containsApprovalGroup(groupList []ApprovalGroupValue, group ApprovalGroupValue) bool {
+	for _, g := range groupList {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
 // BackupPlanPolicy is the policy configuration for backup plan.
 type BackupPlanPolicy struct {
 	Schedule BackupPlanPolicySchedule `json:"schedule"`
@@ -229,6 +378,259 @@ func UnmarshalEnvironmentTierPolicy(payload string) (*EnvironmentTierPolicy, err
 	return &p, nil
 }
 
+// SQLExportPolicy is the policy of exporting the SQL editor query results.
+type SQLExportPolicy struct {
+	// MaxRowCount is the max row count of the exported query result.
+	MaxRowCount int `json:"maxRowCount"`
+}
+
+func (p *SQLExportPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalSQLExportPolicy will unmarshal payload to SQL export policy.
+func UnmarshalSQLExportPolicy(payload string) (*SQLExportPolicy, error) {
+	var p SQLExportPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal SQL export policy %q", payload)
+	}
+	return &p, nil
+}
+
+// QueryTimeoutPolicy is the policy of the max execution time of the SQL editor queries.
+type QueryTimeoutPolicy struct {
+	// MaxExecutionSeconds is the max execution time of a query in seconds, 0 means no limit.
+	MaxExecutionSeconds int `json:"maxExecutionSeconds"`
+}
+
+func (p *QueryTimeoutPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalQueryTimeoutPolicy will unmarshal payload to query timeout policy.
+func UnmarshalQueryTimeoutPolicy(payload string) (*QueryTimeoutPolicy, error) {
+	var p QueryTimeoutPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal query timeout policy %q", payload)
+	}
+	return &p, nil
+}
+
+// TaskRetryRule is the rule of retrying the task failed with the retryable errors, e.g. connection reset, lock wait timeout and deadlock.
+type TaskRetryRule struct {
+	// MaxAttempts is the max attempts of running a task including the first run, 1 means no retry.
+	MaxAttempts int `json:"maxAttempts"`
+	// InitialBackoffSeconds is the backoff before the first retry, which doubles for each subsequent retry.
+	InitialBackoffSeconds int `json:"initialBackoffSeconds"`
+	// MaxBackoffSeconds is the max backoff between the retries.
+	MaxBackoffSeconds int `json:"maxBackoffSeconds"`
+}
+
+// GetBackoff returns the backoff before retrying the task failed in the given attempt, starting from 1.
+func (r *TaskRetryRule) GetBackoff(attempt int) time.Duration {
+	backoff := time.Duration(r.InitialBackoffSeconds) * time.Second
+	maxBackoff := time.Duration(r.MaxBackoffSeconds) * time.Second
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func (r *TaskRetryRule) validate() error {
+	if r.MaxAttempts < 1 || r.MaxAttempts > MaxTaskRetryAttempts {
+		return errors.Errorf("invalid task retry max attempts %d, should be between 1 and %d", r.MaxAttempts, MaxTaskRetryAttempts)
+	}
+	if r.InitialBackoffSeconds <= 0 {
+		return errors.Errorf("invalid task retry initial backoff seconds %d", r.InitialBackoffSeconds)
+	}
+	if r.MaxBackoffSeconds < r.InitialBackoffSeconds {
+		return errors.Errorf("invalid task retry max backoff seconds %d, should not be less than the initial backoff seconds %d", r.MaxBackoffSeconds, r.InitialBackoffSeconds)
+	}
+	return nil
+}
+
+// TaskRetryPolicy is the policy of automatically retrying the failed tasks.
+type TaskRetryPolicy struct {
+	// Default is the retry rule applied to the task types without their own rule.
+	Default TaskRetryRule `json:"default"`
+	// TaskTypeRuleMap is the retry rules of the specific task types.
+	TaskTypeRuleMap map[TaskType]TaskRetryRule `json:"taskTypeRuleMap,omitempty"`
+}
+
+// GetRule returns the retry rule of the task type.
+func (p *TaskRetryPolicy) GetRule(taskType TaskType) TaskRetryRule {
+	if rule, ok := p.TaskTypeRuleMap[taskType]; ok {
+		return rule
+	}
+	return p.Default
+}
+
+func (p *TaskRetryPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalTaskRetryPolicy will unmarshal payload to task retry policy.
+func UnmarshalTaskRetryPolicy(payload string) (*TaskRetryPolicy, error) {
+	var p TaskRetryPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal task retry policy %q", payload)
+	}
+	return &p, nil
+}
+
+// TaskConcurrencyPolicy is the policy of the max number of tasks running at the same time in an environment.
+// The tasks exceeding the limits wait in the queue until the running tasks finish.
+type TaskConcurrencyPolicy struct {
+	// InstanceLimit is the max number of running tasks on each instance in the environment, 0 means no limit.
+	InstanceLimit int `json:"instanceLimit"`
+	// EnvironmentLimit is the max number of running tasks in the environment, 0 means no limit.
+	EnvironmentLimit int `json:"environmentLimit"`
+}
+
+func (p *TaskConcurrencyPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalTaskConcurrencyPolicy will unmarshal payload to task concurrency policy.
+func UnmarshalTaskConcurrencyPolicy(payload string) (*TaskConcurrencyPolicy, error) {
+	var p TaskConcurrencyPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal task concurrency policy %q", payload)
+	}
+	return &p, nil
+}
+
+// TaskRunLogRetentionPolicy is the policy of how long the task run logs are kept in an environment.
+type TaskRunLogRetentionPolicy struct {
+	// RetentionDays is the number of days to keep the task run logs, 0 means keeping them forever.
+	RetentionDays int `json:"retentionDays"`
+}
+
+func (p *TaskRunLogRetentionPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalTaskRunLogRetentionPolicy will unmarshal payload to task run log retention policy.
+func UnmarshalTaskRunLogRetentionPolicy(payload string) (*TaskRunLogRetentionPolicy, error) {
+	var p TaskRunLogRetentionPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal task run log retention policy %q", payload)
+	}
+	return &p, nil
+}
+
+// MaintenanceWindow is a recurring time window allowing the tasks to run.
+type MaintenanceWindow struct {
+	// Cron is the 5-field cron expression of when the window starts, e.g. "0 22 * * 1-5" for 22:00 on weekdays.
+	Cron string `json:"cron"`
+	// DurationMinutes is how long the window lasts after it starts.
+	DurationMinutes int `json:"durationMinutes"`
+}
+
+// MaintenanceWindowPolicy is the policy of the time windows allowing the tasks to run.
+type MaintenanceWindowPolicy struct {
+	// TimeZone is the IANA time zone of the windows and the blackout dates, e.g. "America/New_York". Empty means UTC.
+	TimeZone string `json:"timeZone"`
+	// WindowList is the recurring windows allowing the tasks to run, empty means any time except the blackout dates.
+	WindowList []MaintenanceWindow `json:"windowList"`
+	// BlackoutDateList is the dates in the format of "YYYY-MM-DD" on which no task is allowed to run.
+	BlackoutDateList []string `json:"blackoutDateList"`
+}
+
+func (p *MaintenanceWindowPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// GetNextAllowedTime returns the earliest time at or after t allowed by the policy.
+// It returns an error if there is no allowed time within a year.
+func (p *MaintenanceWindowPolicy) GetNextAllowedTime(t time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid time zone %q", p.TimeZone)
+	}
+	var scheduleList []*common.CronSchedule
+	for _, window := range p.WindowList {
+		schedule, err := common.ParseCron(window.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		scheduleList = append(scheduleList, schedule)
+	}
+	blackoutDates := make(map[string]bool)
+	for _, date := range p.BlackoutDateList {
+		blackoutDates[date] = true
+	}
+
+	t = t.In(location)
+	limit := t.Add(maintenanceWindowSearchLimit)
+	for t.Before(limit) {
+		if blackoutDates[t.Format(maintenanceWindowDateFormat)] {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if len(scheduleList) == 0 {
+			return t, nil
+		}
+		// t is in the window if the window starts in (t - duration, t].
+		// Otherwise, we move on to the earliest start of the windows after t.
+		var next time.Time
+		for i, schedule := range scheduleList {
+			duration := time.Duration(p.WindowList[i].DurationMinutes) * time.Minute
+			start, ok := schedule.Next(t.Add(-duration).Add(time.Nanosecond))
+			if !ok {
+				continue
+			}
+			if !start.After(t) {
+				return t, nil
+			}
+			if next.IsZero() || start.Before(next) {
+				next = start
+			}
+		}
+		if next.IsZero() {
+			break
+		}
+		t = next
+	}
+	return time.Time{}, errors.Errorf("no time is allowed by the maintenance window policy in a year")
+}
+
+// UnmarshalMaintenanceWindowPolicy will unmarshal payload to maintenance window policy.
+func UnmarshalMaintenanceWindowPolicy(payload string) (*MaintenanceWindowPolicy, error) {
+	var p MaintenanceWindowPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal maintenance window policy %q", payload)
+	}
+	return &p, nil
+}
+
 // ValidatePolicy will validate the policy type and payload values.
 func ValidatePolicy(pType PolicyType, payload string) error {
 	if !PolicyTypes[pType] {
@@ -259,6 +661,17 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 			}
 			issueTypeSeen[group.IssueType] = true
 		}
+		if err := validateApprovalRuleList(pa.ApprovalRuleList); err != nil {
+			return err
+		}
+		for riskLevel, ruleList := range pa.RiskLevelApprovalRuleMap {
+			if !riskLevel.IsValid() {
+				return errors.Errorf("invalid approval rule risk level %q", riskLevel)
+			}
+			if err := validateApprovalRuleList(ruleList); err != nil {
+				return errors.Wrapf(err, "invalid approval rules for risk level %q", riskLevel)
+			}
+		}
 	case PolicyTypeBackupPlan:
 		bp, err := UnmarshalBackupPlanPolicy(payload)
 		if err != nil {
@@ -283,6 +696,75 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 		if p.EnvironmentTier != EnvironmentTierValueProtected && p.EnvironmentTier != EnvironmentTierValueUnprotected {
 			return errors.Errorf("invalid environment tier value %q", p.EnvironmentTier)
 		}
+	case PolicyTypeSQLExport:
+		p, err := UnmarshalSQLExportPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if p.MaxRowCount <= 0 {
+			return errors.Errorf("invalid SQL export max row count %d", p.MaxRowCount)
+		}
+	case PolicyTypeQueryTimeout:
+		p, err := UnmarshalQueryTimeoutPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if p.MaxExecutionSeconds < 0 {
+			return errors.Errorf("invalid query max execution seconds %d", p.MaxExecutionSeconds)
+		}
+	case PolicyTypeMaintenanceWindow:
+		p, err := UnmarshalMaintenanceWindowPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if _, err := time.LoadLocation(p.TimeZone); err != nil {
+			return errors.Wrapf(err, "invalid maintenance window time zone %q", p.TimeZone)
+		}
+		for _, window := range p.WindowList {
+			if _, err := common.ParseCron(window.Cron); err != nil {
+				return err
+			}
+			if window.DurationMinutes <= 0 || window.DurationMinutes > MaxMaintenanceWindowDurationMinutes {
+				return errors.Errorf("invalid maintenance window duration %d minutes, should be between 1 and %d", window.DurationMinutes, MaxMaintenanceWindowDurationMinutes)
+			}
+		}
+		for _, date := range p.BlackoutDateList {
+			if _, err := time.Parse(maintenanceWindowDateFormat, date); err != nil {
+				return errors.Errorf("invalid blackout date %q, should be in the format of YYYY-MM-DD", date)
+			}
+		}
+	case PolicyTypeTaskConcurrency:
+		p, err := UnmarshalTaskConcurrencyPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if p.InstanceLimit < 0 {
+			return errors.Errorf("invalid task concurrency instance limit %d", p.InstanceLimit)
+		}
+		if p.EnvironmentLimit < 0 {
+			return errors.Errorf("invalid task concurrency environment limit %d", p.EnvironmentLimit)
+		}
+	case PolicyTypeTaskRunLogRetention:
+		p, err := UnmarshalTaskRunLogRetentionPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if p.RetentionDays < 0 {
+			return errors.Errorf("invalid task run log retention days %d", p.RetentionDays)
+		}
+	case PolicyTypeTaskRetry:
+		p, err := UnmarshalTaskRetryPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if err := p.Default.validate(); err != nil {
+			return err
+		}
+		for taskType, rule := range p.TaskTypeRuleMap {
+			if err := rule.validate(); err != nil {
+				return errors.Wrapf(err, "invalid retry rule for task type %q", taskType)
+			}
+		}
 	}
 	return nil
 }
@@ -309,6 +791,39 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 			EnvironmentTier: EnvironmentTierValueUnprotected,
 		}
 		return policy.String()
+	case PolicyTypeSQLExport:
+		policy := SQLExportPolicy{
+			MaxRowCount: DefaultSQLExportMaxRowCount,
+		}
+		return policy.String()
+	case PolicyTypeQueryTimeout:
+		policy := QueryTimeoutPolicy{
+			MaxExecutionSeconds: 0,
+		}
+		return policy.String()
+	case PolicyTypeMaintenanceWindow:
+		// Allow the tasks to run at any time by default.
+		policy := MaintenanceWindowPolicy{}
+		return policy.String()
+	case PolicyTypeTaskConcurrency:
+		// Don't limit the running tasks by default.
+		policy := TaskConcurrencyPolicy{}
+		return policy.String()
+	case PolicyTypeTaskRunLogRetention:
+		policy := TaskRunLogRetentionPolicy{
+			RetentionDays: DefaultTaskRunLogRetentionDays,
+		}
+		return policy.String()
+	case PolicyTypeTaskRetry:
+		// Don't retry the failed tasks by default.
+		policy := TaskRetryPolicy{
+			Default: TaskRetryRule{
+				MaxAttempts:           1,
+				InitialBackoffSeconds: DefaultTaskRetryInitialBackoffSeconds,
+				MaxBackoffSeconds:     DefaultTaskRetryMaxBackoffSeconds,
+			},
+		}
+		return policy.String()
 	}
 	return "", nil
 }
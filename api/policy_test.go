@@ -0,0 +1,251 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRetryPolicy(t *testing.T) {
+	payload, err := GetDefaultPolicy(PolicyTypeTaskRetry)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskRetry, payload))
+	policy, err := UnmarshalTaskRetryPolicy(payload)
+	require.NoError(t, err)
+	require.Equal(t, 1, policy.GetRule(TaskDatabaseSchemaUpdate).MaxAttempts)
+
+	policy = &TaskRetryPolicy{
+		Default: TaskRetryRule{MaxAttempts: 3, InitialBackoffSeconds: 10, MaxBackoffSeconds: 30},
+		TaskTypeRuleMap: map[TaskType]TaskRetryRule{
+			TaskDatabaseDataUpdate: {MaxAttempts: 5, InitialBackoffSeconds: 1, MaxBackoffSeconds: 60},
+		},
+	}
+	payload, err = policy.String()
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskRetry, payload))
+
+	rule := policy.GetRule(TaskDatabaseSchemaUpdate)
+	require.Equal(t, 10*time.Second, rule.GetBackoff(1))
+	require.Equal(t, 20*time.Second, rule.GetBackoff(2))
+	require.Equal(t, 30*time.Second, rule.GetBackoff(3))
+	rule = policy.GetRule(TaskDatabaseDataUpdate)
+	require.Equal(t, 5, rule.MaxAttempts)
+	require.Equal(t, 8*time.Second, rule.GetBackoff(4))
+
+	for _, invalid := range []string{
+		`{"default":{"maxAttempts":0,"initialBackoffSeconds":10,"maxBackoffSeconds":30}}`,
+		`{"default":{"maxAttempts":11,"initialBackoffSeconds":10,"maxBackoffSeconds":30}}`,
+		`{"default":{"maxAttempts":3,"initialBackoffSeconds":0,"maxBackoffSeconds":30}}`,
+		`{"default":{"maxAttempts":3,"initialBackoffSeconds":10,"maxBackoffSeconds":5}}`,
+		`{"default":{"maxAttempts":3,"initialBackoffSeconds":10,"maxBackoffSeconds":30},"taskTypeRuleMap":{"bb.task.database.data.update":{"maxAttempts":0}}}`,
+	} {
+		require.Error(t, ValidatePolicy(PolicyTypeTaskRetry, invalid), invalid)
+	}
+}
+
+func TestMaintenanceWindowPolicy(t *testing.T) {
+	payload, err := GetDefaultPolicy(PolicyTypeMaintenanceWindow)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeMaintenanceWindow, payload))
+	policy, err := UnmarshalMaintenanceWindowPolicy(payload)
+	require.NoError(t, err)
+	// 2022-10-28 is a Friday.
+	now := time.Date(2022, 10, 28, 10, 30, 0, 0, time.UTC)
+	next, err := policy.GetNextAllowedTime(now)
+	require.NoError(t, err)
+	require.True(t, next.Equal(now))
+
+	policy = &MaintenanceWindowPolicy{
+		TimeZone: "Asia/Shanghai",
+		WindowList: []MaintenanceWindow{
+			// 22:00-02:00 on weekdays.
+			{Cron: "0 22 * * 1-5", DurationMinutes: 240},
+			// 10:00-18:00 on Saturday.
+			{Cron: "0 10 * * 6", DurationMinutes: 480},
+		},
+		BlackoutDateList: []string{"2022-10-29"},
+	}
+	payload, err = policy.String()
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeMaintenanceWindow, payload))
+
+	location, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{
+			// Before the Friday window.
+			now:  time.Date(2022, 10, 28, 10, 30, 0, 0, location),
+			want: time.Date(2022, 10, 28, 22, 0, 0, 0, location),
+		},
+		{
+			// In the Thursday window lasting to Friday.
+			now:  time.Date(2022, 10, 28, 1, 30, 0, 0, location),
+			want: time.Date(2022, 10, 28, 1, 30, 0, 0, location),
+		},
+		{
+			// Saturday is blacked out, and there is no window on Sunday.
+			now:  time.Date(2022, 10, 29, 1, 0, 0, 0, location),
+			want: time.Date(2022, 10, 31, 22, 0, 0, 0, location),
+		},
+		{
+			// The window ends.
+			now:  time.Date(2022, 11, 1, 2, 0, 0, 0, location),
+			want: time.Date(2022, 11, 1, 22, 0, 0, 0, location),
+		},
+		{
+			// The time in the other time zone.
+			now:  time.Date(2022, 11, 5, 3, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 11, 5, 11, 0, 0, 0, location),
+		},
+	}
+	for _, test := range tests {
+		next, err := policy.GetNextAllowedTime(test.now)
+		require.NoError(t, err)
+		require.True(t, test.want.Equal(next), "now %s, want %s, got %s", test.now, test.want, next)
+	}
+
+	for _, invalid := range []string{
+		`{"timeZone":"Mars/Olympus"}`,
+		`{"windowList":[{"cron":"0 22 * *","durationMinutes":60}]}`,
+		`{"windowList":[{"cron":"0 22 * * *","durationMinutes":0}]}`,
+		`{"windowList":[{"cron":"0 22 * * *","durationMinutes":20000}]}`,
+		`{"blackoutDateList":["2022/10/29"]}`,
+	} {
+		require.Error(t, ValidatePolicy(PolicyTypeMaintenanceWindow, invalid), invalid)
+	}
+}
+
+func TestPipelineApprovalRule(t *testing.T) {
+	policy := &PipelineApprovalPolicy{
+		Value: PipelineApprovalValueManualAlways,
+		ApprovalRuleList: []ApprovalRule{
+			{Group: ApprovalGroupValueProjectOwner, Count: 1},
+			{Group: ApprovalGroupValueWorkspaceDBA, Count: 1},
+		},
+	}
+	payload, err := policy.String()
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypePipelineApproval, payload))
+
+	for _, invalid := range []string{
+		`{"value":"MANUAL_APPROVAL_ALWAYS","approvalRuleList":[{"group":"DEVELOPER","count":1}]}`,
+		`{"value":"MANUAL_APPROVAL_ALWAYS","approvalRuleList":[{"group":"PROJECT_OWNER","count":0}]}`,
+		`{"value":"MANUAL_APPROVAL_ALWAYS","approvalRuleList":[{"group":"PROJECT_OWNER","count":11}]}`,
+		`{"value":"MANUAL_APPROVAL_ALWAYS","approvalRuleList":[{"group":"PROJECT_OWNER","count":1},{"group":"PROJECT_OWNER","count":2}]}`,
+	} {
+		require.Error(t, ValidatePolicy(PolicyTypePipelineApproval, invalid), invalid)
+	}
+
+	owner := []ApprovalGroupValue{ApprovalGroupValueWorkspaceOwner, ApprovalGroupValueWorkspaceOwnerOrDBA}
+	dba := []ApprovalGroupValue{ApprovalGroupValueWorkspaceDBA, ApprovalGroupValueWorkspaceOwnerOrDBA}
+	projectOwner := []ApprovalGroupValue{ApprovalGroupValueProjectOwner}
+	dbaAndProjectOwner := []ApprovalGroupValue{ApprovalGroupValueWorkspaceDBA, ApprovalGroupValueProjectOwner}
+	tests := []struct {
+		ruleList          []ApprovalRule
+		approverGroupList [][]ApprovalGroupValue
+		want              bool
+	}{
+		{
+			ruleList:          policy.ApprovalRuleList,
+			approverGroupList: [][]ApprovalGroupValue{projectOwner, dba},
+			want:              true,
+		},
+		{
+			ruleList:          policy.ApprovalRuleList,
+			approverGroupList: [][]ApprovalGroupValue{projectOwner, owner},
+			want:              false,
+		},
+		{
+			// An approver is counted for at most one rule.
+			ruleList:          policy.ApprovalRuleList,
+			approverGroupList: [][]ApprovalGroupValue{dbaAndProjectOwner},
+			want:              false,
+		},
+		{
+			// The approver in both groups fills the slot which the other approver can't fill.
+			ruleList:          policy.ApprovalRuleList,
+			approverGroupList: [][]ApprovalGroupValue{dbaAndProjectOwner, dba},
+			want:              true,
+		},
+		{
+			ruleList: []ApprovalRule{
+				{Group: ApprovalGroupValueWorkspaceOwnerOrDBA, Count: 2},
+			},
+			approverGroupList: [][]ApprovalGroupValue{owner, projectOwner, dba},
+			want:              true,
+		},
+		{
+			ruleList: []ApprovalRule{
+				{Group: ApprovalGroupValueWorkspaceOwnerOrDBA, Count: 2},
+			},
+			approverGroupList: [][]ApprovalGroupValue{owner, projectOwner},
+			want:              false,
+		},
+	}
+	for i, test := range tests {
+		require.Equal(t, test.want, IsApprovalRuleListSatisfied(test.ruleList, test.approverGroupList), i)
+	}
+}
+
+func TestPipelineApprovalRiskLevelRule(t *testing.T) {
+	dbaRuleList := []ApprovalRule{{Group: ApprovalGroupValueWorkspaceDBA, Count: 1}}
+	strictRuleList := []ApprovalRule{
+		{Group: ApprovalGroupValueProjectOwner, Count: 1},
+		{Group: ApprovalGroupValueWorkspaceDBA, Count: 2},
+	}
+	policy := &PipelineApprovalPolicy{
+		Value:            PipelineApprovalValueManualAlways,
+		ApprovalRuleList: dbaRuleList,
+		RiskLevelApprovalRuleMap: map[RiskLevel][]ApprovalRule{
+			RiskLevelHigh: strictRuleList,
+		},
+	}
+	payload, err := policy.String()
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypePipelineApproval, payload))
+	require.Equal(t, dbaRuleList, policy.GetApprovalRuleList(RiskLevelLow))
+	require.Equal(t, dbaRuleList, policy.GetApprovalRuleList(RiskLevelModerate))
+	require.Equal(t, strictRuleList, policy.GetApprovalRuleList(RiskLevelHigh))
+
+	// The risky tasks require the approvals even if the policy never requires manual approval.
+	policy.Value = PipelineApprovalValueManualNever
+	require.Empty(t, policy.GetApprovalRuleList(RiskLevelLow))
+	require.Equal(t, strictRuleList, policy.GetApprovalRuleList(RiskLevelHigh))
+
+	for _, invalid := range []string{
+		`{"value":"MANUAL_APPROVAL_ALWAYS","riskLevelApprovalRuleMap":{"CRITICAL":[{"group":"PROJECT_OWNER","count":1}]}}`,
+		`{"value":"MANUAL_APPROVAL_ALWAYS","riskLevelApprovalRuleMap":{"HIGH":[{"group":"PROJECT_OWNER","count":0}]}}`,
+	} {
+		require.Error(t, ValidatePolicy(PolicyTypePipelineApproval, invalid), invalid)
+	}
+}
+
+func TestTaskConcurrencyPolicy(t *testing.T) {
+	payload, err := GetDefaultPolicy(PolicyTypeTaskConcurrency)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskConcurrency, payload))
+	policy, err := UnmarshalTaskConcurrencyPolicy(payload)
+	require.NoError(t, err)
+	require.Equal(t, &TaskConcurrencyPolicy{}, policy)
+
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskConcurrency, `{"instanceLimit":2,"environmentLimit":10}`))
+	require.Error(t, ValidatePolicy(PolicyTypeTaskConcurrency, `{"instanceLimit":-1}`))
+	require.Error(t, ValidatePolicy(PolicyTypeTaskConcurrency, `{"environmentLimit":-1}`))
+}
+
+func TestTaskRunLogRetentionPolicy(t *testing.T) {
+	payload, err := GetDefaultPolicy(PolicyTypeTaskRunLogRetention)
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskRunLogRetention, payload))
+	policy, err := UnmarshalTaskRunLogRetentionPolicy(payload)
+	require.NoError(t, err)
+	require.Equal(t, &TaskRunLogRetentionPolicy{RetentionDays: DefaultTaskRunLogRetentionDays}, policy)
+
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskRunLogRetention, `{"retentionDays":0}`))
+	require.NoError(t, ValidatePolicy(PolicyTypeTaskRunLogRetention, `{"retentionDays":7}`))
+	require.Error(t, ValidatePolicy(PolicyTypeTaskRunLogRetention, `{"retentionDays":-1}`))
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// QueryHistoryStatus is the status of the SQL editor query.
+type QueryHistoryStatus string
+
+const (
+	// QueryHistorySuccess is the status for the query succeeded without any advice.
+	QueryHistorySuccess QueryHistoryStatus = "SUCCESS"
+	// QueryHistoryWarn is the status for the query with warning advices.
+	QueryHistoryWarn QueryHistoryStatus = "WARN"
+	// QueryHistoryError is the status for the failed query or the query with error advices.
+	QueryHistoryError QueryHistoryStatus = "ERROR"
+)
+
+// QueryHistory is the API message for a query executed in the SQL editor.
+type QueryHistory struct {
+	ID int `jsonapi:"primary,queryHistory"`
+
+	// Standard fields
+	CreatorID int        `jsonapi:"attr,creatorId"`
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	InstanceID   int    `jsonapi:"attr,instanceId"`
+	DatabaseName string `jsonapi:"attr,databaseName"`
+
+	// Domain specific fields
+	Statement  string             `jsonapi:"attr,statement"`
+	Status     QueryHistoryStatus `jsonapi:"attr,status"`
+	DurationNs int64              `jsonapi:"attr,durationNs"`
+	Error      string             `jsonapi:"attr,error"`
+}
+
+// QueryHistoryResponse is the API message for a page of query history.
+type QueryHistoryResponse struct {
+	QueryHistoryList []*QueryHistory `jsonapi:"relation,queryHistoryList"`
+	NextToken        string          `jsonapi:"attr,nextToken"`
+}
+
+// QueryHistoryCreate is the API message for creating a query history.
+type QueryHistoryCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	InstanceID   int
+	DatabaseName string
+
+	// Domain specific fields
+	Statement  string
+	Status     QueryHistoryStatus
+	DurationNs int64
+	Error      string
+}
+
+// QueryHistoryFind is the API message for finding query history.
+type QueryHistoryFind struct {
+	// Standard fields
+	CreatorID *int
+	// CreatedTsAfter and CreatedTsBefore are the inclusive time range of the query.
+	CreatedTsAfter  *int64
+	CreatedTsBefore *int64
+
+	// Related fields
+	InstanceID   *int
+	DatabaseName *string
+
+	// Domain specific fields
+	Status *QueryHistoryStatus
+	// MinDurationNs and MaxDurationNs are the inclusive range of the query duration.
+	MinDurationNs *int64
+	MaxDurationNs *int64
+	// Statement is the case-insensitive substring of the statement.
+	Statement *string
+
+	// Pagination fields
+	// If specified, only find query history whose ID is smaller than or equal to SinceID.
+	SinceID *int
+	Limit   *int
+}
+
+func (find *QueryHistoryFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+// RiskLevel is the risk level of the change made by a task.
+type RiskLevel string
+
+const (
+	// RiskLevelLow is the risk level of the additive changes, e.g. creating tables and adding columns.
+	RiskLevelLow RiskLevel = "LOW"
+	// RiskLevelModerate is the risk level of the changes which may lock or scan big tables, e.g. adding constraints and DML without WHERE clause.
+	RiskLevelModerate RiskLevel = "MODERATE"
+	// RiskLevelHigh is the risk level of the destructive changes, e.g. dropping or rewriting tables, DML affecting too many rows and changes touching sensitive columns.
+	RiskLevelHigh RiskLevel = "HIGH"
+)
+
+// RiskTaskCheckTypeList is the task check types whose results are used to compute the risk level of the task.
+var RiskTaskCheckTypeList = []TaskCheckType{
+	TaskCheckDatabaseStatementType,
+	TaskCheckDatabaseStatementAdvise,
+}
+
+func (r RiskLevel) level() int {
+	switch r {
+	case RiskLevelLow:
+		return 0
+	case RiskLevelModerate:
+		return 1
+	case RiskLevelHigh:
+		return 2
+	}
+	return -1
+}
+
+// IsValid checks if the risk level is valid.
+func (r RiskLevel) IsValid() bool {
+	return r.level() >= 0
+}
+
+// HigherRiskLevel returns the higher one of the two risk levels.
+func HigherRiskLevel(a, b RiskLevel) RiskLevel {
+	if b.level() > a.level() {
+		return b
+	}
+	return a
+}
+
+// GetTaskCheckResultRiskLevel returns the risk level indicated by a task check result.
+func GetTaskCheckResultRiskLevel(result TaskCheckResult) RiskLevel {
+	switch result.Namespace {
+	case BBNamespace:
+		switch common.Code(result.Code) {
+		case common.TaskRiskDestructiveDDL, common.TaskRiskSensitiveColumn:
+			return RiskLevelHigh
+		}
+	case AdvisorNamespace:
+		switch advisor.Code(result.Code) {
+		case advisor.CompatibilityDropDatabase,
+			advisor.CompatibilityRenameTable,
+			advisor.CompatibilityDropTable,
+			advisor.CompatibilityRenameColumn,
+			advisor.CompatibilityDropColumn,
+			advisor.CompatibilityAlterColumn,
+			advisor.StatementAffectedRowExceedsLimit:
+			return RiskLevelHigh
+		case advisor.CompatibilityAddPrimaryKey,
+			advisor.CompatibilityAddUniqueKey,
+			advisor.CompatibilityAddForeignKey,
+			advisor.CompatibilityAddCheck,
+			advisor.CompatibilityAlterCheck,
+			advisor.StatementNoWhere,
+			advisor.StatementMutationOnBigTable:
+			return RiskLevelModerate
+		}
+	}
+	return RiskLevelLow
+}
+
+// GetTaskCheckRunRiskLevel returns the highest risk level indicated by the results of a finished task check run.
+// The risk is unknown if the check run is not done, e.g. failed or canceled, so we consider it high.
+func GetTaskCheckRunRiskLevel(taskCheckRun *TaskCheckRun) (RiskLevel, error) {
+	if taskCheckRun.Status != TaskCheckRunDone {
+		return RiskLevelHigh, nil
+	}
+	riskLevel := RiskLevelLow
+	payload := &TaskCheckRunResultPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Result), payload); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal task check run result %q", taskCheckRun.Result)
+	}
+	for _, result := range payload.ResultList {
+		riskLevel = HigherRiskLevel(riskLevel, GetTaskCheckResultRiskLevel(result))
+	}
+	return riskLevel, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestGetTaskCheckRunRiskLevel(t *testing.T) {
+	tests := []struct {
+		status TaskCheckRunStatus
+		result string
+		want   RiskLevel
+	}{
+		{
+			status: TaskCheckRunDone,
+			result: `{"resultList":[{"namespace":"bb.core","code":0,"status":"SUCCESS","title":"OK"}]}`,
+			want:   RiskLevelLow,
+		},
+		{
+			status: TaskCheckRunDone,
+			result: `{"resultList":[{"namespace":"bb.advisor","code":202,"status":"WARN"},{"namespace":"bb.advisor","code":402,"status":"WARN"}]}`,
+			want:   RiskLevelModerate,
+		},
+		{
+			status: TaskCheckRunDone,
+			result: `{"resultList":[{"namespace":"bb.advisor","code":202,"status":"WARN"},{"namespace":"bb.advisor","code":209,"status":"WARN"}]}`,
+			want:   RiskLevelHigh,
+		},
+		{
+			status: TaskCheckRunDone,
+			result: `{"resultList":[{"namespace":"bb.core","code":502,"status":"WARN"}]}`,
+			want:   RiskLevelHigh,
+		},
+		{
+			// The failed check run has no result, so the risk is unknown.
+			status: TaskCheckRunFailed,
+			result: `{"detail":"failed"}`,
+			want:   RiskLevelHigh,
+		},
+		{
+			status: TaskCheckRunCanceled,
+			result: "",
+			want:   RiskLevelHigh,
+		},
+	}
+	for _, test := range tests {
+		got, err := GetTaskCheckRunRiskLevel(&TaskCheckRun{Status: test.status, Result: test.result})
+		require.NoError(t, err)
+		require.Equal(t, test.want, got, test.result)
+	}
+
+	require.Equal(t, RiskLevelHigh, GetTaskCheckResultRiskLevel(TaskCheckResult{Namespace: BBNamespace, Code: common.TaskRiskDestructiveDDL.Int()}))
+	require.Equal(t, RiskLevelHigh, GetTaskCheckResultRiskLevel(TaskCheckResult{Namespace: AdvisorNamespace, Code: advisor.CompatibilityDropTable.Int()}))
+	// The same code in the other namespace means differently.
+	require.Equal(t, RiskLevelLow, GetTaskCheckResultRiskLevel(TaskCheckResult{Namespace: BBNamespace, Code: advisor.CompatibilityDropTable.Int()}))
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+// SchemaLintReportStatus is the status of the schema lint report.
+type SchemaLintReportStatus string
+
+const (
+	// SchemaLintReportSuccess is the status for the schema without any violation.
+	SchemaLintReportSuccess SchemaLintReportStatus = "SUCCESS"
+	// SchemaLintReportWarn is the status for the schema with warnings only.
+	SchemaLintReportWarn SchemaLintReportStatus = "WARN"
+	// SchemaLintReportError is the status for the schema with errors.
+	SchemaLintReportError SchemaLintReportStatus = "ERROR"
+)
+
+// SchemaLintReportPayload is the payload for the schema lint report.
+type SchemaLintReportPayload struct {
+	// PolicyID is the SQL review policy used for linting.
+	PolicyID        int                         `json:"policyId,omitempty"`
+	AdviceList      []advisor.Advice            `json:"adviceList"`
+	SkippedRuleList []advisor.SQLReviewRuleType `json:"skippedRuleList"`
+}
+
+// SchemaLintReport is the API message for the result of linting the whole database schema.
+// There is at most one report for each database, and it's overwritten by the next lint.
+type SchemaLintReport struct {
+	ID int `jsonapi:"primary,schemaLintReport"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int       `jsonapi:"attr,databaseId"`
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Status       SchemaLintReportStatus `jsonapi:"attr,status"`
+	ErrorCount   int                    `jsonapi:"attr,errorCount"`
+	WarningCount int                    `jsonapi:"attr,warningCount"`
+	// Payload is the JSON string of SchemaLintReportPayload.
+	Payload string `jsonapi:"attr,payload"`
+}
+
+// SchemaLintReportUpsert is the API message for upserting a schema lint report.
+type SchemaLintReportUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Status       SchemaLintReportStatus
+	ErrorCount   int
+	WarningCount int
+	Payload      string
+}
+
+// SchemaLintReportDelete is the API message for deleting the schema lint report of a database.
+type SchemaLintReportDelete struct {
+	// Related fields
+	DatabaseID int
+}
+
+// SchemaLintReportFind is the API message for finding schema lint reports.
+type SchemaLintReportFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID    *int
+	EnvironmentID *int
+}
+
+func (find *SchemaLintReportFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SchemaLintSummary is the API message for the schema lint summary of an environment.
+type SchemaLintSummary struct {
+	EnvironmentID int `json:"environmentId"`
+	// DatabaseCount is the number of the databases in the environment.
+	DatabaseCount int `json:"databaseCount"`
+	// LintedDatabaseCount is the number of the databases having the schema lint report.
+	LintedDatabaseCount int `json:"lintedDatabaseCount"`
+	// FailedDatabaseList is the list of the databases failed to lint with the error message.
+	FailedDatabaseList []*SchemaLintFailure `json:"failedDatabaseList"`
+
+	SuccessDatabaseCount int `json:"successDatabaseCount"`
+	WarnDatabaseCount    int `json:"warnDatabaseCount"`
+	ErrorDatabaseCount   int `json:"errorDatabaseCount"`
+	ErrorCount           int `json:"errorCount"`
+	WarningCount         int `json:"warningCount"`
+	// RuleViolationCount is the number of the violations for each rule type.
+	RuleViolationCount map[string]int `json:"ruleViolationCount"`
+}
+
+// SchemaLintFailure is the database failed to lint.
+type SchemaLintFailure struct {
+	DatabaseID   int    `json:"databaseId"`
+	DatabaseName string `json:"databaseName"`
+	Error        string `json:"error"`
+}
@@ -28,27 +28,117 @@ type SQLSyncSchema struct {
 }
 
 // SQLExecute is the API message for execute SQL.
-// For now, we only support readonly / SELECT.
 type SQLExecute struct {
 	InstanceID int `jsonapi:"attr,instanceId"`
 	// For engines such as MySQL, databaseName can be empty.
 	DatabaseName string `jsonapi:"attr,databaseName"`
 	Statement    string `jsonapi:"attr,statement"`
-	// For now, Readonly must be true
+	// Readonly only allows the SELECT, EXPLAIN, SET and CREATE TEMPORARY TABLE statements, which are run sequentially in one session.
+	// Otherwise, the statement is run in the admin execution mode, which is only allowed for workspace owners and DBAs.
 	Readonly bool `jsonapi:"attr,readonly"`
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+
+	// Admin execution mode fields.
+	// Justification is the reason to run the statement in the admin execution mode, it's required.
+	Justification string `jsonapi:"attr,justification"`
+	// ConfirmedAffectedRowCount is the affected row count of the DML statements confirmed by the caller.
+	// If it's nil, the DML statements are run in a transaction and rolled back to get the affected row count for confirmation.
+	// Otherwise, the transaction is only committed if the affected row count is the same as the confirmed one.
+	ConfirmedAffectedRowCount *int64 `jsonapi:"attr,confirmedAffectedRowCount"`
+}
+
+// SQLExportFormat is the format of the exported query result.
+type SQLExportFormat string
+
+const (
+	// SQLExportFormatCSV is the CSV format.
+	SQLExportFormatCSV SQLExportFormat = "CSV"
+	// SQLExportFormatJSON is the JSON lines format, one JSON object for each row.
+	SQLExportFormatJSON SQLExportFormat = "JSON"
+	// SQLExportFormatSQL is the SQL INSERT statements format.
+	SQLExportFormatSQL SQLExportFormat = "SQL"
+	// SQLExportFormatXLSX is the Excel XLSX format.
+	SQLExportFormatXLSX SQLExportFormat = "XLSX"
+)
+
+// SQLExport is the API message for exporting the query result.
+// The query is re-run and the result is streamed as a file download.
+type SQLExport struct {
+	InstanceID int `jsonapi:"attr,instanceId"`
+	// For engines such as MySQL, databaseName can be empty.
+	DatabaseName string          `jsonapi:"attr,databaseName"`
+	Statement    string          `jsonapi:"attr,statement"`
+	Format       SQLExportFormat `jsonapi:"attr,format"`
+	// The maximum row count exported, it's capped by the SQL export policy of the environment.
+	// The limit of the policy is used if limit <= 0.
+	Limit int `jsonapi:"attr,limit"`
+}
+
+// SQLBatchQuery is the API message for running the readonly query against the databases of a project.
+type SQLBatchQuery struct {
+	ProjectID int `jsonapi:"attr,projectId"`
+	// Selector is the JSON serialization of the LabelSelector to filter the databases of the project.
+	// All databases of the project are queried if it's empty.
+	Selector string `jsonapi:"attr,selector"`
+	// Statement only allows a single SELECT / EXPLAIN statement.
+	Statement string `jsonapi:"attr,statement"`
+	// The maximum row count returned for each database.
+	// Not enforced if limit <= 0.
+	Limit int `jsonapi:"attr,limit"`
+}
+
+// SQLDatabaseResult is the API message for the query result of a database in the batch query.
+type SQLDatabaseResult struct {
+	DatabaseID   int    `json:"databaseId"`
+	DatabaseName string `json:"databaseName"`
+	InstanceName string `json:"instanceName"`
+	// A list of rows marshalled into a JSON.
+	Data       string `json:"data"`
+	Error      string `json:"error"`
+	DurationNs int64  `json:"durationNs"`
+	// AdviceList is the result of the SQL review policy of the database environment, and the query is not run if any advice is at the error level.
+	AdviceList []advisor.Advice `json:"adviceList"`
+}
+
+// SQLBatchResultSet is the API message for the batch query results.
+type SQLBatchResultSet struct {
+	// ResultList is the result of each matched database in the ascending order of database name.
+	ResultList []*SQLDatabaseResult `jsonapi:"attr,resultList"`
+	// MergedData is the rows of all succeeded databases marshalled into a JSON, with the database name as the first column.
+	// The databases whose columns are different from the first succeeded database are excluded.
+	MergedData string `jsonapi:"attr,mergedData"`
+}
+
+// SQLStatementResult is the API message for the result of a statement in the readonly SQL editor script.
+type SQLStatementResult struct {
+	Statement string `json:"statement"`
+	// A list of rows marshalled into a JSON.
+	Data       string           `json:"data"`
+	Error      string           `json:"error"`
+	AdviceList []advisor.Advice `json:"adviceList"`
+	DurationNs int64            `json:"durationNs"`
 }
 
 // SQLResultSet is the API message for SQL results.
 type SQLResultSet struct {
 	// A list of rows marshalled into a JSON.
+	// For the readonly script, it's the data of the last executed statement.
 	Data string `jsonapi:"attr,data"`
 	// SQL operation may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
 	Error string `jsonapi:"attr,error"`
 	// A list of SQL check advice.
+	// For the readonly script, it's the advice of all statements.
 	AdviceList []advisor.Advice `jsonapi:"attr,adviceList"`
+	// ResultList is the ordered result of each statement in the readonly script.
+	// The execution stops at the first failed statement, so the statements after it have no result.
+	// If any statement fails the SQL check, no statement is executed and the list only carries the advice.
+	ResultList []*SQLStatementResult `jsonapi:"attr,resultList"`
+	// AffectedRowCount is the affected row count of the DML statements in the admin execution mode.
+	AffectedRowCount int64 `jsonapi:"attr,affectedRowCount"`
+	// RequireConfirmation is true if the DML statements are rolled back and wait for the affected row count confirmation.
+	RequireConfirmation bool `jsonapi:"attr,requireConfirmation"`
 }
 
 // SQLService is the service for SQL.
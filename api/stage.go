@@ -24,7 +24,8 @@ type Stage struct {
 	TaskList      []*Task      `jsonapi:"relation,task"`
 
 	// Domain specific fields
-	Name string `jsonapi:"attr,name"`
+	Name    string            `jsonapi:"attr,name"`
+	Rollout DeploymentRollout `jsonapi:"attr,rollout"`
 }
 
 // StageCreate is the API message for creating a stage.
@@ -40,7 +41,8 @@ type StageCreate struct {
 	TaskIndexDAGList []TaskIndexDAG `jsonapi:"attr,taskDAGList"`
 
 	// Domain specific fields
-	Name string `jsonapi:"attr,name"`
+	Name    string            `jsonapi:"attr,name"`
+	Rollout DeploymentRollout `jsonapi:"attr,rollout"`
 }
 
 // StageFind is the API message for finding stages.
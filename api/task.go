@@ -33,6 +33,10 @@ const (
 	TaskGeneral TaskType = "bb.task.general"
 	// TaskDatabaseCreate is the task type for creating databases.
 	TaskDatabaseCreate TaskType = "bb.task.database.create"
+	// TaskDatabaseGrant is the task type for granting the temporary access of databases.
+	TaskDatabaseGrant TaskType = "bb.task.database.grant"
+	// TaskDataSourceRequest is the task type for creating the requested read-only data sources.
+	TaskDataSourceRequest TaskType = "bb.task.data-source.request"
 	// TaskDatabaseSchemaBaseline is the task type for database schema baseline.
 	TaskDatabaseSchemaBaseline TaskType = "bb.task.database.schema.baseline"
 	// TaskDatabaseSchemaUpdate is the task type for updating database schemas.
@@ -96,6 +100,22 @@ type TaskDatabaseCreatePayload struct {
 	SchemaVersion string `json:"schemaVersion,omitempty"`
 }
 
+// TaskDatabaseGrantPayload is the task payload for granting the temporary access of databases.
+type TaskDatabaseGrantPayload struct {
+	Access DatabaseGrantAccess `json:"access,omitempty"`
+	// ExpireTs is the time when the granted access is revoked.
+	ExpireTs int64 `json:"expireTs,omitempty"`
+}
+
+// TaskDataSourceRequestPayload is the task payload for creating the requested read-only data sources.
+// The data source is requested by the task creator.
+type TaskDataSourceRequestPayload struct {
+	HostOverride string `json:"hostOverride,omitempty"`
+	PortOverride string `json:"portOverride,omitempty"`
+	// ExpireTs is the time when the data source is deleted.
+	ExpireTs int64 `json:"expireTs,omitempty"`
+}
+
 // TaskDatabaseSchemaBaselinePayload is the task payload for database schema baseline.
 type TaskDatabaseSchemaBaselinePayload struct {
 	Statement     string `json:"statement,omitempty"`
@@ -174,11 +194,19 @@ type Task struct {
 	Type              TaskType   `jsonapi:"attr,type"`
 	Payload           string     `jsonapi:"attr,payload"`
 	EarliestAllowedTs int64      `jsonapi:"attr,earliestAllowedTs"`
+	// MaintenanceWindowOverride allows the task to run outside the maintenance window of the environment.
+	MaintenanceWindowOverride bool `jsonapi:"attr,maintenanceWindowOverride"`
 	// BlockedBy is an array of Task ID.
 	// We use string here to workaround jsonapi limitations. https://github.com/google/jsonapi/issues/209
 	BlockedBy []string `jsonapi:"attr,blockedBy"`
 	// Progress is loaded from the task scheduler in memory, NOT from the database
 	Progress Progress `jsonapi:"attr,progress"`
+	// MaintenanceWindowAllowedTs is the next time the task is allowed to run by the maintenance window policy, 0 if the task isn't held by the policy.
+	// It's loaded from the task scheduler in memory, NOT from the database.
+	MaintenanceWindowAllowedTs int64 `jsonapi:"attr,maintenanceWindowAllowedTs"`
+	// QueuePosition is the position of the task waiting in the queue for the task concurrency limits, starting from 1, 0 if the task isn't waiting.
+	// It's loaded from the task scheduler in memory, NOT from the database.
+	QueuePosition int `jsonapi:"attr,queuePosition"`
 }
 
 // Progress is a generalized struct which can track the progress of a task.
@@ -263,6 +291,8 @@ type TaskPatch struct {
 	Statement         *string `jsonapi:"attr,statement"`
 	Payload           *string
 	EarliestAllowedTs *int64 `jsonapi:"attr,earliestAllowedTs"`
+	// MaintenanceWindowOverride can only be set by the workspace owners and the project owners.
+	MaintenanceWindowOverride *bool `jsonapi:"attr,maintenanceWindowOverride"`
 }
 
 // TaskStatusPatch is the API message for patching a task status.
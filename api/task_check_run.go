@@ -208,7 +208,7 @@ type TaskCheckRunStatusPatch struct {
 
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type, _ common.ReleaseMode) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.ClickHouse || dbType == db.Snowflake {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -222,7 +222,7 @@ func IsSyntaxCheckSupported(dbType db.Type, _ common.ReleaseMode) bool {
 
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type, _ common.ReleaseMode) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.ClickHouse || dbType == db.Snowflake {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -27,6 +27,10 @@ type TaskRunResultPayload struct {
 	Detail      string `json:"detail,omitempty"`
 	MigrationID int64  `json:"migrationId,omitempty"`
 	Version     string `json:"version,omitempty"`
+	// RetryTs is the time the failed task run is scheduled to retry automatically, 0 means no retry.
+	RetryTs int64 `json:"retryTs,omitempty"`
+	// Progress is the last progress of the task run, e.g. the statement failed to execute.
+	Progress *Progress `json:"progress,omitempty"`
 }
 
 // TaskRun is the API message for a task run.
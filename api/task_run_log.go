@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// TaskRunLogLevel is the level of the task run log.
+type TaskRunLogLevel string
+
+const (
+	// TaskRunLogInfo is the level of the informational task run logs.
+	TaskRunLogInfo TaskRunLogLevel = "INFO"
+	// TaskRunLogWarn is the level of the warning task run logs.
+	TaskRunLogWarn TaskRunLogLevel = "WARN"
+	// TaskRunLogError is the level of the error task run logs.
+	TaskRunLogError TaskRunLogLevel = "ERROR"
+
+	// DefaultTaskRunLogLimit is the default and the max number of task run logs returned by a request.
+	DefaultTaskRunLogLimit = 1000
+)
+
+// TaskRunLog is the API message for a log line of a task run.
+type TaskRunLog struct {
+	ID int `jsonapi:"primary,taskRunLog"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	TaskRunID int `jsonapi:"attr,taskRunId"`
+
+	// Domain specific fields
+	Level   TaskRunLogLevel `jsonapi:"attr,level"`
+	Message string          `jsonapi:"attr,message"`
+}
+
+// TaskRunLogCreate is the API message for creating a task run log.
+type TaskRunLogCreate struct {
+	// Standard fields
+	// CreatedTs is the time the log is recorded, which may be earlier than the time it is created, because the logs are created in batches.
+	CreatedTs int64
+
+	// Related fields
+	TaskRunID int
+
+	// Domain specific fields
+	Level   TaskRunLogLevel
+	Message string
+}
+
+// TaskRunLogFind is the API message for finding task run logs.
+type TaskRunLogFind struct {
+	// Related fields
+	TaskRunID *int
+
+	// Domain specific fields
+	// AfterID finds the logs after the log with the ID, which is used to tail the logs of a running task run.
+	AfterID *int
+	// Limit is the max number of logs returned.
+	Limit *int
+}
+
+func (find *TaskRunLogFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// TaskRunLogDelete is the API message for deleting the expired task run logs.
+type TaskRunLogDelete struct {
+	// Related fields
+	// EnvironmentID deletes the logs of the task runs on the instances in the environment.
+	EnvironmentID int
+
+	// Domain specific fields
+	// CreatedTsBefore deletes the logs created before it.
+	CreatedTsBefore int64
+}
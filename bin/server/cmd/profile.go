@@ -40,6 +40,7 @@ func getBaseProfile() server.Profile {
 		BackupRegion:         flags.backupRegion,
 		BackupBucket:         flags.backupBucket,
 		BackupCredentialFile: flags.backupCredential,
+		MaxConcurrentTasks:   flags.maxConcurrentTasks,
 	}
 }
 
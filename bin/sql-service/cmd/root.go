@@ -18,12 +18,16 @@ import (
 
 	// Register pingcap parser driver.
 	_ "github.com/pingcap/tidb/types/parser_driver"
+	// Register clickhouse advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/clickhouse"
 	// Register fake advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/fake"
 	// Register mysql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/mysql"
 	// Register postgresql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/pg"
+	// Register snowflake advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/snowflake"
 
 	// Register postgres parser driver.
 	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
@@ -0,0 +1,143 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronSearchLimit is how far CronSchedule.Next searches for the next time.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is the schedule parsed from the standard 5-field cron expression "minute hour day-of-month month day-of-week".
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the day-of-month and day-of-week fields are "*".
+	// If both fields are restricted, the time matches either of them as the standard cron does.
+	domStar, dowStar bool
+}
+
+type cronBounds struct {
+	min, max int
+}
+
+var (
+	cronMinuteBounds = cronBounds{0, 59}
+	cronHourBounds   = cronBounds{0, 23}
+	cronDomBounds    = cronBounds{1, 31}
+	cronMonthBounds  = cronBounds{1, 12}
+	// Both 0 and 7 are Sunday.
+	cronDowBounds = cronBounds{0, 7}
+)
+
+// ParseCron parses the standard 5-field cron expression, in which each field supports "*", values, ranges "a-b", steps "*/n" and "a-b/n", and the lists of them.
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("invalid cron expression %q, expect 5 fields but got %d", spec, len(fields))
+	}
+	s := &CronSchedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	var err error
+	if s.minute, err = parseCronField(fields[0], cronMinuteBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid minute field in cron expression %q", spec)
+	}
+	if s.hour, err = parseCronField(fields[1], cronHourBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid hour field in cron expression %q", spec)
+	}
+	if s.dom, err = parseCronField(fields[2], cronDomBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid day-of-month field in cron expression %q", spec)
+	}
+	if s.month, err = parseCronField(fields[3], cronMonthBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid month field in cron expression %q", spec)
+	}
+	if s.dow, err = parseCronField(fields[4], cronDowBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid day-of-week field in cron expression %q", spec)
+	}
+	// Fold 7 into 0 for Sunday.
+	if s.dow&(1<<7) > 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseCronField(field string, bounds cronBounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeAndStep := strings.SplitN(part, "/", 2)
+		start, end := bounds.min, bounds.max
+		if rangeAndStep[0] != "*" {
+			startAndEnd := strings.SplitN(rangeAndStep[0], "-", 2)
+			var err error
+			if start, err = strconv.Atoi(startAndEnd[0]); err != nil {
+				return 0, errors.Errorf("invalid value %q", startAndEnd[0])
+			}
+			end = start
+			if len(startAndEnd) == 2 {
+				if end, err = strconv.Atoi(startAndEnd[1]); err != nil {
+					return 0, errors.Errorf("invalid value %q", startAndEnd[1])
+				}
+			} else if len(rangeAndStep) == 2 {
+				// "a/n" means from a to the max.
+				end = bounds.max
+			}
+		}
+		step := 1
+		if len(rangeAndStep) == 2 {
+			var err error
+			if step, err = strconv.Atoi(rangeAndStep[1]); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step %q", rangeAndStep[1])
+			}
+		}
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, errors.Errorf("invalid range %q, should be within %d-%d", part, bounds.min, bounds.max)
+		}
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time matching the schedule at or after t, truncated to the minute.
+// It returns false if there is no such time in the next 5 years, e.g. "0 0 30 2 *".
+func (s *CronSchedule) Next(t time.Time) (time.Time, bool) {
+	// Round up to the minute.
+	if t.Second() != 0 || t.Nanosecond() != 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+	}
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) > 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) > 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
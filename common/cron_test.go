@@ -0,0 +1,87 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, spec := range []string{
+		"* * * * *",
+		"0 22 * * 1-5",
+		"*/15 0-6/2 1,15 * 0,7",
+		"30 1 * 1-3,10-12 *",
+	} {
+		_, err := ParseCron(spec)
+		require.NoError(t, err, spec)
+	}
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCron(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2022-10-28 is a Friday.
+	from := time.Date(2022, 10, 28, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{
+			spec: "* * * * *",
+			want: time.Date(2022, 10, 28, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 22 * * 1-5",
+			want: time.Date(2022, 10, 28, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 2 * * 6,0",
+			want: time.Date(2022, 10, 29, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			// Sunday as 7.
+			spec: "0 2 * * 7",
+			want: time.Date(2022, 10, 30, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "*/20 11 1 * *",
+			want: time.Date(2022, 11, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			// Either the day of month or the day of week matches.
+			spec: "0 0 1 * 1",
+			want: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 29 2 *",
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCron(test.spec)
+		require.NoError(t, err)
+		got, ok := schedule.Next(from)
+		require.True(t, ok, test.spec)
+		require.Equal(t, test.want, got, test.spec)
+	}
+
+	schedule, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	_, ok := schedule.Next(from)
+	require.False(t, ok)
+}
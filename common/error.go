@@ -35,11 +35,16 @@ const (
 	MigrationFailed  Code = 206
 
 	// 301 task error.
-	TaskTimingNotAllowed Code = 301
+	TaskTimingNotAllowed        Code = 301
+	TaskRolloutValidationFailed Code = 302
 
 	// 401 task sql type error.
 	TaskTypeNotDML Code = 401
 	TaskTypeNotDDL Code = 402
+
+	// 501 task risk.
+	TaskRiskDestructiveDDL  Code = 501
+	TaskRiskSensitiveColumn Code = 502
 )
 
 // Int returns the int type of code.
@@ -67,6 +72,11 @@ func (e *Error) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the embedded error, so that errors.Is and errors.As can inspect it.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 // ErrorCode unwraps an application error and returns its code.
 // Non-application errors always return EINTERNAL.
 func ErrorCode(err error) Code {
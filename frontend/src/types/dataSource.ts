@@ -39,6 +39,8 @@ export type DataSource = {
   // hostOverride and portOverride are only used for read-only data sources for user's read-replica instances.
   hostOverride: string;
   portOverride: string;
+  // principalId is set for the read-only data sources requested by the principal, which are private to the principal.
+  principalId?: PrincipalId;
 
   // UI-only fields
   updateSsl?: boolean;
@@ -3,6 +3,8 @@
 package advisor
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"sync"
 
@@ -199,6 +201,9 @@ const (
 	// MySQLStatementDMLDryRun is an advisor type for MySQL DML dry run.
 	MySQLStatementDMLDryRun Type = "bb.plugin.advisor.mysql.statement.dml-dry-run"
 
+	// MySQLCustomRule is an advisor type for MySQL user-defined rules.
+	MySQLCustomRule Type = "bb.plugin.advisor.mysql.custom"
+
 	// PostgreSQL Advisor.
 
 	// PostgreSQLSyntax is an advisor type for PostgreSQL syntax.
@@ -260,6 +265,43 @@ const (
 
 	// PostgreSQLIndexNoDuplicateColumn is an advisor type for Postgresql no duplicate columns in index.
 	PostgreSQLIndexNoDuplicateColumn Type = "bb.plugin.advisor.postgresql.index.no-duplicate-column"
+
+	// PostgreSQLStatementAffectedRowLimit is an advisor type for PostgreSQL UPDATE/DELETE affected row limit.
+	PostgreSQLStatementAffectedRowLimit Type = "bb.plugin.advisor.postgresql.statement.affected-row-limit"
+
+	// PostgreSQLStatementDMLDryRun is an advisor type for PostgreSQL DML dry run.
+	PostgreSQLStatementDMLDryRun Type = "bb.plugin.advisor.postgresql.statement.dml-dry-run"
+
+	// PostgreSQLCustomRule is an advisor type for PostgreSQL user-defined rules.
+	PostgreSQLCustomRule Type = "bb.plugin.advisor.postgresql.custom"
+
+	// ClickHouse Advisor.
+
+	// ClickHouseSyntax is an advisor type for ClickHouse syntax.
+	ClickHouseSyntax Type = "bb.plugin.advisor.clickhouse.syntax"
+
+	// ClickHouseTableRequireEngine is an advisor type for ClickHouse table engine and ORDER BY requirement.
+	ClickHouseTableRequireEngine Type = "bb.plugin.advisor.clickhouse.table.require-engine"
+
+	// ClickHouseDisallowMutation is an advisor type for ClickHouse disallowing ALTER TABLE ... UPDATE/DELETE mutations on big tables.
+	ClickHouseDisallowMutation Type = "bb.plugin.advisor.clickhouse.statement.disallow-mutation"
+
+	// ClickHouseOnClusterConsistency is an advisor type for ClickHouse ON CLUSTER consistency.
+	ClickHouseOnClusterConsistency Type = "bb.plugin.advisor.clickhouse.statement.on-cluster-consistency"
+
+	// Snowflake Advisor.
+
+	// SnowflakeSyntax is an advisor type for Snowflake syntax.
+	SnowflakeSyntax Type = "bb.plugin.advisor.snowflake.syntax"
+
+	// SnowflakeNamingTableConvention is an advisor type for Snowflake table naming convention.
+	SnowflakeNamingTableConvention Type = "bb.plugin.advisor.snowflake.naming.table"
+
+	// SnowflakeNoSelectAll is an advisor type for Snowflake no select all.
+	SnowflakeNoSelectAll Type = "bb.plugin.advisor.snowflake.select.no-select-all"
+
+	// SnowflakeWhereRequirement is an advisor type for Snowflake WHERE clause requirement.
+	SnowflakeWhereRequirement Type = "bb.plugin.advisor.snowflake.where.require"
 )
 
 // Advice is the result of an advisor.
@@ -304,6 +346,11 @@ type Context struct {
 	// SQL review rule special fields.
 	Rule    *SQLReviewRule
 	Catalog *catalog.Finder
+
+	// Driver and Context are used by the advisors which need to access the database, e.g. running EXPLAIN.
+	// Driver is a read-only connection and it may be nil, in which case such advisors should skip the check.
+	Driver  *sql.DB
+	Context context.Context
 }
 
 // Advisor is the interface for advisor.
@@ -364,7 +411,7 @@ func Check(dbType db.Type, advType Type, ctx Context, statement string) (adviceL
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.MySQL, db.TiDB, db.Postgres:
+	case db.MySQL, db.TiDB, db.Postgres, db.ClickHouse, db.Snowflake:
 		return true
 	}
 	return false
@@ -373,7 +420,7 @@ func IsSyntaxCheckSupported(dbType db.Type) bool {
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.MySQL, db.TiDB, db.Postgres:
+	case db.MySQL, db.TiDB, db.Postgres, db.ClickHouse, db.Snowflake:
 		return true
 	}
 	return false
@@ -74,6 +74,7 @@ func newTableState(t *Table) *TableState {
 		engine:    newStringPointer(t.Engine),
 		collation: newStringPointer(t.Collation),
 		comment:   newStringPointer(t.Comment),
+		rowCount:  t.RowCount,
 		columnSet: make(columnStateMap),
 		indexSet:  make(indexStateMap),
 	}
@@ -303,12 +304,19 @@ type TableState struct {
 	// collation isn't supported for Postgres, ClickHouse, Snowflake, SQLite.
 	collation *string
 	// comment isn't supported for SQLite.
-	comment   *string
+	comment *string
+	// rowCount is the approximate row count from the last sync, it's zero for the tables created during walk-through.
+	rowCount  int64
 	columnSet columnStateMap
 	// indexSet isn't supported for ClickHouse, Snowflake.
 	indexSet indexStateMap
 }
 
+// RowCount returns the approximate row count of the table.
+func (table *TableState) RowCount() int64 {
+	return table.rowCount
+}
+
 // CountIndex return the index total number.
 func (table *TableState) CountIndex() int {
 	return len(table.indexSet)
@@ -321,6 +329,7 @@ func (table *TableState) copy() *TableState {
 		engine:    copyStringPointer(table.engine),
 		collation: copyStringPointer(table.collation),
 		comment:   copyStringPointer(table.comment),
+		rowCount:  table.rowCount,
 		columnSet: table.columnSet.copy(),
 		indexSet:  table.indexSet.copy(),
 	}
@@ -0,0 +1,107 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*StatementDisallowMutationAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseDisallowMutation, &StatementDisallowMutationAdvisor{})
+}
+
+// StatementDisallowMutationAdvisor is the advisor checking for the ALTER TABLE ... UPDATE/DELETE mutations on big tables.
+// The mutations rewrite the whole data parts, which is expensive for big tables.
+type StatementDisallowMutationAdvisor struct {
+}
+
+// Check checks for the ALTER TABLE ... UPDATE/DELETE mutations.
+func (*StatementDisallowMutationAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		tableName, ok := getMutationTableName(stmt)
+		if !ok {
+			continue
+		}
+		// The number is the max row count of the table allowed to mutate, 0 means disallowing all the mutations.
+		if payload.Number > 0 {
+			if ctx.Catalog == nil {
+				continue
+			}
+			table := ctx.Catalog.Origin.FindTable(&catalog.TableFind{TableName: tableName})
+			// Skip the table we don't know the size of, e.g. not synced yet.
+			if table == nil || table.RowCount() <= int64(payload.Number) {
+				continue
+			}
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.StatementMutationOnBigTable,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("\"%s\" mutates table `%s` with about %d rows, which exceeds the limit %d", stmt.text, tableName, table.RowCount(), payload.Number),
+				Line:    stmt.line,
+			})
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.StatementMutationOnBigTable,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("\"%s\" uses the ALTER TABLE ... UPDATE/DELETE mutation on table `%s`", stmt.text, tableName),
+			Line:    stmt.line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// getMutationTableName returns the table name if the statement is ALTER TABLE ... UPDATE/DELETE.
+// The ALTER TABLE statement may contain a comma-separated list of the actions.
+func getMutationTableName(stmt *tokenizedStatement) (string, bool) {
+	if !stmt.hasPrefix("ALTER", "TABLE") {
+		return "", false
+	}
+	tableName, pos := stmt.readTableName(2)
+	if pos+2 < len(stmt.tokenList) && stmt.tokenList[pos].IsWord("ON") && stmt.tokenList[pos+1].IsWord("CLUSTER") {
+		pos += 3
+	}
+	for i := pos; i < len(stmt.tokenList); i++ {
+		if stmt.depthList[i] != 0 {
+			continue
+		}
+		if i != pos && !stmt.tokenList[i-1].IsPunctuation(",") {
+			continue
+		}
+		if stmt.tokenList[i].IsWord("UPDATE") || stmt.tokenList[i].IsWord("DELETE") {
+			return tableName, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,64 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestStatementDisallowMutation(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "ALTER TABLE tech_book UPDATE name = 'x' WHERE id = 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementMutationOnBigTable,
+					Title:   "statement.clickhouse.disallow-mutation",
+					Content: "\"ALTER TABLE tech_book UPDATE name = 'x' WHERE id = 1\" mutates table `tech_book` with about 1000000 rows, which exceeds the limit 1000",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE db.tech_book ON CLUSTER c ADD COLUMN a String, DELETE WHERE id = 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementMutationOnBigTable,
+					Title:   "statement.clickhouse.disallow-mutation",
+					Content: "\"ALTER TABLE db.tech_book ON CLUSTER c ADD COLUMN a String, DELETE WHERE id = 1\" mutates table `tech_book` with about 1000000 rows, which exceeds the limit 1000",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE t UPDATE name = 'x' WHERE id = 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD COLUMN a String",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &StatementDisallowMutationAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementDisallowMutation,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: `{"number": 1000}`,
+	}, advisor.MockClickHouseDatabase)
+}
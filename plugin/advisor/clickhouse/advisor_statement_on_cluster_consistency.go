@@ -0,0 +1,86 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*StatementOnClusterConsistencyAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseOnClusterConsistency, &StatementOnClusterConsistencyAdvisor{})
+}
+
+// StatementOnClusterConsistencyAdvisor is the advisor checking for the ON CLUSTER consistency.
+// Mixing the DDL statements with and without ON CLUSTER leaves the replicas of the cluster in different schemas.
+type StatementOnClusterConsistencyAdvisor struct {
+}
+
+// Check checks for the ON CLUSTER consistency.
+func (*StatementOnClusterConsistencyAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first DDL statement with ON CLUSTER decides the cluster for the whole change.
+	cluster := ""
+	for _, stmt := range stmtList {
+		if stmt.isDDL() {
+			if cluster = stmt.getCluster(); cluster != "" {
+				break
+			}
+		}
+	}
+
+	var adviceList []advisor.Advice
+	if cluster != "" {
+		for _, stmt := range stmtList {
+			if !stmt.isDDL() {
+				continue
+			}
+			// The temporary table only exists in the current session.
+			if stmt.hasPrefix("CREATE", "TEMPORARY") {
+				continue
+			}
+			switch stmtCluster := stmt.getCluster(); stmtCluster {
+			case cluster:
+			case "":
+				adviceList = append(adviceList, advisor.Advice{
+					Status:  level,
+					Code:    advisor.StatementOnClusterInconsistent,
+					Title:   string(ctx.Rule.Type),
+					Content: fmt.Sprintf("\"%s\" requires ON CLUSTER %s as the other statements", stmt.text, cluster),
+					Line:    stmt.line,
+				})
+			default:
+				adviceList = append(adviceList, advisor.Advice{
+					Status:  level,
+					Code:    advisor.StatementOnClusterInconsistent,
+					Title:   string(ctx.Rule.Type),
+					Content: fmt.Sprintf("\"%s\" uses ON CLUSTER %s, but the other statements use ON CLUSTER %s", stmt.text, stmtCluster, cluster),
+					Line:    stmt.line,
+				})
+			}
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
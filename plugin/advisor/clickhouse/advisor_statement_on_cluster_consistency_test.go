@@ -0,0 +1,59 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestStatementOnClusterConsistency(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t ON CLUSTER c (id UInt64) ENGINE = MergeTree ORDER BY id;\nALTER TABLE t ON CLUSTER c ADD COLUMN a String;\nINSERT INTO t VALUES (1, 'a');",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t (id UInt64) ENGINE = MergeTree ORDER BY id;\nDROP TABLE t2",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t ON CLUSTER c (id UInt64) ENGINE = MergeTree ORDER BY id;\nALTER TABLE t ADD COLUMN a String;\nDROP TABLE t2 ON CLUSTER c2;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementOnClusterInconsistent,
+					Title:   "statement.clickhouse.on-cluster-consistency",
+					Content: "\"ALTER TABLE t ADD COLUMN a String;\" requires ON CLUSTER c as the other statements",
+					Line:    2,
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementOnClusterInconsistent,
+					Title:   "statement.clickhouse.on-cluster-consistency",
+					Content: "\"DROP TABLE t2 ON CLUSTER c2;\" uses ON CLUSTER c2, but the other statements use ON CLUSTER c",
+					Line:    3,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &StatementOnClusterConsistencyAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementOnClusterConsistency,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockClickHouseDatabase)
+}
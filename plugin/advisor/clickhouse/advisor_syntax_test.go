@@ -0,0 +1,53 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestSyntax(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t(id UInt64, `name` String) ENGINE = MergeTree ORDER BY id;\nOPTIMIZE TABLE t FINAL;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "Syntax OK",
+					Content: "OK",
+				},
+			},
+		},
+		{
+			Statement: "SELECT 1;\nSELECT count(( FROM t;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementSyntaxError,
+					Title:   advisor.SyntaxErrorTitle,
+					Content: "syntax error at line 1: 2 unclosed parenthesis",
+					Line:    2,
+				},
+			},
+		},
+		{
+			Statement: "SELCT 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementSyntaxError,
+					Title:   advisor.SyntaxErrorTitle,
+					Content: "unexpected \"SELCT\" at the beginning of statement \"SELCT 1\"",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &SyntaxAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementRequireWhere,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockClickHouseDatabase)
+}
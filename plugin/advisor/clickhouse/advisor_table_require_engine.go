@@ -0,0 +1,118 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequireEngineAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseTableRequireEngine, &TableRequireEngineAdvisor{})
+}
+
+// TableRequireEngineAdvisor is the advisor checking for the table engine and the ORDER BY key of the MergeTree family engines.
+type TableRequireEngineAdvisor struct {
+}
+
+// Check checks for the table engine and the ORDER BY key.
+func (*TableRequireEngineAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		tableName, engine, ok := getCreateTableEngine(stmt)
+		if !ok {
+			continue
+		}
+		if engine == "" {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.TableNoEngine,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("Table `%s` requires the ENGINE clause instead of relying on the default table engine", tableName),
+				Line:    stmt.line,
+			})
+			continue
+		}
+		if strings.HasSuffix(strings.ToLower(engine), "mergetree") && !hasMergeTreeSortingKey(stmt) {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.MergeTreeNoOrderByKey,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("Table `%s` with %s engine requires the ORDER BY clause", tableName, engine),
+				Line:    stmt.line,
+			})
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// getCreateTableEngine returns the table name and the engine name of the CREATE TABLE statement.
+// The ok is false if the statement isn't CREATE TABLE, or it copies the structure and engine from another table, e.g. CREATE TABLE t2 AS t1.
+func getCreateTableEngine(stmt *tokenizedStatement) (string, string, bool) {
+	pos := 0
+	if !stmt.hasPrefix("CREATE") {
+		return "", "", false
+	}
+	pos++
+	pos = stmt.skipWords(pos, "OR", "REPLACE")
+	pos = stmt.skipWords(pos, "TEMPORARY")
+	if pos >= len(stmt.tokenList) || !stmt.tokenList[pos].IsWord("TABLE") {
+		return "", "", false
+	}
+	pos++
+	pos = stmt.skipWords(pos, "IF", "NOT", "EXISTS")
+	tableName, pos := stmt.readTableName(pos)
+	if pos+2 < len(stmt.tokenList) && stmt.tokenList[pos].IsWord("ON") && stmt.tokenList[pos+1].IsWord("CLUSTER") {
+		pos += 3
+	}
+	if pos+1 < len(stmt.tokenList) && stmt.tokenList[pos].IsWord("AS") {
+		next := stmt.tokenList[pos+1]
+		if !next.IsWord("SELECT") && !next.IsWord("WITH") && !next.IsPunctuation("(") {
+			return "", "", false
+		}
+	}
+
+	enginePos := stmt.findTopLevelWord(pos, "ENGINE")
+	if enginePos < 0 {
+		return tableName, "", true
+	}
+	enginePos++
+	if enginePos < len(stmt.tokenList) && stmt.tokenList[enginePos].IsPunctuation("=") {
+		enginePos++
+	}
+	if enginePos >= len(stmt.tokenList) || stmt.tokenList[enginePos].Type != parser.TokenWord {
+		return tableName, "", true
+	}
+	return tableName, stmt.tokenList[enginePos].Text, true
+}
+
+// hasMergeTreeSortingKey returns true if the MergeTree family table declares the ORDER BY key.
+// We don't support the deprecated syntax declaring the key in the engine parameters, e.g. MergeTree(date, (id), 8192).
+func hasMergeTreeSortingKey(stmt *tokenizedStatement) bool {
+	return stmt.findTopLevelWordPair(0, "ORDER", "BY") >= 0
+}
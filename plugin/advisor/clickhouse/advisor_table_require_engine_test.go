@@ -0,0 +1,75 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestTableRequireEngine(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t ON CLUSTER c (id UInt64) ENGINE = ReplicatedMergeTree('/clickhouse/t', '{replica}') ORDER BY id",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t2 AS t",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE IF NOT EXISTS db.t (id UInt64)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableNoEngine,
+					Title:   "engine.clickhouse.require-engine",
+					Content: "Table `t` requires the ENGINE clause instead of relying on the default table engine",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t (id UInt64, name String) ENGINE = MergeTree PARTITION BY tuple()",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.MergeTreeNoOrderByKey,
+					Title:   "engine.clickhouse.require-engine",
+					Content: "Table `t` with MergeTree engine requires the ORDER BY clause",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t (id UInt64) ENGINE = Memory",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &TableRequireEngineAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleClickHouseEngine,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockClickHouseDatabase)
+}
@@ -24,13 +24,21 @@ const (
 	CompatibilityAlterColumn   Code = 111
 
 	// 201 ~ 299 statement error code.
-	StatementSyntaxError         Code = 201
-	StatementNoWhere             Code = 202
-	StatementSelectAll           Code = 203
-	StatementLeadingWildcardLike Code = 204
-	StatementCreateTableAs       Code = 205
-	StatementDisallowCommit      Code = 206
-	StatementRedundantAlterTable Code = 207
+	StatementSyntaxError             Code = 201
+	StatementNoWhere                 Code = 202
+	StatementSelectAll               Code = 203
+	StatementLeadingWildcardLike     Code = 204
+	StatementCreateTableAs           Code = 205
+	StatementDisallowCommit          Code = 206
+	StatementRedundantAlterTable     Code = 207
+	StatementDMLDryRunFailed         Code = 208
+	StatementAffectedRowExceedsLimit Code = 209
+	StatementExplainQueryFailed      Code = 210
+	StatementMutationOnBigTable      Code = 211
+	StatementOnClusterInconsistent   Code = 212
+	StatementFullTableScan           Code = 213
+	StatementUseFilesort             Code = 214
+	StatementUseTemporaryTable       Code = 215
 
 	// 301 ～ 399 naming error code
 	// 301 table naming advisor error code.
@@ -70,8 +78,10 @@ const (
 	OnUpdateCurrentTimeColumnCountExceedsLimit Code = 419
 	NoDefault                                  Code = 420
 
-	// 501 engine error code.
-	NotInnoDBEngine Code = 501
+	// 501 ~ 599 engine error code.
+	NotInnoDBEngine       Code = 501
+	TableNoEngine         Code = 502
+	MergeTreeNoOrderByKey Code = 503
 
 	// 601 ~ 699 table rule advisor error code.
 	TableNoPK                         Code = 601
@@ -121,6 +131,9 @@ const (
 
 	// 1301 ~ 1399 comment error code.
 	CommentTooLong Code = 1301
+
+	// 1401 ~ 1499 custom rule error code.
+	CustomRuleViolation Code = 1401
 )
 
 // Int returns the int type of code.
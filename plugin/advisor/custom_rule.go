@@ -0,0 +1,245 @@
+package advisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CustomRuleStatementType is the statement kind used by the custom rule predicate.
+type CustomRuleStatementType string
+
+const (
+	// CustomRuleStatementCreateTable is the statement type for CREATE TABLE.
+	CustomRuleStatementCreateTable CustomRuleStatementType = "CREATE_TABLE"
+	// CustomRuleStatementAlterTable is the statement type for ALTER TABLE.
+	CustomRuleStatementAlterTable CustomRuleStatementType = "ALTER_TABLE"
+	// CustomRuleStatementDropTable is the statement type for DROP TABLE.
+	CustomRuleStatementDropTable CustomRuleStatementType = "DROP_TABLE"
+	// CustomRuleStatementRenameTable is the statement type for RENAME TABLE.
+	CustomRuleStatementRenameTable CustomRuleStatementType = "RENAME_TABLE"
+	// CustomRuleStatementTruncateTable is the statement type for TRUNCATE TABLE.
+	CustomRuleStatementTruncateTable CustomRuleStatementType = "TRUNCATE_TABLE"
+	// CustomRuleStatementCreateIndex is the statement type for CREATE INDEX.
+	CustomRuleStatementCreateIndex CustomRuleStatementType = "CREATE_INDEX"
+	// CustomRuleStatementDropIndex is the statement type for DROP INDEX.
+	CustomRuleStatementDropIndex CustomRuleStatementType = "DROP_INDEX"
+	// CustomRuleStatementCreateDatabase is the statement type for CREATE DATABASE.
+	CustomRuleStatementCreateDatabase CustomRuleStatementType = "CREATE_DATABASE"
+	// CustomRuleStatementDropDatabase is the statement type for DROP DATABASE.
+	CustomRuleStatementDropDatabase CustomRuleStatementType = "DROP_DATABASE"
+	// CustomRuleStatementInsert is the statement type for INSERT.
+	CustomRuleStatementInsert CustomRuleStatementType = "INSERT"
+	// CustomRuleStatementUpdate is the statement type for UPDATE.
+	CustomRuleStatementUpdate CustomRuleStatementType = "UPDATE"
+	// CustomRuleStatementDelete is the statement type for DELETE.
+	CustomRuleStatementDelete CustomRuleStatementType = "DELETE"
+	// CustomRuleStatementSelect is the statement type for SELECT.
+	CustomRuleStatementSelect CustomRuleStatementType = "SELECT"
+	// CustomRuleStatementOther is the statement type for the statements not listed above.
+	CustomRuleStatementOther CustomRuleStatementType = "OTHER"
+)
+
+var customRuleStatementTypes = map[CustomRuleStatementType]bool{
+	CustomRuleStatementCreateTable:    true,
+	CustomRuleStatementAlterTable:     true,
+	CustomRuleStatementDropTable:      true,
+	CustomRuleStatementRenameTable:    true,
+	CustomRuleStatementTruncateTable:  true,
+	CustomRuleStatementCreateIndex:    true,
+	CustomRuleStatementDropIndex:      true,
+	CustomRuleStatementCreateDatabase: true,
+	CustomRuleStatementDropDatabase:   true,
+	CustomRuleStatementInsert:         true,
+	CustomRuleStatementUpdate:         true,
+	CustomRuleStatementDelete:         true,
+	CustomRuleStatementSelect:         true,
+	CustomRuleStatementOther:          true,
+}
+
+// CustomRulePayload is the payload for the user-defined SQL review rule.
+// A statement violates the rule if it matches all the non-empty predicates.
+// The column predicates (name pattern and type list) must be satisfied by the same column.
+type CustomRulePayload struct {
+	// Title is shown as the advice title. The rule type is used if it's empty.
+	Title string `json:"title"`
+	// Message is the advice content. It supports the {{table}} template token.
+	Message string `json:"message"`
+
+	StatementTypeList []CustomRuleStatementType `json:"statementTypeList"`
+	TableNamePattern  string                    `json:"tableNamePattern"`
+	ColumnNamePattern string                    `json:"columnNamePattern"`
+	ColumnTypeList    []string                  `json:"columnTypeList"`
+}
+
+// CustomRule is the compiled custom rule payload.
+type CustomRule struct {
+	title             string
+	message           string
+	statementTypeSet  map[CustomRuleStatementType]bool
+	tableNameFormat   *regexp.Regexp
+	columnNameFormat  *regexp.Regexp
+	columnTypeList    []string
+	hasColumnCriteria bool
+}
+
+// CustomRuleColumn is the column defined or changed by a statement.
+type CustomRuleColumn struct {
+	Name string
+	// EquivalentType reports whether the column type is equivalent to the given type name.
+	EquivalentType func(tp string) bool
+}
+
+// CustomRuleStatement is the engine-neutral description of a statement for the custom rule evaluation.
+// Each engine advisor converts its AST into this form and the evaluation is shared.
+type CustomRuleStatement struct {
+	Type       CustomRuleStatementType
+	TableList  []string
+	ColumnList []*CustomRuleColumn
+	Text       string
+	Line       int
+}
+
+// UnmarshalCustomRulePayload will unmarshal payload to CustomRulePayload and compile it as CustomRule.
+func UnmarshalCustomRulePayload(payload string) (*CustomRule, error) {
+	var cr CustomRulePayload
+	if err := json.Unmarshal([]byte(payload), &cr); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal custom rule payload %q", payload)
+	}
+	if cr.Message == "" {
+		return nil, errors.Errorf("invalid custom rule payload, message cannot be empty")
+	}
+	if len(cr.StatementTypeList) == 0 && cr.TableNamePattern == "" && cr.ColumnNamePattern == "" && len(cr.ColumnTypeList) == 0 {
+		return nil, errors.Errorf("invalid custom rule payload, at least one predicate is required")
+	}
+
+	rule := &CustomRule{
+		title:            cr.Title,
+		message:          cr.Message,
+		statementTypeSet: make(map[CustomRuleStatementType]bool),
+		columnTypeList:   cr.ColumnTypeList,
+	}
+	for _, tp := range cr.StatementTypeList {
+		if !customRuleStatementTypes[tp] {
+			return nil, errors.Errorf("invalid custom rule statement type %q", tp)
+		}
+		rule.statementTypeSet[tp] = true
+	}
+	if cr.TableNamePattern != "" {
+		format, err := regexp.Compile(cr.TableNamePattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile regular expression \"%s\"", cr.TableNamePattern)
+		}
+		rule.tableNameFormat = format
+	}
+	if cr.ColumnNamePattern != "" {
+		format, err := regexp.Compile(cr.ColumnNamePattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile regular expression \"%s\"", cr.ColumnNamePattern)
+		}
+		rule.columnNameFormat = format
+	}
+	rule.hasColumnCriteria = rule.columnNameFormat != nil || len(rule.columnTypeList) > 0
+	return rule, nil
+}
+
+// CheckCustomRule evaluates the custom rule against the statement list and returns the advices.
+func CheckCustomRule(rule *SQLReviewRule, statementList []*CustomRuleStatement) ([]Advice, error) {
+	level, err := NewStatusBySQLReviewRuleLevel(rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	customRule, err := UnmarshalCustomRulePayload(rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	title := customRule.title
+	if title == "" {
+		title = string(rule.Type)
+	}
+
+	var adviceList []Advice
+	for _, stmt := range statementList {
+		table, ok := customRule.match(stmt)
+		if !ok {
+			continue
+		}
+		adviceList = append(adviceList, Advice{
+			Status:  level,
+			Code:    CustomRuleViolation,
+			Title:   title,
+			Content: fmt.Sprintf("%s, related statement: \"%s\"", strings.ReplaceAll(customRule.message, TableNameTemplateToken, table), stmt.Text),
+			Line:    stmt.Line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, Advice{
+			Status:  Success,
+			Code:    Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// match reports whether the statement violates the rule, and returns the matched table name.
+func (r *CustomRule) match(stmt *CustomRuleStatement) (string, bool) {
+	if len(r.statementTypeSet) > 0 && !r.statementTypeSet[stmt.Type] {
+		return "", false
+	}
+
+	table := ""
+	if len(stmt.TableList) > 0 {
+		table = stmt.TableList[0]
+	}
+	if r.tableNameFormat != nil {
+		matched := false
+		for _, name := range stmt.TableList {
+			if r.tableNameFormat.MatchString(name) {
+				table = name
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", false
+		}
+	}
+
+	if r.hasColumnCriteria {
+		matched := false
+		for _, column := range stmt.ColumnList {
+			if r.matchColumn(column) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", false
+		}
+	}
+	return table, true
+}
+
+func (r *CustomRule) matchColumn(column *CustomRuleColumn) bool {
+	if r.columnNameFormat != nil && !r.columnNameFormat.MatchString(column.Name) {
+		return false
+	}
+	if len(r.columnTypeList) == 0 {
+		return true
+	}
+	if column.EquivalentType == nil {
+		return false
+	}
+	for _, tp := range r.columnTypeList {
+		if column.EquivalentType(tp) {
+			return true
+		}
+	}
+	return false
+}
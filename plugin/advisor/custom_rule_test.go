@@ -0,0 +1,53 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCustomRule(t *testing.T) {
+	tests := []struct {
+		payload string
+		wantErr bool
+	}{
+		{
+			payload: `{"message":"no delete","statementTypeList":["DELETE"]}`,
+			wantErr: false,
+		},
+		{
+			payload: `{"message":"no json","columnTypeList":["json"],"tableNamePattern":"^t_"}`,
+			wantErr: false,
+		},
+		{
+			payload: `{"statementTypeList":["DELETE"]}`,
+			wantErr: true,
+		},
+		{
+			payload: `{"message":"empty predicate"}`,
+			wantErr: true,
+		},
+		{
+			payload: `{"message":"bad type","statementTypeList":["MERGE"]}`,
+			wantErr: true,
+		},
+		{
+			payload: `{"message":"bad pattern","tableNamePattern":"("}`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		rule := &SQLReviewRule{
+			Type:    SchemaRuleCustom,
+			Level:   SchemaRuleLevelError,
+			Payload: test.payload,
+		}
+		err := rule.Validate()
+		if test.wantErr {
+			require.Error(t, err, test.payload)
+		} else {
+			require.NoError(t, err, test.payload)
+		}
+	}
+}
@@ -18,6 +18,10 @@ const (
 	Postgres Type = "POSTGRES"
 	// TiDB is the database type for TiDB.
 	TiDB Type = "TIDB"
+	// ClickHouse is the database type for CLICKHOUSE.
+	ClickHouse Type = "CLICKHOUSE"
+	// Snowflake is the database type for SNOWFLAKE.
+	Snowflake Type = "SNOWFLAKE"
 )
 
 // ConvertToAdvisorDBType will convert db type into advisor db type.
@@ -29,6 +33,10 @@ func ConvertToAdvisorDBType(dbType string) (Type, error) {
 		return Postgres, nil
 	case string(TiDB):
 		return TiDB, nil
+	case string(ClickHouse):
+		return ClickHouse, nil
+	case string(Snowflake):
+		return Snowflake, nil
 	}
 
 	return "", errors.Errorf("unsupported db type %s for advisor", dbType)
@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*CustomRuleAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLCustomRule, &CustomRuleAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLCustomRule, &CustomRuleAdvisor{})
+}
+
+// CustomRuleAdvisor is the advisor checking for the user-defined rules.
+type CustomRuleAdvisor struct {
+}
+
+// Check checks for the user-defined rules.
+func (*CustomRuleAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	var statementList []*advisor.CustomRuleStatement
+	for _, stmt := range stmtList {
+		statementList = append(statementList, convertToCustomRuleStatement(stmt))
+	}
+	return advisor.CheckCustomRule(ctx.Rule, statementList)
+}
+
+func convertToCustomRuleStatement(in ast.StmtNode) *advisor.CustomRuleStatement {
+	stmt := &advisor.CustomRuleStatement{
+		Type: advisor.CustomRuleStatementOther,
+		Text: in.Text(),
+		Line: in.OriginTextPosition(),
+	}
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		stmt.Type = advisor.CustomRuleStatementCreateTable
+		stmt.TableList = []string{node.Table.Name.O}
+		stmt.ColumnList = convertToCustomRuleColumnList(node.Cols)
+	case *ast.AlterTableStmt:
+		stmt.Type = advisor.CustomRuleStatementAlterTable
+		stmt.TableList = []string{node.Table.Name.O}
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddColumns, ast.AlterTableChangeColumn, ast.AlterTableModifyColumn:
+				stmt.ColumnList = append(stmt.ColumnList, convertToCustomRuleColumnList(spec.NewColumns)...)
+			}
+		}
+	case *ast.DropTableStmt:
+		stmt.Type = advisor.CustomRuleStatementDropTable
+		for _, table := range node.Tables {
+			stmt.TableList = append(stmt.TableList, table.Name.O)
+		}
+	case *ast.RenameTableStmt:
+		stmt.Type = advisor.CustomRuleStatementRenameTable
+		for _, table := range node.TableToTables {
+			stmt.TableList = append(stmt.TableList, table.OldTable.Name.O)
+		}
+	case *ast.TruncateTableStmt:
+		stmt.Type = advisor.CustomRuleStatementTruncateTable
+		stmt.TableList = []string{node.Table.Name.O}
+	case *ast.CreateIndexStmt:
+		stmt.Type = advisor.CustomRuleStatementCreateIndex
+		stmt.TableList = []string{node.Table.Name.O}
+	case *ast.DropIndexStmt:
+		stmt.Type = advisor.CustomRuleStatementDropIndex
+		stmt.TableList = []string{node.Table.Name.O}
+	case *ast.CreateDatabaseStmt:
+		stmt.Type = advisor.CustomRuleStatementCreateDatabase
+	case *ast.DropDatabaseStmt:
+		stmt.Type = advisor.CustomRuleStatementDropDatabase
+	case *ast.InsertStmt:
+		stmt.Type = advisor.CustomRuleStatementInsert
+		stmt.TableList = extractTableNameList(node.Table)
+	case *ast.UpdateStmt:
+		stmt.Type = advisor.CustomRuleStatementUpdate
+		stmt.TableList = extractTableNameList(node.TableRefs)
+	case *ast.DeleteStmt:
+		stmt.Type = advisor.CustomRuleStatementDelete
+		stmt.TableList = extractTableNameList(node.TableRefs)
+	case *ast.SelectStmt:
+		stmt.Type = advisor.CustomRuleStatementSelect
+		if node.From != nil {
+			stmt.TableList = extractTableNameList(node.From)
+		}
+	}
+	return stmt
+}
+
+func convertToCustomRuleColumnList(columnList []*ast.ColumnDef) []*advisor.CustomRuleColumn {
+	var result []*advisor.CustomRuleColumn
+	for _, column := range columnList {
+		// The type string is something like "varchar(255)", we match either the whole string or the type name.
+		columnType := strings.ToLower(column.Tp.CompactStr())
+		typeName := columnType
+		if idx := strings.Index(typeName, "("); idx >= 0 {
+			typeName = typeName[:idx]
+		}
+		result = append(result, &advisor.CustomRuleColumn{
+			Name: column.Name.Name.O,
+			EquivalentType: func(tp string) bool {
+				tp = strings.ToLower(tp)
+				return tp == columnType || tp == typeName
+			},
+		})
+	}
+	return result
+}
+
+func extractTableNameList(refs *ast.TableRefsClause) []string {
+	if refs == nil {
+		return nil
+	}
+	var result []string
+	for _, table := range extractTableSourceList(refs.TableRefs) {
+		if name, ok := table.Source.(*ast.TableName); ok {
+			result = append(result, name.Name.O)
+		}
+	}
+	return result
+}
+
+func extractTableSourceList(node ast.ResultSetNode) []*ast.TableSource {
+	switch n := node.(type) {
+	case *ast.Join:
+		var result []*ast.TableSource
+		if n.Left != nil {
+			result = append(result, extractTableSourceList(n.Left)...)
+		}
+		if n.Right != nil {
+			result = append(result, extractTableSourceList(n.Right)...)
+		}
+		return result
+	case *ast.TableSource:
+		return []*ast.TableSource{n}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestCustomRule(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "DELETE FROM tech_book WHERE id = 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.CustomRuleViolation,
+					Title:   "custom",
+					Content: "Use soft delete for tech_book, related statement: \"DELETE FROM tech_book WHERE id = 1\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "UPDATE tech_book SET name = 'x' WHERE id = 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t(id int); DELETE FROM t WHERE id = 1",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(advisor.CustomRulePayload{
+		Message:           "Use soft delete for {{table}}",
+		StatementTypeList: []advisor.CustomRuleStatementType{advisor.CustomRuleStatementDelete},
+		TableNamePattern:  "^tech_",
+	})
+	require.NoError(t, err)
+	advisor.RunSQLReviewRuleTests(t, tests, &CustomRuleAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleCustom,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: string(payload),
+	}, advisor.MockMySQLDatabase)
+}
+
+func TestCustomRuleColumnType(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t(id int, b blob)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.CustomRuleViolation,
+					Title:   "No blob",
+					Content: "Table t has BLOB column, related statement: \"CREATE TABLE t(id int, b blob)\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book MODIFY COLUMN name varchar(10)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(advisor.CustomRulePayload{
+		Title:          "No blob",
+		Message:        "Table {{table}} has BLOB column",
+		ColumnTypeList: []string{"BLOB"},
+	})
+	require.NoError(t, err)
+	advisor.RunSQLReviewRuleTests(t, tests, &CustomRuleAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleCustom,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}, advisor.MockMySQLDatabase)
+}
@@ -3,6 +3,10 @@ package mysql
 // Framework code is generated by the generator.
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+
 	"github.com/pingcap/tidb/parser/ast"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
@@ -24,6 +28,7 @@ type StatementAffectedRowLimitAdvisor struct {
 }
 
 // Check checks for UPDATE/DELETE affected row limit.
+// The affected rows are estimated by EXPLAIN, so it will not execute the statement.
 func (*StatementAffectedRowLimitAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
 	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
 	if errAdvice != nil {
@@ -42,12 +47,17 @@ func (*StatementAffectedRowLimitAdvisor) Check(ctx advisor.Context, statement st
 		level:  level,
 		title:  string(ctx.Rule.Type),
 		maxRow: payload.Number,
+		driver: ctx.Driver,
+		ctx:    ctx.Context,
 	}
 
-	for _, stmt := range stmtList {
-		checker.text = stmt.Text()
-		checker.line = stmt.OriginTextPosition()
-		(stmt).Accept(checker)
+	// Skip the check if there is no connection to the database, e.g. the SQL review API.
+	if checker.driver != nil {
+		for _, stmt := range stmtList {
+			checker.text = stmt.Text()
+			checker.line = stmt.OriginTextPosition()
+			(stmt).Accept(checker)
+		}
 	}
 
 	if len(checker.adviceList) == 0 {
@@ -68,15 +78,37 @@ type statementAffectedRowLimitChecker struct {
 	text       string
 	line       int
 	maxRow     int
+	driver     *sql.DB
+	ctx        context.Context
 }
 
 // Enter implements the ast.Visitor interface.
-func (*statementAffectedRowLimitChecker) Enter(in ast.Node) (ast.Node, bool) {
-	// TODO: implement it
-	// switch node := in.(type) {
-	// }
-
-	return in, false
+func (checker *statementAffectedRowLimitChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch in.(type) {
+	case *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return in, false
+	}
+	rows, err := getAffectedRows(checker.ctx, checker.driver, checker.text)
+	if err != nil {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.StatementExplainQueryFailed,
+			Title:   checker.title,
+			Content: fmt.Sprintf("\"%s\" failed to explain: %v", checker.text, err),
+			Line:    checker.line,
+		})
+	} else if checker.maxRow > 0 && rows > int64(checker.maxRow) {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.StatementAffectedRowExceedsLimit,
+			Title:   checker.title,
+			Content: fmt.Sprintf("\"%s\" affected %d rows (estimated). The count exceeds %d.", checker.text, rows, checker.maxRow),
+			Line:    checker.line,
+		})
+	}
+	// The subqueries are explained with the statement.
+	return in, true
 }
 
 // Leave implements the ast.Visitor interface.
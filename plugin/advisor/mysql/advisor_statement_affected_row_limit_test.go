@@ -3,6 +3,8 @@ package mysql
 // Framework code is generated by the generator.
 
 import (
+	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -35,3 +37,123 @@ func TestStatementAffectedRowLimit(t *testing.T) {
 		Payload: string(payload),
 	}, advisor.MockMySQLDatabase)
 }
+
+func TestStatementAffectedRowLimitWithDriver(t *testing.T) {
+	mysqlColumnList := []string{"id", "select_type", "table", "partitions", "type", "possible_keys", "key", "key_len", "ref", "rows", "filtered", "Extra"}
+	tidbColumnList := []string{"id", "estRows", "task", "access object", "operator info"}
+	conn := newExplainDB(map[string]explainPlan{
+		"UPDATE tech_book SET name = 'x'": {
+			columnList: mysqlColumnList,
+			rowList: [][]driver.Value{
+				{"1", "UPDATE", "tech_book", nil, "ALL", nil, nil, nil, nil, "1200", "100.00", nil},
+			},
+		},
+		"DELETE FROM tech_book WHERE id = 1": {
+			columnList: mysqlColumnList,
+			rowList: [][]driver.Value{
+				{"1", "DELETE", "tech_book", nil, "range", "PRIMARY", "PRIMARY", "4", "const", "1", "100.00", "Using where"},
+			},
+		},
+		"DELETE FROM book": {
+			columnList: tidbColumnList,
+			rowList: [][]driver.Value{
+				{"Delete_4", "N/A", "root", "", "N/A"},
+				{"└─TableReader_7", "10000.00", "root", "", "data:TableFullScan_6"},
+				{"  └─TableFullScan_6", "10000.00", "cop[tikv]", "table:book", "keep order:false, stats:pseudo"},
+			},
+		},
+		"INSERT INTO tech_book SELECT * FROM book": {
+			columnList: mysqlColumnList,
+			rowList: [][]driver.Value{
+				{"1", "INSERT", "tech_book", nil, "ALL", nil, nil, nil, nil, nil, nil, nil},
+				{"1", "SIMPLE", "book", nil, "ALL", nil, nil, nil, nil, "5000", "100.00", nil},
+			},
+		},
+	})
+	defer conn.Close()
+	payload, err := json.Marshal(advisor.NumberTypeRulePayload{
+		Number: 5,
+	})
+	require.NoError(t, err)
+	rule := &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementAffectedRowLimit,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}
+
+	tests := []struct {
+		statement string
+		want      []advisor.Advice
+	}{
+		{
+			statement: "UPDATE tech_book SET name = 'x'",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementAffectedRowExceedsLimit,
+					Title:   string(advisor.SchemaRuleStatementAffectedRowLimit),
+					Content: "\"UPDATE tech_book SET name = 'x'\" affected 1200 rows (estimated). The count exceeds 5.",
+					Line:    1,
+				},
+			},
+		},
+		{
+			statement: "DELETE FROM tech_book WHERE id = 1",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			// TiDB reports N/A for the root Delete operator.
+			statement: "DELETE FROM book",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementAffectedRowExceedsLimit,
+					Title:   string(advisor.SchemaRuleStatementAffectedRowLimit),
+					Content: "\"DELETE FROM book\" affected 10000 rows (estimated). The count exceeds 5.",
+					Line:    1,
+				},
+			},
+		},
+		{
+			// The limit only applies to UPDATE and DELETE.
+			statement: "INSERT INTO tech_book SELECT * FROM book",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			statement: "DELETE FROM unknown_table",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementExplainQueryFailed,
+					Title:   string(advisor.SchemaRuleStatementAffectedRowLimit),
+					Content: "\"DELETE FROM unknown_table\" failed to explain: syntax error in \"EXPLAIN DELETE FROM unknown_table\"",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		adviceList, err := (&StatementAffectedRowLimitAdvisor{}).Check(advisor.Context{
+			Rule:    rule,
+			Driver:  conn,
+			Context: context.Background(),
+		}, test.statement)
+		require.NoError(t, err)
+		require.Equal(t, test.want, adviceList, test.statement)
+	}
+}
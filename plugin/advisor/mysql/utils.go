@@ -2,11 +2,16 @@
 package mysql
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/format"
+	"github.com/pkg/errors"
 )
 
 type columnSet map[string]bool
@@ -51,3 +56,56 @@ func restoreNode(node ast.Node, flag format.RestoreFlags) (string, error) {
 	}
 	return buffer.String(), nil
 }
+
+// getAffectedRows returns the affected rows of UPDATE and DELETE estimated by EXPLAIN.
+// MySQL reports the rows of the target table in the first row of the plan, and TiDB reports the estimated rows
+// of the first reader under the root Update or Delete operator, whose estRows is N/A.
+func getAffectedRows(ctx context.Context, conn *sql.DB, statement string) (int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("EXPLAIN %s", statement))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columnNames, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	rowsIndex := -1
+	for i, name := range columnNames {
+		if strings.EqualFold(name, "rows") || strings.EqualFold(name, "estRows") {
+			rowsIndex = i
+			break
+		}
+	}
+	if rowsIndex < 0 {
+		return 0, errors.Errorf("no rows column in the plan columns %v", columnNames)
+	}
+
+	values := make([]sql.NullString, len(columnNames))
+	dest := make([]interface{}, len(columnNames))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		if !values[rowsIndex].Valid {
+			continue
+		}
+		// TiDB reports the estimated rows in float.
+		count, err := strconv.ParseFloat(values[rowsIndex].String, 64)
+		if err != nil {
+			continue
+		}
+		return int64(count), nil
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.Errorf("no estimated rows in the plan of %q", statement)
+}
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// explainPrefix is the prefix of the EXPLAIN statement run by getAffectedRows.
+const explainPrefix = "EXPLAIN "
+
+// explainPlan is the plan returned by EXPLAIN, one value per column in each row.
+type explainPlan struct {
+	columnList []string
+	rowList    [][]driver.Value
+}
+
+// newExplainDB returns a database connection answering EXPLAIN with the plans keyed by the statement.
+// EXPLAIN fails for the statements without a plan, like the database does for the invalid statements.
+func newExplainDB(planMap map[string]explainPlan) *sql.DB {
+	return sql.OpenDB(&explainConnector{planMap: planMap})
+}
+
+type explainConnector struct {
+	planMap map[string]explainPlan
+}
+
+func (c *explainConnector) Connect(context.Context) (driver.Conn, error) {
+	return &explainConn{planMap: c.planMap}, nil
+}
+
+func (c *explainConnector) Driver() driver.Driver {
+	return explainDriver{connector: c}
+}
+
+type explainDriver struct {
+	connector *explainConnector
+}
+
+func (d explainDriver) Open(string) (driver.Conn, error) {
+	return d.connector.Connect(context.Background())
+}
+
+type explainConn struct {
+	planMap map[string]explainPlan
+}
+
+func (*explainConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (*explainConn) Close() error {
+	return nil
+}
+
+func (*explainConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transaction is not supported")
+}
+
+func (c *explainConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, explainPrefix) {
+		return nil, errors.Errorf("unexpected query %q", query)
+	}
+	plan, ok := c.planMap[strings.TrimPrefix(query, explainPrefix)]
+	if !ok {
+		return nil, errors.Errorf("syntax error in %q", query)
+	}
+	return &explainRows{plan: plan}, nil
+}
+
+type explainRows struct {
+	plan explainPlan
+	next int
+}
+
+func (r *explainRows) Columns() []string {
+	return r.plan.columnList
+}
+
+func (*explainRows) Close() error {
+	return nil
+}
+
+func (r *explainRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.plan.rowList) {
+		return io.EOF
+	}
+	copy(dest, r.plan.rowList[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,121 @@
+package pg
+
+import (
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*CustomRuleAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLCustomRule, &CustomRuleAdvisor{})
+}
+
+// CustomRuleAdvisor is the advisor checking for the user-defined rules.
+type CustomRuleAdvisor struct {
+}
+
+// Check checks for the user-defined rules.
+func (*CustomRuleAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	var statementList []*advisor.CustomRuleStatement
+	for _, stmt := range stmts {
+		statementList = append(statementList, convertToCustomRuleStatement(stmt))
+	}
+	return advisor.CheckCustomRule(ctx.Rule, statementList)
+}
+
+func convertToCustomRuleStatement(in ast.Node) *advisor.CustomRuleStatement {
+	stmt := &advisor.CustomRuleStatement{
+		Type: advisor.CustomRuleStatementOther,
+		Text: in.Text(),
+		Line: in.LastLine(),
+	}
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		stmt.Type = advisor.CustomRuleStatementCreateTable
+		stmt.TableList = []string{node.Name.Name}
+		stmt.ColumnList = convertToCustomRuleColumnList(node.ColumnList)
+	case *ast.AlterTableStmt:
+		stmt.Type = advisor.CustomRuleStatementAlterTable
+		stmt.TableList = []string{node.Table.Name}
+		for _, item := range node.AlterItemList {
+			switch itemNode := item.(type) {
+			case *ast.AddColumnListStmt:
+				stmt.ColumnList = append(stmt.ColumnList, convertToCustomRuleColumnList(itemNode.ColumnList)...)
+			case *ast.AlterColumnTypeStmt:
+				stmt.ColumnList = append(stmt.ColumnList, &advisor.CustomRuleColumn{
+					Name:           itemNode.ColumnName,
+					EquivalentType: itemNode.Type.EquivalentType,
+				})
+			case *ast.RenameTableStmt:
+				stmt.Type = advisor.CustomRuleStatementRenameTable
+			}
+		}
+	case *ast.DropTableStmt:
+		stmt.Type = advisor.CustomRuleStatementDropTable
+		for _, table := range node.TableList {
+			stmt.TableList = append(stmt.TableList, table.Name)
+		}
+	case *ast.CreateIndexStmt:
+		stmt.Type = advisor.CustomRuleStatementCreateIndex
+		stmt.TableList = []string{node.Index.Table.Name}
+	case *ast.DropIndexStmt:
+		stmt.Type = advisor.CustomRuleStatementDropIndex
+		for _, index := range node.IndexList {
+			if index.Table != nil {
+				stmt.TableList = append(stmt.TableList, index.Table.Name)
+			}
+		}
+	case *ast.CreateDatabaseStmt:
+		stmt.Type = advisor.CustomRuleStatementCreateDatabase
+	case *ast.DropDatabaseStmt:
+		stmt.Type = advisor.CustomRuleStatementDropDatabase
+	case *ast.InsertStmt:
+		stmt.Type = advisor.CustomRuleStatementInsert
+		stmt.TableList = []string{node.Table.Name}
+	case *ast.UpdateStmt:
+		stmt.Type = advisor.CustomRuleStatementUpdate
+		stmt.TableList = []string{node.Table.Name}
+	case *ast.DeleteStmt:
+		stmt.Type = advisor.CustomRuleStatementDelete
+		stmt.TableList = []string{node.Table.Name}
+	case *ast.SelectStmt:
+		stmt.Type = advisor.CustomRuleStatementSelect
+		stmt.TableList = getSelectTableNameList(node)
+	}
+	return stmt
+}
+
+// getSelectTableNameList returns the tables in the FROM clause of the SELECT, including both sides of the set operations.
+func getSelectTableNameList(node *ast.SelectStmt) []string {
+	if node == nil {
+		return nil
+	}
+	if node.SetOperation != ast.SetOperationTypeNone {
+		return append(getSelectTableNameList(node.LQuery), getSelectTableNameList(node.RQuery)...)
+	}
+	var tableList []string
+	for _, table := range node.TableList {
+		tableList = append(tableList, table.Name)
+	}
+	return tableList
+}
+
+func convertToCustomRuleColumnList(columnList []*ast.ColumnDef) []*advisor.CustomRuleColumn {
+	var result []*advisor.CustomRuleColumn
+	for _, column := range columnList {
+		result = append(result, &advisor.CustomRuleColumn{
+			Name:           column.ColumnName,
+			EquivalentType: column.Type.EquivalentType,
+		})
+	}
+	return result
+}
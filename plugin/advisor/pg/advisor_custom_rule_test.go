@@ -0,0 +1,128 @@
+package pg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestCustomRule(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE t_user(id INT, payload JSON)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.CustomRuleViolation,
+					Title:   "Disallow JSON column",
+					Content: "Table t_user uses JSON column, related statement: \"CREATE TABLE t_user(id INT, payload JSON)\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE t_user ADD COLUMN extra json",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.CustomRuleViolation,
+					Title:   "Disallow JSON column",
+					Content: "Table t_user uses JSON column, related statement: \"ALTER TABLE t_user ADD COLUMN extra json\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE t_user(id INT, payload TEXT)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE book(id INT, payload JSON)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(advisor.CustomRulePayload{
+		Title:             "Disallow JSON column",
+		Message:           "Table {{table}} uses JSON column",
+		StatementTypeList: []advisor.CustomRuleStatementType{advisor.CustomRuleStatementCreateTable, advisor.CustomRuleStatementAlterTable},
+		TableNamePattern:  "^t_",
+		ColumnTypeList:    []string{"json"},
+	})
+	require.NoError(t, err)
+	advisor.RunSQLReviewRuleTests(t, tests, &CustomRuleAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleCustom,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}, advisor.MockPostgreSQLDatabase)
+}
+
+func TestCustomRuleSelect(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "SELECT * FROM book JOIN t_user ON book.id = t_user.id",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.CustomRuleViolation,
+					Title:   "Disallow querying user tables",
+					Content: "Query on t_user, related statement: \"SELECT * FROM book JOIN t_user ON book.id = t_user.id\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "SELECT id FROM book UNION SELECT id FROM t_user",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.CustomRuleViolation,
+					Title:   "Disallow querying user tables",
+					Content: "Query on t_user, related statement: \"SELECT id FROM book UNION SELECT id FROM t_user\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "SELECT * FROM book",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(advisor.CustomRulePayload{
+		Title:             "Disallow querying user tables",
+		Message:           "Query on {{table}}",
+		StatementTypeList: []advisor.CustomRuleStatementType{advisor.CustomRuleStatementSelect},
+		TableNamePattern:  "^t_",
+	})
+	require.NoError(t, err)
+	advisor.RunSQLReviewRuleTests(t, tests, &CustomRuleAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleCustom,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}, advisor.MockPostgreSQLDatabase)
+}
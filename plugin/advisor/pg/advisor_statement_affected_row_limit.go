@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*StatementAffectedRowLimitAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLStatementAffectedRowLimit, &StatementAffectedRowLimitAdvisor{})
+}
+
+// StatementAffectedRowLimitAdvisor is the advisor checking for UPDATE/DELETE affected row limit.
+type StatementAffectedRowLimitAdvisor struct {
+}
+
+// Check checks for UPDATE/DELETE affected row limit.
+// The affected rows are estimated by EXPLAIN without ANALYZE, so it will not execute the statement.
+func (*StatementAffectedRowLimitAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	// Skip the check if there is no connection to the database, e.g. the SQL review API.
+	if ctx.Driver != nil {
+		for _, stmt := range stmts {
+			switch stmt.(type) {
+			case *ast.UpdateStmt, *ast.DeleteStmt:
+			default:
+				continue
+			}
+			plan, err := explainStatement(ctx.Context, ctx.Driver, stmt.Text())
+			if err != nil {
+				adviceList = append(adviceList, advisor.Advice{
+					Status:  level,
+					Code:    advisor.StatementExplainQueryFailed,
+					Title:   string(ctx.Rule.Type),
+					Content: fmt.Sprintf("\"%s\" failed to explain: %v", stmt.Text(), err),
+					Line:    stmt.LastLine(),
+				})
+				continue
+			}
+			if rows := getAffectedRows(plan); payload.Number > 0 && rows > int64(payload.Number) {
+				adviceList = append(adviceList, advisor.Advice{
+					Status:  level,
+					Code:    advisor.StatementAffectedRowExceedsLimit,
+					Title:   string(ctx.Rule.Type),
+					Content: fmt.Sprintf("\"%s\" affected %d rows (estimated). The count exceeds %d.", stmt.Text(), rows, payload.Number),
+					Line:    stmt.LastLine(),
+				})
+			}
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
@@ -0,0 +1,144 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestStatementAffectedRowLimit(t *testing.T) {
+	// There is no database connection in the test, so the advisor skips the check.
+	tests := []advisor.TestCase{
+		{
+			Statement: "UPDATE tech_book SET name = 'x'",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(advisor.NumberTypeRulePayload{
+		Number: 5,
+	})
+	require.NoError(t, err)
+	advisor.RunSQLReviewRuleTests(t, tests, &StatementAffectedRowLimitAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementAffectedRowLimit,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}, advisor.MockPostgreSQLDatabase)
+}
+
+func TestStatementAffectedRowLimitWithDriver(t *testing.T) {
+	conn := newExplainDB(map[string]string{
+		"UPDATE tech_book SET name = 'x'":          `[{"Plan": {"Node Type": "ModifyTable", "Plan Rows": 0, "Plans": [{"Node Type": "Seq Scan", "Plan Rows": 1200}]}}]`,
+		"DELETE FROM tech_book WHERE id = 1":       `[{"Plan": {"Node Type": "ModifyTable", "Plan Rows": 0, "Plans": [{"Node Type": "Index Scan", "Plan Rows": 1}]}}]`,
+		"INSERT INTO tech_book SELECT * FROM book": `[{"Plan": {"Node Type": "ModifyTable", "Plan Rows": 0, "Plans": [{"Node Type": "Seq Scan", "Plan Rows": 5000}]}}]`,
+	})
+	defer conn.Close()
+	payload, err := json.Marshal(advisor.NumberTypeRulePayload{
+		Number: 5,
+	})
+	require.NoError(t, err)
+	rule := &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementAffectedRowLimit,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}
+
+	tests := []struct {
+		statement string
+		want      []advisor.Advice
+	}{
+		{
+			statement: "UPDATE tech_book SET name = 'x'",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementAffectedRowExceedsLimit,
+					Title:   string(advisor.SchemaRuleStatementAffectedRowLimit),
+					Content: "\"UPDATE tech_book SET name = 'x'\" affected 1200 rows (estimated). The count exceeds 5.",
+					Line:    1,
+				},
+			},
+		},
+		{
+			statement: "DELETE FROM tech_book WHERE id = 1",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			// The limit only applies to UPDATE and DELETE.
+			statement: "INSERT INTO tech_book SELECT * FROM book",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			statement: "DELETE FROM unknown_table",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementExplainQueryFailed,
+					Title:   string(advisor.SchemaRuleStatementAffectedRowLimit),
+					Content: "\"DELETE FROM unknown_table\" failed to explain: syntax error in \"EXPLAIN (FORMAT JSON) DELETE FROM unknown_table\"",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		adviceList, err := (&StatementAffectedRowLimitAdvisor{}).Check(advisor.Context{
+			Rule:    rule,
+			Driver:  conn,
+			Context: context.Background(),
+		}, test.statement)
+		require.NoError(t, err)
+		require.Equal(t, test.want, adviceList, test.statement)
+	}
+}
+
+func TestGetAffectedRows(t *testing.T) {
+	tests := []struct {
+		plan string
+		want int64
+	}{
+		{
+			plan: `[{"Plan": {"Node Type": "ModifyTable", "Operation": "Update", "Plan Rows": 0, "Plans": [{"Node Type": "Seq Scan", "Plan Rows": 1200}]}}]`,
+			want: 1200,
+		},
+		{
+			plan: `[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 42}}]`,
+			want: 42,
+		},
+	}
+
+	for _, test := range tests {
+		plan, err := parseExplainPlan(test.plan)
+		require.NoError(t, err)
+		require.Equal(t, test.want, getAffectedRows(plan), test.plan)
+	}
+
+	_, err := parseExplainPlan(`[]`)
+	require.Error(t, err)
+}
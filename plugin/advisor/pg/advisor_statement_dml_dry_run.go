@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*StatementDMLDryRunAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLStatementDMLDryRun, &StatementDMLDryRunAdvisor{})
+}
+
+// StatementDMLDryRunAdvisor is the advisor checking for DML dry run.
+type StatementDMLDryRunAdvisor struct {
+}
+
+// Check checks for DML dry run.
+// It validates the plan of INSERT/UPDATE/DELETE by EXPLAIN without ANALYZE, so it will not execute the statement.
+func (*StatementDMLDryRunAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	// Skip the check if there is no connection to the database, e.g. the SQL review API.
+	if ctx.Driver != nil {
+		// The tables created or altered earlier in the statement don't exist or are not altered yet in the database,
+		// so EXPLAIN cannot validate the DML on them.
+		changedTableMap := make(map[string]bool)
+		for _, stmt := range stmts {
+			var table *ast.TableDef
+			switch node := stmt.(type) {
+			case *ast.CreateTableStmt:
+				changedTableMap[normalizeDryRunTableName(node.Name)] = true
+				continue
+			case *ast.AlterTableStmt:
+				changedTableMap[normalizeDryRunTableName(node.Table)] = true
+				for _, item := range node.AlterItemList {
+					if rename, ok := item.(*ast.RenameTableStmt); ok {
+						changedTableMap[normalizeDryRunTableName(&ast.TableDef{Schema: node.Table.Schema, Name: rename.NewName})] = true
+					}
+				}
+				continue
+			case *ast.DropTableStmt:
+				for _, table := range node.TableList {
+					changedTableMap[normalizeDryRunTableName(table)] = true
+				}
+				continue
+			case *ast.InsertStmt:
+				table = node.Table
+			case *ast.UpdateStmt:
+				table = node.Table
+			case *ast.DeleteStmt:
+				table = node.Table
+			default:
+				continue
+			}
+			if changedTableMap[normalizeDryRunTableName(table)] {
+				continue
+			}
+			if _, err := explainStatement(ctx.Context, ctx.Driver, stmt.Text()); err != nil {
+				adviceList = append(adviceList, advisor.Advice{
+					Status:  level,
+					Code:    advisor.StatementDMLDryRunFailed,
+					Title:   string(ctx.Rule.Type),
+					Content: fmt.Sprintf("\"%s\" dry runs failed: %v", stmt.Text(), err),
+					Line:    stmt.LastLine(),
+				})
+			}
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+func normalizeDryRunTableName(table *ast.TableDef) string {
+	if table == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", normalizeSchemaName(table.Schema), table.Name)
+}
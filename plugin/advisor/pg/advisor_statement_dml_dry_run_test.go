@@ -0,0 +1,107 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestStatementDMLDryRun(t *testing.T) {
+	conn := newExplainDB(map[string]string{
+		"INSERT INTO tech_book VALUES (1, 'x')": `[{"Plan": {"Node Type": "ModifyTable", "Plan Rows": 0, "Plans": [{"Node Type": "Result", "Plan Rows": 1}]}}]`,
+	})
+	defer conn.Close()
+	rule := &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementDMLDryRun,
+		Level:   advisor.SchemaRuleLevelError,
+		Payload: "",
+	}
+
+	tests := []struct {
+		statement string
+		driver    bool
+		want      []advisor.Advice
+	}{
+		{
+			statement: "INSERT INTO tech_book VALUES (1, 'x')",
+			driver:    true,
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			statement: "UPDATE unknown_table SET name = 'x'",
+			driver:    true,
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementDMLDryRunFailed,
+					Title:   string(advisor.SchemaRuleStatementDMLDryRun),
+					Content: "\"UPDATE unknown_table SET name = 'x'\" dry runs failed: syntax error in \"EXPLAIN (FORMAT JSON) UPDATE unknown_table SET name = 'x'\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			// The table created earlier in the statement doesn't exist in the database yet.
+			statement: "CREATE TABLE t(id INT, name TEXT);\nINSERT INTO public.t VALUES (1, 'x');\nUPDATE unknown_table SET name = 'x'",
+			driver:    true,
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementDMLDryRunFailed,
+					Title:   string(advisor.SchemaRuleStatementDMLDryRun),
+					Content: "\"UPDATE unknown_table SET name = 'x'\" dry runs failed: syntax error in \"EXPLAIN (FORMAT JSON) UPDATE unknown_table SET name = 'x'\"",
+					Line:    3,
+				},
+			},
+		},
+		{
+			// The tables altered or renamed earlier in the statement.
+			statement: "ALTER TABLE tech_book ADD COLUMN author TEXT;\nUPDATE tech_book SET author = 'x';\nALTER TABLE book RENAME TO novel;\nDELETE FROM novel",
+			driver:    true,
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			// There is no database connection, e.g. the SQL review API, so the advisor skips the check.
+			statement: "UPDATE unknown_table SET name = 'x'",
+			driver:    false,
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ctx := advisor.Context{
+			Rule:    rule,
+			Context: context.Background(),
+		}
+		if test.driver {
+			ctx.Driver = conn
+		}
+		adviceList, err := (&StatementDMLDryRunAdvisor{}).Check(ctx, test.statement)
+		require.NoError(t, err)
+		require.Equal(t, test.want, adviceList, test.statement)
+	}
+}
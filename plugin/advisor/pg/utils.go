@@ -2,7 +2,12 @@
 package pg
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -32,3 +37,46 @@ func normalizeSchemaName(name string) string {
 	}
 	return "public"
 }
+
+// explainPlan is the plan node of the PostgreSQL EXPLAIN (FORMAT JSON) output.
+type explainPlan struct {
+	NodeType string         `json:"Node Type"`
+	PlanRows float64        `json:"Plan Rows"`
+	Plans    []*explainPlan `json:"Plans"`
+}
+
+type explainResult struct {
+	Plan *explainPlan `json:"Plan"`
+}
+
+// explainStatement runs EXPLAIN without ANALYZE for the statement, so the statement will not be executed.
+func explainStatement(ctx context.Context, conn *sql.DB, statement string) (*explainPlan, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var plan string
+	if err := conn.QueryRowContext(ctx, fmt.Sprintf("EXPLAIN (FORMAT JSON) %s", statement)).Scan(&plan); err != nil {
+		return nil, err
+	}
+	return parseExplainPlan(plan)
+}
+
+func parseExplainPlan(plan string) (*explainPlan, error) {
+	var resultList []*explainResult
+	if err := json.Unmarshal([]byte(plan), &resultList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal explain result %q", plan)
+	}
+	if len(resultList) != 1 || resultList[0].Plan == nil {
+		return nil, errors.Errorf("unexpected explain result %q", plan)
+	}
+	return resultList[0].Plan, nil
+}
+
+// getAffectedRows returns the estimated affected rows for UPDATE and DELETE.
+// The top node is the ModifyTable node whose "Plan Rows" is 0 in most versions, so we use its child node instead.
+func getAffectedRows(plan *explainPlan) int64 {
+	if plan.NodeType == "ModifyTable" && len(plan.Plans) > 0 {
+		return int64(plan.Plans[0].PlanRows)
+	}
+	return int64(plan.PlanRows)
+}
@@ -0,0 +1,291 @@
+package advisor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReportFormat is the output format of the SQL review result.
+type ReportFormat string
+
+const (
+	// ReportFormatJSON is the default format, the plain advice list in JSON.
+	ReportFormatJSON ReportFormat = "application/json"
+	// ReportFormatSARIF is the SARIF 2.1.0 format, consumed by GitHub code scanning.
+	// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+	ReportFormatSARIF ReportFormat = "application/sarif+json"
+	// ReportFormatJUnit is the JUnit XML format, consumed by GitLab test reports.
+	ReportFormatJUnit ReportFormat = "application/xml"
+
+	// ErrorCodeDocURL is the URL for the SQL review error code doc.
+	ErrorCodeDocURL = "https://www.bytebase.com/docs/reference/error-code/advisor"
+
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	reportToolName = "Bytebase SQL Review"
+	reportToolURI  = "https://www.bytebase.com/docs/sql-review/overview"
+)
+
+// NegotiateReportFormat returns the report format by the Accept header value.
+// It falls back to ReportFormatJSON if no supported format is found.
+func NegotiateReportFormat(accept string) ReportFormat {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType := strings.ToLower(strings.TrimSpace(strings.Split(mediaRange, ";")[0]))
+		switch mediaType {
+		case string(ReportFormatSARIF):
+			return ReportFormatSARIF
+		case string(ReportFormatJUnit), "text/xml", "application/junit+xml":
+			return ReportFormatJUnit
+		case string(ReportFormatJSON):
+			return ReportFormatJSON
+		}
+	}
+	return ReportFormatJSON
+}
+
+// FileAdvice is the SQL review result for a single file.
+type FileAdvice struct {
+	FilePath   string   `json:"filePath"`
+	AdviceList []Advice `json:"adviceList"`
+}
+
+// GetErrorCodeHelpURI returns the doc URL for the advice code.
+func GetErrorCodeHelpURI(code Code) string {
+	return fmt.Sprintf("%s#%d", ErrorCodeDocURL, code)
+}
+
+type sarifLog struct {
+	Version string      `json:"version"`
+	Schema  string      `json:"$schema"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Version        string       `json:"version,omitempty"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	Help             sarifMessage `json:"help"`
+	HelpURI          string       `json:"helpUri"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	RuleIndex int              `json:"ruleIndex"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// ConvertToSARIF converts the SQL review results to the SARIF 2.1.0 log.
+// The successful advices are omitted.
+func ConvertToSARIF(toolVersion string, fileAdviceList []*FileAdvice) ([]byte, error) {
+	run := &sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           reportToolName,
+				InformationURI: reportToolURI,
+				Version:        toolVersion,
+				Rules:          []*sarifRule{},
+			},
+		},
+		Results: []*sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, fileAdvice := range fileAdviceList {
+		for _, advice := range fileAdvice.AdviceList {
+			if advice.Status == Success {
+				continue
+			}
+			ruleID := getReportRuleID(advice)
+			index, ok := ruleIndex[ruleID]
+			if !ok {
+				index = len(run.Tool.Driver.Rules)
+				ruleIndex[ruleID] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{
+					ID:               ruleID,
+					ShortDescription: sarifMessage{Text: advice.Title},
+					Help:             sarifMessage{Text: getReportHelpText(advice)},
+					HelpURI:          GetErrorCodeHelpURI(advice.Code),
+				})
+			}
+			run.Results = append(run.Results, &sarifResult{
+				RuleID:    ruleID,
+				RuleIndex: index,
+				Level:     getSARIFLevel(advice.Status),
+				Message:   sarifMessage{Text: getReportMessage(advice)},
+				Locations: []*sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: fileAdvice.FilePath},
+							Region:           sarifRegion{StartLine: getReportLine(advice)},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return json.MarshalIndent(&sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchemaURI,
+		Runs:    []*sarifRun{run},
+	}, "", "  ")
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Name       string            `xml:"name,attr"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	TestSuites []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ConvertToJUnit converts the SQL review results to the JUnit XML report.
+// Each file is a test suite, and each advice is a test case. A file without any finding reports a single passed test case.
+func ConvertToJUnit(fileAdviceList []*FileAdvice) ([]byte, error) {
+	suites := &junitTestSuites{
+		Name: reportToolName,
+	}
+	for _, fileAdvice := range fileAdviceList {
+		suite := &junitTestSuite{
+			Name: fileAdvice.FilePath,
+		}
+		for _, advice := range fileAdvice.AdviceList {
+			if advice.Status == Success {
+				continue
+			}
+			line := getReportLine(advice)
+			suite.TestCases = append(suite.TestCases, &junitTestCase{
+				Name:      getReportTestCaseName(advice),
+				Classname: fileAdvice.FilePath,
+				File:      fileAdvice.FilePath,
+				Line:      line,
+				Failure: &junitFailure{
+					Message: getReportMessage(advice),
+					Type:    string(advice.Status),
+					Text:    fmt.Sprintf("%s\n%s:%d\n%s", getReportMessage(advice), fileAdvice.FilePath, line, getReportHelpText(advice)),
+				},
+			})
+			suite.Failures++
+		}
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, &junitTestCase{
+				Name:      "OK",
+				Classname: fileAdvice.FilePath,
+				File:      fileAdvice.FilePath,
+			})
+		}
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// getReportRuleID returns the stable rule id for the advice, which is the advice code, e.g. "301".
+// The title is not stable because the custom rules and the builtin errors such as syntax error have free-form titles.
+func getReportRuleID(advice Advice) string {
+	return strconv.Itoa(advice.Code.Int())
+}
+
+// getReportTestCaseName returns the readable test case name for the advice.
+// The title of the SQL review advice is the rule type, e.g. "naming.table", except for the builtin errors such as syntax error.
+func getReportTestCaseName(advice Advice) string {
+	return fmt.Sprintf("%s (%d)", advice.Title, advice.Code)
+}
+
+func getReportMessage(advice Advice) string {
+	if advice.Content == "" {
+		return advice.Title
+	}
+	return advice.Content
+}
+
+func getReportHelpText(advice Advice) string {
+	return fmt.Sprintf("You can check the docs at %s", GetErrorCodeHelpURI(advice.Code))
+}
+
+func getReportLine(advice Advice) int {
+	// The line is 1-based and some advices such as syntax error don't carry the line.
+	if advice.Line < 1 {
+		return 1
+	}
+	return advice.Line
+}
+
+func getSARIFLevel(status Status) string {
+	switch status {
+	case Error:
+		return "error"
+	case Warn:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,93 @@
+package advisor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mockFileAdviceList = []*FileAdvice{
+	{
+		FilePath: "migration/1_create_book.sql",
+		AdviceList: []Advice{
+			{
+				Status:  Warn,
+				Code:    NamingTableConventionMismatch,
+				Title:   "naming.table",
+				Content: "\"techBook\" mismatches table naming convention",
+				Line:    3,
+			},
+			{
+				Status:  Error,
+				Code:    StatementSyntaxError,
+				Title:   SyntaxErrorTitle,
+				Content: "line 1 column 6 near \"TABL\"",
+			},
+		},
+	},
+	{
+		FilePath: "migration/2_insert_book.sql",
+		AdviceList: []Advice{
+			{
+				Status: Success,
+				Code:   Ok,
+				Title:  "OK",
+			},
+		},
+	},
+}
+
+func TestNegotiateReportFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   ReportFormat
+	}{
+		{"", ReportFormatJSON},
+		{"*/*", ReportFormatJSON},
+		{"application/json", ReportFormatJSON},
+		{"application/sarif+json", ReportFormatSARIF},
+		{"text/html, application/xml;q=0.9", ReportFormatJUnit},
+		{"application/junit+xml", ReportFormatJUnit},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, NegotiateReportFormat(test.accept), test.accept)
+	}
+}
+
+func TestConvertToSARIF(t *testing.T) {
+	out, err := ConvertToSARIF("1.0.0", mockFileAdviceList)
+	require.NoError(t, err)
+
+	log := &sarifLog{}
+	require.NoError(t, json.Unmarshal(out, log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "301", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "naming.table", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	assert.Equal(t, "https://www.bytebase.com/docs/reference/error-code/advisor#301", run.Tool.Driver.Rules[0].HelpURI)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "warning", run.Results[0].Level)
+	assert.Equal(t, "migration/1_create_book.sql", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "error", run.Results[1].Level)
+	assert.Equal(t, 1, run.Results[1].RuleIndex)
+	assert.Equal(t, "201", run.Results[1].RuleID)
+	assert.Equal(t, 1, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestConvertToJUnit(t *testing.T) {
+	out, err := ConvertToJUnit(mockFileAdviceList)
+	require.NoError(t, err)
+
+	report := string(out)
+	assert.True(t, strings.HasPrefix(report, "<?xml"))
+	assert.Contains(t, report, `<testsuites name="Bytebase SQL Review" tests="3" failures="2">`)
+	assert.Contains(t, report, `<testsuite name="migration/2_insert_book.sql" tests="1" failures="0">`)
+	assert.Contains(t, report, `<testcase name="naming.table (301)" classname="migration/1_create_book.sql" file="migration/1_create_book.sql" line="3">`)
+	assert.Contains(t, report, `&#34;techBook&#34; mismatches table naming convention`)
+}
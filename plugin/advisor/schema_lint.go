@@ -0,0 +1,406 @@
+package advisor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+)
+
+// SchemaLintResult is the result of linting the whole database schema.
+type SchemaLintResult struct {
+	AdviceList []Advice `json:"adviceList"`
+	// SkippedRuleList is the list of the enabled rules that cannot be evaluated against the schema metadata,
+	// e.g. the statement rules, or the foreign key rules because the synced schema doesn't contain the foreign keys.
+	SkippedRuleList []SQLReviewRuleType `json:"skippedRuleList"`
+}
+
+// schemaLintFunc lints the database schema with the rule.
+type schemaLintFunc func(linter *schemaLinter, database *catalog.Database) error
+
+// schemaLintFuncMap is the mapping from the SQL review rule type to the schema lint function.
+// Only the rules about the final schema state rather than the statement itself are supported.
+var schemaLintFuncMap = map[SQLReviewRuleType]schemaLintFunc{
+	SchemaRuleMySQLEngine:             lintTableEngine,
+	SchemaRuleTableNaming:             lintTableNaming,
+	SchemaRuleColumnNaming:            lintColumnNaming,
+	SchemaRuleIDXNaming:               lintIndexNaming,
+	SchemaRuleUKNaming:                lintIndexNaming,
+	SchemaRulePKNaming:                lintIndexNaming,
+	SchemaRuleTableRequirePK:          lintTableRequirePK,
+	SchemaRuleTableCommentConvention:  lintTableComment,
+	SchemaRuleColumnCommentConvention: lintColumnComment,
+	SchemaRuleRequiredColumn:          lintRequiredColumn,
+	SchemaRuleColumnNotNull:           lintColumnNotNull,
+	SchemaRuleCharsetAllowlist:        lintCharsetAllowlist,
+	SchemaRuleCollationAllowlist:      lintCollationAllowlist,
+	SchemaRuleIndexKeyNumberLimit:     lintIndexKeyNumberLimit,
+	SchemaRuleIndexTotalNumberLimit:   lintIndexTotalNumberLimit,
+}
+
+type schemaLinter struct {
+	rule       *SQLReviewRule
+	level      Status
+	adviceList []Advice
+}
+
+func (linter *schemaLinter) addAdvice(code Code, content string) {
+	linter.adviceList = append(linter.adviceList, Advice{
+		Status:  linter.level,
+		Code:    code,
+		Title:   string(linter.rule.Type),
+		Content: content,
+	})
+}
+
+// SchemaLint checks the database schema against the SQL review rules.
+// Unlike SQLReviewCheck, it evaluates the existing schema instead of the statements to be executed.
+func SchemaLint(database *catalog.Database, ruleList []*SQLReviewRule) (*SchemaLintResult, error) {
+	result := &SchemaLintResult{
+		AdviceList:      []Advice{},
+		SkippedRuleList: []SQLReviewRuleType{},
+	}
+
+	for _, rule := range ruleList {
+		if rule.Level == SchemaRuleLevelDisabled {
+			continue
+		}
+		lint, ok := schemaLintFuncMap[rule.Type]
+		if !ok {
+			result.SkippedRuleList = append(result.SkippedRuleList, rule.Type)
+			continue
+		}
+		// Keep consistent with SQL review, the rule is skipped if the engine doesn't support it.
+		if _, err := getAdvisorTypeByRule(rule.Type, database.DbType); err != nil {
+			result.SkippedRuleList = append(result.SkippedRuleList, rule.Type)
+			continue
+		}
+
+		level, err := NewStatusBySQLReviewRuleLevel(rule.Level)
+		if err != nil {
+			return nil, err
+		}
+		linter := &schemaLinter{
+			rule:  rule,
+			level: level,
+		}
+		if err := lint(linter, database); err != nil {
+			return nil, errors.Wrapf(err, "failed to lint schema with rule %q", rule.Type)
+		}
+		result.AdviceList = append(result.AdviceList, linter.adviceList...)
+	}
+
+	return result, nil
+}
+
+// getLintTableName returns the table name with the schema prefix if the schema is not the default one.
+func getLintTableName(schema *catalog.Schema, table *catalog.Table) string {
+	if schema.Name == "" || schema.Name == "public" {
+		return table.Name
+	}
+	return fmt.Sprintf("%s.%s", schema.Name, table.Name)
+}
+
+func lintTableEngine(linter *schemaLinter, database *catalog.Database) error {
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			// The view and other objects don't have the engine.
+			if table.Engine != "" && !strings.EqualFold(table.Engine, "innodb") {
+				linter.addAdvice(NotInnoDBEngine, fmt.Sprintf("Table `%s` uses %s engine instead of InnoDB", getLintTableName(schema, table), table.Engine))
+			}
+		}
+	}
+	return nil
+}
+
+func lintTableNaming(linter *schemaLinter, database *catalog.Database) error {
+	format, maxLength, err := UnamrshalNamingRulePayloadAsRegexp(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			if !format.MatchString(table.Name) {
+				linter.addAdvice(NamingTableConventionMismatch, fmt.Sprintf("`%s` mismatches table naming convention, naming format should be %q", getLintTableName(schema, table), format))
+			}
+			if maxLength > 0 && len(table.Name) > maxLength {
+				linter.addAdvice(NamingTableConventionMismatch, fmt.Sprintf("`%s` mismatches table naming convention, its length should be within %d characters", getLintTableName(schema, table), maxLength))
+			}
+		}
+	}
+	return nil
+}
+
+func lintColumnNaming(linter *schemaLinter, database *catalog.Database) error {
+	format, maxLength, err := UnamrshalNamingRulePayloadAsRegexp(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			for _, column := range table.ColumnList {
+				if !format.MatchString(column.Name) {
+					linter.addAdvice(NamingColumnConventionMismatch, fmt.Sprintf("`%s`.`%s` mismatches column naming convention, naming format should be %q", getLintTableName(schema, table), column.Name, format))
+				}
+				if maxLength > 0 && len(column.Name) > maxLength {
+					linter.addAdvice(NamingColumnConventionMismatch, fmt.Sprintf("`%s`.`%s` mismatches column naming convention, its length should be within %d characters", getLintTableName(schema, table), column.Name, maxLength))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lintIndexNaming lints the naming of the index, unique key and primary key, depending on the rule type.
+func lintIndexNaming(linter *schemaLinter, database *catalog.Database) error {
+	format, templateList, maxLength, err := UnmarshalNamingRulePayloadAsTemplate(linter.rule.Type, linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+
+	var code Code
+	var kind string
+	switch linter.rule.Type {
+	case SchemaRulePKNaming:
+		code, kind = NamingPKConventionMismatch, "Primary key"
+	case SchemaRuleUKNaming:
+		code, kind = NamingUKConventionMismatch, "Unique key"
+	default:
+		code, kind = NamingIndexConventionMismatch, "Index"
+	}
+
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			for _, index := range table.IndexList {
+				switch linter.rule.Type {
+				case SchemaRulePKNaming:
+					if !index.Primary {
+						continue
+					}
+				case SchemaRuleUKNaming:
+					if index.Primary || !index.Unique {
+						continue
+					}
+				default:
+					if index.Primary || index.Unique {
+						continue
+					}
+				}
+
+				regex, err := getLintTemplateRegexp(format, templateList, map[string]string{
+					TableNameTemplateToken:  table.Name,
+					ColumnListTemplateToken: strings.Join(index.ExpressionList, "_"),
+				})
+				if err != nil {
+					return err
+				}
+				if !regex.MatchString(index.Name) {
+					linter.addAdvice(code, fmt.Sprintf("%s in table `%s` mismatches the naming convention, expect %q but found `%s`", kind, getLintTableName(schema, table), regex, index.Name))
+				}
+				if maxLength > 0 && len(index.Name) > maxLength {
+					linter.addAdvice(code, fmt.Sprintf("%s `%s` in table `%s` mismatches the naming convention, its length should be within %d characters", kind, index.Name, getLintTableName(schema, table), maxLength))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func getLintTemplateRegexp(template string, templateList []string, tokens map[string]string) (*regexp.Regexp, error) {
+	for _, key := range templateList {
+		if token, ok := tokens[key]; ok {
+			template = strings.ReplaceAll(template, key, token)
+		}
+	}
+	return regexp.Compile(template)
+}
+
+func lintTableRequirePK(linter *schemaLinter, database *catalog.Database) error {
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			hasPK := false
+			for _, index := range table.IndexList {
+				if index.Primary {
+					hasPK = true
+					break
+				}
+			}
+			if !hasPK {
+				linter.addAdvice(TableNoPK, fmt.Sprintf("Table `%s` requires PRIMARY KEY", getLintTableName(schema, table)))
+			}
+		}
+	}
+	return nil
+}
+
+func lintTableComment(linter *schemaLinter, database *catalog.Database) error {
+	payload, err := UnmarshalCommentConventionRulePayload(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			if payload.Required && table.Comment == "" {
+				linter.addAdvice(NoTableComment, fmt.Sprintf("Table `%s` requires comments", getLintTableName(schema, table)))
+			}
+			if payload.MaxLength > 0 && len(table.Comment) > payload.MaxLength {
+				linter.addAdvice(TableCommentTooLong, fmt.Sprintf("The length of table `%s` comment should be within %d characters", getLintTableName(schema, table), payload.MaxLength))
+			}
+		}
+	}
+	return nil
+}
+
+func lintColumnComment(linter *schemaLinter, database *catalog.Database) error {
+	payload, err := UnmarshalCommentConventionRulePayload(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			for _, column := range table.ColumnList {
+				if payload.Required && column.Comment == "" {
+					linter.addAdvice(NoColumnComment, fmt.Sprintf("Column `%s`.`%s` requires comments", getLintTableName(schema, table), column.Name))
+				}
+				if payload.MaxLength > 0 && len(column.Comment) > payload.MaxLength {
+					linter.addAdvice(ColumnCommentTooLong, fmt.Sprintf("The length of column `%s`.`%s` comment should be within %d characters", getLintTableName(schema, table), column.Name, payload.MaxLength))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func lintRequiredColumn(linter *schemaLinter, database *catalog.Database) error {
+	requiredColumnList, err := UnmarshalRequiredColumnList(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			columnSet := make(map[string]bool)
+			for _, column := range table.ColumnList {
+				columnSet[column.Name] = true
+			}
+			var missingColumnList []string
+			for _, column := range requiredColumnList {
+				if !columnSet[column] {
+					missingColumnList = append(missingColumnList, column)
+				}
+			}
+			if len(missingColumnList) > 0 {
+				linter.addAdvice(NoRequiredColumn, fmt.Sprintf("Table `%s` requires columns: %s", getLintTableName(schema, table), strings.Join(missingColumnList, ", ")))
+			}
+		}
+	}
+	return nil
+}
+
+func lintColumnNotNull(linter *schemaLinter, database *catalog.Database) error {
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			for _, column := range table.ColumnList {
+				if column.Nullable {
+					linter.addAdvice(ColumnCannotNull, fmt.Sprintf("Column `%s`.`%s` cannot have NULL value", getLintTableName(schema, table), column.Name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func lintCharsetAllowlist(linter *schemaLinter, database *catalog.Database) error {
+	allowlist, err := unmarshalLintAllowlist(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	if database.CharacterSet != "" && !allowlist[strings.ToLower(database.CharacterSet)] {
+		linter.addAdvice(DisabledCharset, fmt.Sprintf("Database `%s` used disabled charset '%s'", database.Name, database.CharacterSet))
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			for _, column := range table.ColumnList {
+				if column.CharacterSet != "" && !allowlist[strings.ToLower(column.CharacterSet)] {
+					linter.addAdvice(DisabledCharset, fmt.Sprintf("Column `%s`.`%s` used disabled charset '%s'", getLintTableName(schema, table), column.Name, column.CharacterSet))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func lintCollationAllowlist(linter *schemaLinter, database *catalog.Database) error {
+	allowlist, err := unmarshalLintAllowlist(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	if database.Collation != "" && !allowlist[strings.ToLower(database.Collation)] {
+		linter.addAdvice(DisabledCollation, fmt.Sprintf("Database `%s` used disabled collation '%s'", database.Name, database.Collation))
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			if table.Collation != "" && !allowlist[strings.ToLower(table.Collation)] {
+				linter.addAdvice(DisabledCollation, fmt.Sprintf("Table `%s` used disabled collation '%s'", getLintTableName(schema, table), table.Collation))
+			}
+			for _, column := range table.ColumnList {
+				if column.Collation != "" && !allowlist[strings.ToLower(column.Collation)] {
+					linter.addAdvice(DisabledCollation, fmt.Sprintf("Column `%s`.`%s` used disabled collation '%s'", getLintTableName(schema, table), column.Name, column.Collation))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func unmarshalLintAllowlist(payload string) (map[string]bool, error) {
+	stringArrayPayload, err := UnmarshalStringArrayTypeRulePayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	allowlist := make(map[string]bool)
+	for _, item := range stringArrayPayload.List {
+		allowlist[strings.ToLower(item)] = true
+	}
+	return allowlist, nil
+}
+
+func lintIndexKeyNumberLimit(linter *schemaLinter, database *catalog.Database) error {
+	payload, err := UnmarshalNumberTypeRulePayload(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Number <= 0 {
+		return nil
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			for _, index := range table.IndexList {
+				if len(index.ExpressionList) > payload.Number {
+					linter.addAdvice(IndexKeyNumberExceedsLimit, fmt.Sprintf("The number of index `%s` in table `%s` should be not greater than %d", index.Name, getLintTableName(schema, table), payload.Number))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func lintIndexTotalNumberLimit(linter *schemaLinter, database *catalog.Database) error {
+	payload, err := UnmarshalNumberTypeRulePayload(linter.rule.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Number <= 0 {
+		return nil
+	}
+	for _, schema := range database.SchemaList {
+		for _, table := range schema.TableList {
+			if len(table.IndexList) > payload.Number {
+				linter.addAdvice(IndexCountExceedsLimit, fmt.Sprintf("The count of index in table `%s` should be no more than %d, but found %d", getLintTableName(schema, table), payload.Number, len(table.IndexList)))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestSchemaLint(t *testing.T) {
+	database := &catalog.Database{
+		Name:         "test",
+		CharacterSet: "utf8mb4",
+		Collation:    "utf8mb4_general_ci",
+		DbType:       db.MySQL,
+		SchemaList: []*catalog.Schema{
+			{
+				TableList: []*catalog.Table{
+					{
+						Name:    "tech_book",
+						Engine:  "InnoDB",
+						Comment: "book",
+						ColumnList: []*catalog.Column{
+							{Name: "id", Comment: "id"},
+							{Name: "Name", Comment: "", Collation: "latin1_swedish_ci"},
+						},
+						IndexList: []*catalog.Index{
+							{Name: "PRIMARY", ExpressionList: []string{"id"}, Primary: true, Unique: true},
+							{Name: "idx_name", ExpressionList: []string{"Name"}},
+						},
+					},
+					{
+						Name:   "TechAuthor",
+						Engine: "MyISAM",
+						ColumnList: []*catalog.Column{
+							{Name: "id", Comment: "id"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ruleList := []*SQLReviewRule{
+		{Type: SchemaRuleMySQLEngine, Level: SchemaRuleLevelError},
+		{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^[a-z]+(_[a-z]+)*$"}`},
+		{Type: SchemaRuleColumnNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^[a-z]+(_[a-z]+)*$"}`},
+		{Type: SchemaRuleIDXNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^idx_{{table}}_{{column_list}}$"}`},
+		{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError},
+		{Type: SchemaRuleTableNoFK, Level: SchemaRuleLevelError},
+		{Type: SchemaRuleTableCommentConvention, Level: SchemaRuleLevelWarning, Payload: `{"required":true}`},
+		{Type: SchemaRuleColumnCommentConvention, Level: SchemaRuleLevelDisabled, Payload: `{"required":true}`},
+		{Type: SchemaRuleCollationAllowlist, Level: SchemaRuleLevelError, Payload: `{"list":["utf8mb4_general_ci"]}`},
+		{Type: SchemaRuleStatementRequireWhere, Level: SchemaRuleLevelError},
+	}
+
+	result, err := SchemaLint(database, ruleList)
+	require.NoError(t, err)
+	require.Equal(t, []SQLReviewRuleType{SchemaRuleTableNoFK, SchemaRuleStatementRequireWhere}, result.SkippedRuleList)
+	require.Equal(t, []Advice{
+		{
+			Status:  Error,
+			Code:    NotInnoDBEngine,
+			Title:   string(SchemaRuleMySQLEngine),
+			Content: "Table `TechAuthor` uses MyISAM engine instead of InnoDB",
+		},
+		{
+			Status:  Warn,
+			Code:    NamingTableConventionMismatch,
+			Title:   string(SchemaRuleTableNaming),
+			Content: "`TechAuthor` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+		},
+		{
+			Status:  Warn,
+			Code:    NamingColumnConventionMismatch,
+			Title:   string(SchemaRuleColumnNaming),
+			Content: "`tech_book`.`Name` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+		},
+		{
+			Status:  Warn,
+			Code:    NamingIndexConventionMismatch,
+			Title:   string(SchemaRuleIDXNaming),
+			Content: "Index in table `tech_book` mismatches the naming convention, expect \"^idx_tech_book_Name$\" but found `idx_name`",
+		},
+		{
+			Status:  Error,
+			Code:    TableNoPK,
+			Title:   string(SchemaRuleTableRequirePK),
+			Content: "Table `TechAuthor` requires PRIMARY KEY",
+		},
+		{
+			Status:  Warn,
+			Code:    NoTableComment,
+			Title:   string(SchemaRuleTableCommentConvention),
+			Content: "Table `TechAuthor` requires comments",
+		},
+		{
+			Status:  Error,
+			Code:    DisabledCollation,
+			Title:   string(SchemaRuleCollationAllowlist),
+			Content: "Column `tech_book`.`Name` used disabled collation 'latin1_swedish_ci'",
+		},
+	}, result.AdviceList)
+}
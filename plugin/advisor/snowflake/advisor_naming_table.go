@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*NamingTableConventionAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeNamingTableConvention, &NamingTableConventionAdvisor{})
+}
+
+// NamingTableConventionAdvisor is the advisor checking for table naming convention.
+type NamingTableConventionAdvisor struct {
+}
+
+// Check checks for table naming convention.
+func (*NamingTableConventionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	format, maxLength, err := advisor.UnamrshalNamingRulePayloadAsRegexp(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		tableName, ok := getTableNameToCheck(stmt)
+		if !ok {
+			continue
+		}
+		if !format.MatchString(tableName) {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.NamingTableConventionMismatch,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf(`"%s" mismatches table naming convention, naming format should be %q`, tableName, format),
+				Line:    stmt.line,
+			})
+		}
+		if maxLength > 0 && len(tableName) > maxLength {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.NamingTableConventionMismatch,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("\"%s\" mismatches table naming convention, its length should be within %d characters", tableName, maxLength),
+				Line:    stmt.line,
+			})
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// getTableNameToCheck returns the new table name of the CREATE TABLE or ALTER TABLE ... RENAME TO statement.
+func getTableNameToCheck(stmt *tokenizedStatement) (string, bool) {
+	if stmt.hasPrefix("CREATE") {
+		pos := stmt.skipWords(1, "OR", "REPLACE")
+		if pos < len(stmt.tokenList) && (stmt.tokenList[pos].IsWord("LOCAL") || stmt.tokenList[pos].IsWord("GLOBAL")) {
+			pos++
+		}
+		if pos < len(stmt.tokenList) {
+			token := stmt.tokenList[pos]
+			if token.IsWord("TEMP") || token.IsWord("TEMPORARY") || token.IsWord("VOLATILE") || token.IsWord("TRANSIENT") {
+				pos++
+			}
+		}
+		if pos >= len(stmt.tokenList) || !stmt.tokenList[pos].IsWord("TABLE") {
+			return "", false
+		}
+		pos = stmt.skipWords(pos+1, "IF", "NOT", "EXISTS")
+		tableName, _ := stmt.readTableName(pos)
+		return tableName, tableName != ""
+	}
+
+	if stmt.hasPrefix("ALTER", "TABLE") {
+		pos := stmt.skipWords(2, "IF", "EXISTS")
+		_, pos = stmt.readTableName(pos)
+		if pos+2 < len(stmt.tokenList) && stmt.tokenList[pos].IsWord("RENAME") && stmt.tokenList[pos+1].IsWord("TO") {
+			tableName, _ := stmt.readTableName(pos + 2)
+			return tableName, tableName != ""
+		}
+	}
+	return "", false
+}
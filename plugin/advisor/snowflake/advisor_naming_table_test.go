@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestNamingTableConvention(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE OR REPLACE TRANSIENT TABLE IF NOT EXISTS db.public.tech_book(id NUMBER)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE \"TechBook\"(id NUMBER)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NamingTableConventionMismatch,
+					Title:   "naming.table",
+					Content: "\"TechBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE IF EXISTS tech_book RENAME TO TechBook",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NamingTableConventionMismatch,
+					Title:   "naming.table",
+					Content: "\"TechBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE tech_book_with_a_very_long_name_more_than_sixty_four_characters_in_total(id NUMBER)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NamingTableConventionMismatch,
+					Title:   "naming.table",
+					Content: "\"tech_book_with_a_very_long_name_more_than_sixty_four_characters_in_total\" mismatches table naming convention, its length should be within 64 characters",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(advisor.NamingRulePayload{
+		Format:    "^[a-z]+(_[a-z]+)*$",
+		MaxLength: 64,
+	})
+	require.NoError(t, err)
+	advisor.RunSQLReviewRuleTests(t, tests, &NamingTableConventionAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleTableNaming,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: string(payload),
+	}, advisor.MockSnowflakeDatabase)
+}
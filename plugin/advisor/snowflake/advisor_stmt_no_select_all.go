@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*NoSelectAllAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeNoSelectAll, &NoSelectAllAdvisor{})
+}
+
+// NoSelectAllAdvisor is the advisor checking for no "select *".
+type NoSelectAllAdvisor struct {
+}
+
+// Check checks for no "select *".
+func (*NoSelectAllAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		if hasSelectAll(stmt) {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.StatementSelectAll,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("\"%s\" uses SELECT all", stmt.text),
+				Line:    stmt.line,
+			})
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// hasSelectAll returns true if the statement selects * or t.* in any SELECT list, including the subqueries.
+// The * in COUNT(*) or the multiplication is preceded by other tokens, so we can tell them apart.
+func hasSelectAll(stmt *tokenizedStatement) bool {
+	for i := 1; i < len(stmt.tokenList); i++ {
+		if !stmt.tokenList[i].IsPunctuation("*") {
+			continue
+		}
+		prev := stmt.tokenList[i-1]
+		if prev.IsWord("SELECT") || prev.IsWord("DISTINCT") || prev.IsWord("ALL") || prev.IsPunctuation(",") || prev.IsPunctuation(".") {
+			if prev.IsPunctuation(",") && !inSelectList(stmt, i) {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// inSelectList returns true if the token at the position is in a SELECT list,
+// i.e. the nearest preceding SELECT or FROM keyword at the same depth is SELECT.
+func inSelectList(stmt *tokenizedStatement, pos int) bool {
+	depth := stmt.depthList[pos]
+	for i := pos - 1; i >= 0; i-- {
+		if stmt.depthList[i] < depth {
+			return false
+		}
+		if stmt.depthList[i] != depth {
+			continue
+		}
+		if stmt.tokenList[i].IsWord("SELECT") {
+			return true
+		}
+		if stmt.tokenList[i].IsWord("FROM") {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestNoSelectAll(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "SELECT id, COUNT(*), 2 * 3 FROM t GROUP BY id",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "SELECT * FROM t",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementSelectAll,
+					Title:   "statement.select.no-select-all",
+					Content: "\"SELECT * FROM t\" uses SELECT all",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "INSERT INTO t2 SELECT id FROM (SELECT a.id, b.* FROM a JOIN b ON a.id = b.id)",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementSelectAll,
+					Title:   "statement.select.no-select-all",
+					Content: "\"INSERT INTO t2 SELECT id FROM (SELECT a.id, b.* FROM a JOIN b ON a.id = b.id)\" uses SELECT all",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &NoSelectAllAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementNoSelectAll,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockSnowflakeDatabase)
+}
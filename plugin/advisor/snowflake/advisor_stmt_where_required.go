@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*WhereRequirementAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeWhereRequirement, &WhereRequirementAdvisor{})
+}
+
+// WhereRequirementAdvisor is the advisor checking for the WHERE clause requirement.
+type WhereRequirementAdvisor struct {
+}
+
+// Check checks for the WHERE clause requirement.
+func (*WhereRequirementAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		if !stmt.hasPrefix("UPDATE") && !stmt.hasPrefix("DELETE") {
+			continue
+		}
+		if stmt.findTopLevelWord(1, "WHERE") < 0 {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.StatementNoWhere,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("\"%s\" requires WHERE clause", stmt.text),
+				Line:    stmt.line,
+			})
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
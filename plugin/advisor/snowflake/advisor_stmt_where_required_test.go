@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestWhereRequirement(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "UPDATE t SET name = 'a' WHERE id = 1;\nDELETE FROM t WHERE id IN (SELECT id FROM t2);",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+		{
+			Statement: "DELETE FROM t USING (SELECT id FROM t2 WHERE id > 1) s",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementNoWhere,
+					Title:   "statement.where.require",
+					Content: "\"DELETE FROM t USING (SELECT id FROM t2 WHERE id > 1) s\" requires WHERE clause",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "UPDATE t SET name = 'a'",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.StatementNoWhere,
+					Title:   "statement.where.require",
+					Content: "\"UPDATE t SET name = 'a'\" requires WHERE clause",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	advisor.RunSQLReviewRuleTests(t, tests, &WhereRequirementAdvisor{}, &advisor.SQLReviewRule{
+		Type:    advisor.SchemaRuleStatementRequireWhere,
+		Level:   advisor.SchemaRuleLevelWarning,
+		Payload: "",
+	}, advisor.MockSnowflakeDatabase)
+}
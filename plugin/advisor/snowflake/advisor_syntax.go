@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*SyntaxAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeSyntax, &SyntaxAdvisor{})
+}
+
+// SyntaxAdvisor is the advisor for checking syntax.
+// It only checks the lexical structure and the statement type, because we don't have a full Snowflake parser.
+type SyntaxAdvisor struct {
+}
+
+// Check parses the given statement and checks for errors.
+func (*SyntaxAdvisor) Check(_ advisor.Context, statement string) ([]advisor.Advice, error) {
+	if _, errAdvice := parseStatement(statement); errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	return []advisor.Advice{
+		{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "Syntax OK",
+			Content: "OK",
+		},
+	}, nil
+}
@@ -0,0 +1,169 @@
+// Package snowflake is the advisor for Snowflake database.
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+// statementKeywordSet is the set of the leading keywords of Snowflake statements.
+// See https://docs.snowflake.com/en/sql-reference/sql-all.html.
+var statementKeywordSet = map[string]bool{
+	"ALTER":    true,
+	"BEGIN":    true,
+	"CALL":     true,
+	"COMMENT":  true,
+	"COMMIT":   true,
+	"COPY":     true,
+	"CREATE":   true,
+	"DELETE":   true,
+	"DESC":     true,
+	"DESCRIBE": true,
+	"DROP":     true,
+	"EXECUTE":  true,
+	"EXPLAIN":  true,
+	"GET":      true,
+	"GRANT":    true,
+	"INSERT":   true,
+	"LIST":     true,
+	"LS":       true,
+	"MERGE":    true,
+	"PUT":      true,
+	"REMOVE":   true,
+	"REVOKE":   true,
+	"RM":       true,
+	"ROLLBACK": true,
+	"SELECT":   true,
+	"SET":      true,
+	"SHOW":     true,
+	"START":    true,
+	"TRUNCATE": true,
+	"UNDROP":   true,
+	"UNSET":    true,
+	"UPDATE":   true,
+	"USE":      true,
+	"VALUES":   true,
+	"WITH":     true,
+}
+
+// tokenizedStatement is the Snowflake statement with lexical tokens.
+// We don't have a full Snowflake parser, so the advisors work on the token list.
+type tokenizedStatement struct {
+	text      string
+	line      int
+	tokenList []parser.Token
+	// depthList is the parentheses depth of each token, 0 means top level.
+	depthList []int
+}
+
+func parseStatement(statement string) ([]*tokenizedStatement, []advisor.Advice) {
+	singleSQLList, err := parser.SplitMultiSQL(parser.Snowflake, statement)
+	if err != nil {
+		return nil, []advisor.Advice{newSyntaxErrorAdvice(err.Error(), 0)}
+	}
+
+	var res []*tokenizedStatement
+	for _, singleSQL := range singleSQLList {
+		tokenList, err := parser.Tokenize(parser.Snowflake, singleSQL.Text)
+		if err != nil {
+			return nil, []advisor.Advice{newSyntaxErrorAdvice(err.Error(), singleSQL.LastLine)}
+		}
+		if len(tokenList) > 0 && tokenList[len(tokenList)-1].IsPunctuation(";") {
+			tokenList = tokenList[:len(tokenList)-1]
+		}
+		if len(tokenList) == 0 {
+			continue
+		}
+		if tokenList[0].Type != parser.TokenWord || !statementKeywordSet[strings.ToUpper(tokenList[0].Text)] {
+			return nil, []advisor.Advice{newSyntaxErrorAdvice(fmt.Sprintf("unexpected %q at the beginning of statement %q", tokenList[0].Text, singleSQL.Text), singleSQL.LastLine)}
+		}
+		res = append(res, &tokenizedStatement{
+			text:      singleSQL.Text,
+			line:      singleSQL.LastLine,
+			tokenList: tokenList,
+			depthList: getDepthList(tokenList),
+		})
+	}
+	return res, nil
+}
+
+func newSyntaxErrorAdvice(content string, line int) advisor.Advice {
+	return advisor.Advice{
+		Status:  advisor.Error,
+		Code:    advisor.StatementSyntaxError,
+		Title:   advisor.SyntaxErrorTitle,
+		Content: content,
+		Line:    line,
+	}
+}
+
+func getDepthList(tokenList []parser.Token) []int {
+	var res []int
+	depth := 0
+	for _, token := range tokenList {
+		if token.IsPunctuation(")") {
+			depth--
+		}
+		res = append(res, depth)
+		if token.IsPunctuation("(") {
+			depth++
+		}
+	}
+	return res
+}
+
+// hasPrefix returns true if the statement starts with the given words.
+func (s *tokenizedStatement) hasPrefix(wordList ...string) bool {
+	if len(s.tokenList) < len(wordList) {
+		return false
+	}
+	for i, word := range wordList {
+		if !s.tokenList[i].IsWord(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// findTopLevelWord returns the index of the first top level word from the start position, or -1 if not found.
+func (s *tokenizedStatement) findTopLevelWord(start int, word string) int {
+	for i := start; i < len(s.tokenList); i++ {
+		if s.depthList[i] == 0 && s.tokenList[i].IsWord(word) {
+			return i
+		}
+	}
+	return -1
+}
+
+// skipWords skips the optional words from the position, and returns the next position.
+func (s *tokenizedStatement) skipWords(pos int, wordList ...string) int {
+	for i, word := range wordList {
+		if pos+i >= len(s.tokenList) || !s.tokenList[pos+i].IsWord(word) {
+			return pos
+		}
+	}
+	return pos + len(wordList)
+}
+
+// readTableName reads the table name such as db.schema.t or "db"."schema"."t" from the position.
+// It returns the table name without the database and schema, and the next position.
+func (s *tokenizedStatement) readTableName(pos int) (string, int) {
+	name := ""
+	for pos < len(s.tokenList) {
+		token := s.tokenList[pos]
+		if token.Type != parser.TokenWord && token.Type != parser.TokenQuotedIdentifier {
+			break
+		}
+		name = token.Text
+		pos++
+		if pos < len(s.tokenList) && s.tokenList[pos].IsPunctuation(".") {
+			pos++
+			continue
+		}
+		break
+	}
+	return name, pos
+}
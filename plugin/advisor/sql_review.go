@@ -1,6 +1,8 @@
 package advisor
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"log"
 	"regexp"
@@ -33,6 +35,8 @@ const (
 
 	// SchemaRuleMySQLEngine require InnoDB as the storage engine.
 	SchemaRuleMySQLEngine SQLReviewRuleType = "engine.mysql.use-innodb"
+	// SchemaRuleClickHouseEngine require the table to declare the engine, and the ORDER BY key for MergeTree family engines.
+	SchemaRuleClickHouseEngine SQLReviewRuleType = "engine.clickhouse.require-engine"
 
 	// SchemaRuleTableNaming enforce the table name format.
 	SchemaRuleTableNaming SQLReviewRuleType = "naming.table"
@@ -75,6 +79,10 @@ const (
 	SchemaRuleStatementAffectedRowLimit SQLReviewRuleType = "statement.affected-row-limit"
 	// SchemaRuleStatementDMLDryRun dry run the dml.
 	SchemaRuleStatementDMLDryRun SQLReviewRuleType = "statement.dml-dry-run"
+	// SchemaRuleStatementDisallowMutation disallow the ClickHouse ALTER TABLE ... UPDATE/DELETE mutations on the tables exceeding the row limit.
+	SchemaRuleStatementDisallowMutation SQLReviewRuleType = "statement.clickhouse.disallow-mutation"
+	// SchemaRuleStatementOnClusterConsistency require the ClickHouse DDL statements to use ON CLUSTER consistently.
+	SchemaRuleStatementOnClusterConsistency SQLReviewRuleType = "statement.clickhouse.on-cluster-consistency"
 
 	// SchemaRuleTableRequirePK require the table to have a primary key.
 	SchemaRuleTableRequirePK SQLReviewRuleType = "table.require-pk"
@@ -146,6 +154,9 @@ const (
 	// SchemaRuleCommentLength limit comment length.
 	SchemaRuleCommentLength SQLReviewRuleType = "comment.length"
 
+	// SchemaRuleCustom is the user-defined rule with a declarative predicate payload.
+	SchemaRuleCustom SQLReviewRuleType = "custom"
+
 	// TableNameTemplateToken is the token for table name.
 	TableNameTemplateToken = "{{table}}"
 	// ColumnListTemplateToken is the token for column name list.
@@ -239,7 +250,8 @@ func (rule *SQLReviewRule) Validate() error {
 			return err
 		}
 	case SchemaRuleIndexKeyNumberLimit, SchemaRuleStatementInsertRowLimit, SchemaRuleIndexTotalNumberLimit,
-		SchemaRuleColumnMaximumCharacterLength, SchemaRuleColumnAutoIncrementInitialValue, SchemaRuleStatementAffectedRowLimit:
+		SchemaRuleColumnMaximumCharacterLength, SchemaRuleColumnAutoIncrementInitialValue, SchemaRuleStatementAffectedRowLimit,
+		SchemaRuleStatementDisallowMutation:
 		if _, err := UnmarshalNumberTypeRulePayload(rule.Payload); err != nil {
 			return err
 		}
@@ -247,6 +259,10 @@ func (rule *SQLReviewRule) Validate() error {
 		if _, err := UnmarshalStringArrayTypeRulePayload(rule.Payload); err != nil {
 			return err
 		}
+	case SchemaRuleCustom:
+		if _, err := UnmarshalCustomRulePayload(rule.Payload); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -412,6 +428,11 @@ type SQLReviewCheckContext struct {
 	Collation string
 	DbType    db.Type
 	Catalog   catalog.Catalog
+
+	// Driver is the read-only connection to the target database, used by the advisors running EXPLAIN.
+	// It's optional and these advisors will skip the check if it's nil.
+	Driver  *sql.DB
+	Context context.Context
 }
 
 // SQLReviewCheck checks the statements with sql review rules.
@@ -445,6 +466,8 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 				Collation: checkContext.Collation,
 				Rule:      rule,
 				Catalog:   finder,
+				Driver:    checkContext.Driver,
+				Context:   checkContext.Context,
 			},
 			statements,
 		)
@@ -626,6 +649,8 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 			return MySQLWhereRequirement, nil
 		case db.Postgres:
 			return PostgreSQLWhereRequirement, nil
+		case db.Snowflake:
+			return SnowflakeWhereRequirement, nil
 		}
 	case SchemaRuleStatementNoLeadingWildcardLike:
 		switch engine {
@@ -640,6 +665,8 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 			return MySQLNoSelectAll, nil
 		case db.Postgres:
 			return PostgreSQLNoSelectAll, nil
+		case db.Snowflake:
+			return SnowflakeNoSelectAll, nil
 		}
 	case SchemaRuleSchemaBackwardCompatibility:
 		switch engine {
@@ -654,6 +681,8 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 			return MySQLNamingTableConvention, nil
 		case db.Postgres:
 			return PostgreSQLNamingTableConvention, nil
+		case db.Snowflake:
+			return SnowflakeNamingTableConvention, nil
 		}
 	case SchemaRuleIDXNaming:
 		switch engine {
@@ -804,6 +833,18 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		if engine == db.MySQL {
 			return MySQLUseInnoDB, nil
 		}
+	case SchemaRuleClickHouseEngine:
+		if engine == db.ClickHouse {
+			return ClickHouseTableRequireEngine, nil
+		}
+	case SchemaRuleStatementDisallowMutation:
+		if engine == db.ClickHouse {
+			return ClickHouseDisallowMutation, nil
+		}
+	case SchemaRuleStatementOnClusterConsistency:
+		if engine == db.ClickHouse {
+			return ClickHouseOnClusterConsistency, nil
+		}
 	case SchemaRuleDropEmptyDatabase:
 		switch engine {
 		case db.MySQL, db.TiDB:
@@ -892,11 +933,22 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		switch engine {
 		case db.MySQL, db.TiDB:
 			return MySQLStatementAffectedRowLimit, nil
+		case db.Postgres:
+			return PostgreSQLStatementAffectedRowLimit, nil
 		}
 	case SchemaRuleStatementDMLDryRun:
 		switch engine {
 		case db.MySQL, db.TiDB:
 			return MySQLStatementDMLDryRun, nil
+		case db.Postgres:
+			return PostgreSQLStatementDMLDryRun, nil
+		}
+	case SchemaRuleCustom:
+		switch engine {
+		case db.MySQL, db.TiDB:
+			return MySQLCustomRule, nil
+		case db.Postgres:
+			return PostgreSQLCustomRule, nil
 		}
 	}
 	return Fake, errors.Errorf("unknown SQL review rule type %v for %v", ruleType, engine)
@@ -69,6 +69,45 @@ var (
 			},
 		},
 	}
+	// MockClickHouseDatabase is the mock ClickHouse database for test.
+	MockClickHouseDatabase = &catalog.Database{
+		Name:   "test",
+		DbType: db.ClickHouse,
+		SchemaList: []*catalog.Schema{
+			{
+				TableList: []*catalog.Table{
+					{
+						Name:     MockTableName,
+						Engine:   "MergeTree",
+						RowCount: 1000000,
+						ColumnList: []*catalog.Column{
+							{Name: "id"},
+							{Name: "name"},
+						},
+					},
+				},
+			},
+		},
+	}
+	// MockSnowflakeDatabase is the mock Snowflake database for test.
+	MockSnowflakeDatabase = &catalog.Database{
+		Name:   "TEST",
+		DbType: db.Snowflake,
+		SchemaList: []*catalog.Schema{
+			{
+				Name: "PUBLIC",
+				TableList: []*catalog.Table{
+					{
+						Name: MockTableName,
+						ColumnList: []*catalog.Column{
+							{Name: "id"},
+							{Name: "name"},
+						},
+					},
+				},
+			},
+		},
+	}
 	// MockPostgreSQLDatabase is the mock PostgreSQL database for test.
 	MockPostgreSQLDatabase = &catalog.Database{
 		Name:   "test",
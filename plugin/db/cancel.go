@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"sync"
+)
+
+type cancelReasonContextKey struct{}
+
+type cancelReason struct {
+	sync.Mutex
+	reason string
+}
+
+// WithCancelReason returns a copy of parent with a new Done channel, and the cancel function recording why the context is canceled.
+// The drivers record the reason in the migration history canceled by the context, see CancelReason.
+func WithCancelReason(parent context.Context) (context.Context, func(reason string)) {
+	holder := &cancelReason{}
+	ctx, cancel := context.WithCancel(context.WithValue(parent, cancelReasonContextKey{}, holder))
+	return ctx, func(reason string) {
+		holder.Lock()
+		if holder.reason == "" {
+			holder.reason = reason
+		}
+		holder.Unlock()
+		cancel()
+	}
+}
+
+// CancelReason returns the reason of canceling the context created by WithCancelReason.
+// It returns empty if the context is not canceled.
+func CancelReason(ctx context.Context) string {
+	if ctx.Err() != context.Canceled {
+		return ""
+	}
+	holder, ok := ctx.Value(cancelReasonContextKey{}).(*cancelReason)
+	if !ok {
+		return "canceled"
+	}
+	holder.Lock()
+	defer holder.Unlock()
+	if holder.reason == "" {
+		return "canceled"
+	}
+	return holder.reason
+}
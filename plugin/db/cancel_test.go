@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelReason(t *testing.T) {
+	ctx, cancel := WithCancelReason(context.Background())
+	require.Equal(t, "", CancelReason(ctx))
+
+	cancel("canceled by Alice")
+	// The first reason wins.
+	cancel("canceled by Bob")
+	<-ctx.Done()
+	require.Equal(t, "canceled by Alice", CancelReason(ctx))
+
+	parent, parentCancel := context.WithCancel(context.Background())
+	ctx, _ = WithCancelReason(parent)
+	parentCancel()
+	require.Equal(t, "canceled", CancelReason(ctx))
+}
@@ -122,10 +122,16 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	}
 	defer tx.Rollback()
 
+	ctx, stop, err := util.KillOnCancel(ctx, driver.dbType, driver.db, tx)
+	if err != nil {
+		return err
+	}
+	// The stop is deferred for the early returns, and is called explicitly before committing the transaction.
+	defer stop()
+
+	var stmtList []string
 	f := func(stmt string) error {
-		if _, err := tx.ExecContext(ctx, stmt); err != nil {
-			return err
-		}
+		stmtList = append(stmtList, stmt)
 		return nil
 	}
 
@@ -133,6 +139,21 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 		return err
 	}
 
+	tracker := db.GetExecuteProgressTracker(ctx)
+	tracker.Start(len(stmtList))
+	logger := db.GetExecuteLogger(ctx)
+	for _, stmt := range stmtList {
+		tracker.StartStatement(stmt, 0)
+		logger.Logf(db.ExecuteLogLevelInfo, "Executing statement: %s", stmt)
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			logger.Logf(db.ExecuteLogLevelError, "Failed to execute statement: %v", err)
+			return err
+		}
+		// ClickHouse doesn't support transactions, so the statement takes effect immediately.
+		tracker.CompleteStatement(true)
+	}
+
+	stop()
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -141,6 +162,6 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.dbType, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, driver.dbType, driver.db, statement, queryContext)
 }
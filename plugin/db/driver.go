@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -215,6 +216,8 @@ const (
 // MigrationInfoPayload is the API message for migration info payload.
 type MigrationInfoPayload struct {
 	VCSPushEvent *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// CancelReason is the reason of canceling the migration, which is recorded along with the FAILED status.
+	CancelReason string `json:"cancelReason,omitempty"`
 }
 
 // MigrationInfo is the API message for migration info.
@@ -445,6 +448,15 @@ type ConnectionContext struct {
 	InstanceName    string
 }
 
+// QueryContext is the context to execute the readonly query.
+type QueryContext struct {
+	// Limit is the maximum row count returned. No limit enforced if limit <= 0.
+	Limit int
+	// Timeout is the max execution time of the query. No timeout enforced if timeout <= 0.
+	// The backend query is killed when the query is canceled or exceeds the timeout.
+	Timeout time.Duration
+}
+
 // Driver is the interface for database driver.
 type Driver interface {
 	// General execution
@@ -459,8 +471,7 @@ type Driver interface {
 	// will not use transactions to execute the statement but will still use transactions to execute the rest of statements.
 	Execute(ctx context.Context, statement string) error
 	// Used for execute readonly SELECT statement
-	// limit is the maximum row count returned. No limit enforced if limit <= 0
-	Query(ctx context.Context, statement string, limit int) ([]interface{}, error)
+	Query(ctx context.Context, statement string, queryContext *QueryContext) ([]interface{}, error)
 
 	// Sync schema
 	// SyncInstance syncs the instance metadata.
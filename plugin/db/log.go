@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+// maxExecuteLogMessageLength is the max length of the message recorded in the execute log.
+const maxExecuteLogMessageLength = 2048
+
+type executeLoggerContextKey struct{}
+
+// ExecuteLogLevel is the level of the execute log.
+type ExecuteLogLevel string
+
+const (
+	// ExecuteLogLevelInfo is the level of the informational logs, e.g. the statement starts and completes.
+	ExecuteLogLevelInfo ExecuteLogLevel = "INFO"
+	// ExecuteLogLevelWarn is the level of the warning logs, e.g. the warnings returned by the database.
+	ExecuteLogLevelWarn ExecuteLogLevel = "WARN"
+	// ExecuteLogLevelError is the level of the error logs.
+	ExecuteLogLevelError ExecuteLogLevel = "ERROR"
+)
+
+// ExecuteLogger records the logs of executing the statements, such as the statements started and completed, the affected rows and the warnings from the database.
+// The drivers get the logger by GetExecuteLogger, and all the methods are no-op on the nil logger.
+type ExecuteLogger struct {
+	write func(level ExecuteLogLevel, message string)
+}
+
+// NewExecuteLogger creates an execute logger writing the logs with write.
+func NewExecuteLogger(write func(level ExecuteLogLevel, message string)) *ExecuteLogger {
+	return &ExecuteLogger{write: write}
+}
+
+// WithExecuteLogger returns a copy of parent carrying the logger, which records the execute logs of the drivers.
+func WithExecuteLogger(parent context.Context, logger *ExecuteLogger) context.Context {
+	return context.WithValue(parent, executeLoggerContextKey{}, logger)
+}
+
+// GetExecuteLogger returns the logger carried by the context, or nil if there is none.
+func GetExecuteLogger(ctx context.Context) *ExecuteLogger {
+	logger, _ := ctx.Value(executeLoggerContextKey{}).(*ExecuteLogger)
+	return logger
+}
+
+// Logf records a log formatted with the arguments.
+func (l *ExecuteLogger) Logf(level ExecuteLogLevel, format string, args ...interface{}) {
+	if l == nil || l.write == nil {
+		return
+	}
+	message := truncateOnRuneBoundary(fmt.Sprintf(format, args...), maxExecuteLogMessageLength)
+	l.write(level, message)
+}
+
+// truncateOnRuneBoundary truncates the string longer than the max length in bytes with the "..." suffix.
+// It truncates on the rune boundary, so that we never split a multi-byte character.
+func truncateOnRuneBoundary(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+	n := maxLength
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
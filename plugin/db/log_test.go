@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteLogger(t *testing.T) {
+	// The nil logger is no-op.
+	logger := GetExecuteLogger(context.Background())
+	require.Nil(t, logger)
+	logger.Logf(ExecuteLogLevelInfo, "Executing statement at line %d", 1)
+
+	type logLine struct {
+		level   ExecuteLogLevel
+		message string
+	}
+	var logList []logLine
+	logger = GetExecuteLogger(WithExecuteLogger(context.Background(), NewExecuteLogger(func(level ExecuteLogLevel, message string) {
+		logList = append(logList, logLine{level: level, message: message})
+	})))
+	require.NotNil(t, logger)
+	logger.Logf(ExecuteLogLevelInfo, "Executing statement at line %d", 1)
+	logger.Logf(ExecuteLogLevelWarn, "%s", strings.Repeat("a", maxExecuteLogMessageLength+1))
+	// The 3-byte character crossing the max length is dropped as a whole.
+	logger.Logf(ExecuteLogLevelInfo, "a%s", strings.Repeat("数", maxExecuteLogMessageLength/3+1))
+	require.Equal(t, []logLine{
+		{level: ExecuteLogLevelInfo, message: "Executing statement at line 1"},
+		{level: ExecuteLogLevelWarn, message: strings.Repeat("a", maxExecuteLogMessageLength) + "..."},
+		{level: ExecuteLogLevelInfo, message: "a" + strings.Repeat("数", maxExecuteLogMessageLength/3) + "..."},
+	}, logList)
+	for _, line := range logList {
+		require.True(t, utf8.ValidString(line.message))
+	}
+}
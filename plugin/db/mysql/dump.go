@@ -279,7 +279,7 @@ func dumpTxn(ctx context.Context, txn *sql.Tx, database string, out io.Writer, s
 			if !schemaOnly {
 				// Include db prefix if dumping multiple databases.
 				includeDbPrefix := len(dumpableDbNames) > 1
-				if err := exportTableData(txn, dbName, tbl.Name, includeDbPrefix, out); err != nil {
+				if err := exportTableData(ctx, txn, dbName, tbl.Name, includeDbPrefix, out); err != nil {
 					return err
 				}
 			}
@@ -519,9 +519,10 @@ func getViewColumns(txn *sql.Tx, dbName, tblName string) ([]string, error) {
 }
 
 // exportTableData gets the data of a table.
-func exportTableData(txn *sql.Tx, dbName, tblName string, includeDbPrefix bool, out io.Writer) error {
+func exportTableData(ctx context.Context, txn *sql.Tx, dbName, tblName string, includeDbPrefix bool, out io.Writer) error {
 	query := fmt.Sprintf("SELECT * FROM `%s`.`%s`;", dbName, tblName)
-	rows, err := txn.Query(query)
+	// The table data could be large, so we use the context to abort the dump.
+	rows, err := txn.QueryContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
@@ -13,12 +15,21 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
+	"github.com/bytebase/bytebase/plugin/parser"
 )
 
 var (
 	baseTableType = "BASE TABLE"
 	viewTableType = "VIEW"
 
+	delimiterRegexp = regexp.MustCompile(`(?im)^\s*DELIMITER\s+`)
+	// leadingCommentPattern matches the whitespaces and the non-executable comments before the statement.
+	leadingCommentPattern = `(?is)^(\s|--[^\n]*\n|#[^\n]*\n|/\*[^!].*?\*/)*`
+	// implicitCommitRegexp matches the DDL and account management statements.
+	implicitCommitRegexp = regexp.MustCompile(leadingCommentPattern + `(CREATE|ALTER|DROP|RENAME|TRUNCATE|GRANT|REVOKE|LOCK|UNLOCK|ANALYZE|OPTIMIZE|REPAIR|SET\s+PASSWORD)\s`)
+	// temporaryTableRegexp matches the temporary table statements, which don't commit the transaction.
+	temporaryTableRegexp = regexp.MustCompile(leadingCommentPattern + `(CREATE|DROP)\s+TEMPORARY\s`)
+
 	_ db.Driver = (*Driver)(nil)
 )
 
@@ -154,18 +165,135 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, statement)
+	ctx, stop, err := util.KillOnCancel(ctx, driver.dbType, driver.db, tx)
+	if err != nil {
+		return err
+	}
+	// The stop is deferred for the early returns, and is called explicitly before committing the transaction.
+	defer stop()
 
-	if err == nil {
-		if err := tx.Commit(); err != nil {
+	tracker := db.GetExecuteProgressTracker(ctx)
+	if tracker == nil {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
 			return err
 		}
+		stop()
+		return tx.Commit()
+	}
+
+	// Execute the statements one by one to report the progress.
+	singleSQLList, err := driver.getExecuteStatementList(statement)
+	if err != nil {
+		return err
+	}
+	logger := db.GetExecuteLogger(ctx)
+	tracker.Start(len(singleSQLList))
+	for _, singleSQL := range singleSQLList {
+		tracker.StartStatement(singleSQL.Text, singleSQL.LastLine)
+		logger.Logf(db.ExecuteLogLevelInfo, "Executing statement at line %d: %s", singleSQL.LastLine, singleSQL.Text)
+		startTime := time.Now()
+		sqlResult, err := tx.ExecContext(ctx, singleSQL.Text)
+		if err != nil {
+			logger.Logf(db.ExecuteLogLevelError, "Failed to execute statement at line %d: %v", singleSQL.LastLine, err)
+			return err
+		}
+		if logger != nil {
+			rowsAffected, err := sqlResult.RowsAffected()
+			if err != nil {
+				return err
+			}
+			logger.Logf(db.ExecuteLogLevelInfo, "Completed statement at line %d in %s, %d rows affected", singleSQL.LastLine, time.Since(startTime), rowsAffected)
+			if err := logWarnings(ctx, tx, logger); err != nil {
+				return err
+			}
+		}
+		tracker.CompleteStatement(isImplicitCommitStatement(singleSQL.Text))
+	}
+	stop()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	tracker.Commit()
+	return nil
+}
+
+// logWarnings records the warnings of the last statement executed in the transaction.
+func logWarnings(ctx context.Context, tx *sql.Tx, logger *db.ExecuteLogger) error {
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
+	for rows.Next() {
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return err
+		}
+		logger.Logf(db.ExecuteLogLevelWarn, "%s %d: %s", level, code, message)
+	}
+	return rows.Err()
+}
+
+// getExecuteStatementList splits the statement into the statements executed one by one.
+// The statement containing DELIMITER is executed as a whole, because DELIMITER is a client command that can't be split into the statements.
+func (driver *Driver) getExecuteStatementList(statement string) ([]parser.SingleSQL, error) {
+	if delimiterRegexp.MatchString(statement) {
+		return []parser.SingleSQL{{Text: statement, LastLine: strings.Count(statement, "\n") + 1}}, nil
+	}
+	engine := parser.MySQL
+	if driver.dbType == db.TiDB {
+		engine = parser.TiDB
+	}
+	singleSQLList, err := parser.SplitMultiSQL(engine, statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to split statement")
+	}
+	var statementList []parser.SingleSQL
+	for _, singleSQL := range singleSQLList {
+		// MySQL returns an error for the empty query, e.g. the trailing comments.
+		if isEmptyStatement(singleSQL.Text) {
+			continue
+		}
+		statementList = append(statementList, singleSQL)
+	}
+	return statementList, nil
+}
+
+// isEmptyStatement returns true if the statement only contains the comments and the delimiters.
+// The executable comments such as /*!40101 SET NAMES utf8 */ are not empty.
+func isEmptyStatement(statement string) bool {
+	s := statement
+	for {
+		s = strings.TrimLeft(s, " \t\r\n;")
+		switch {
+		case s == "":
+			return true
+		case strings.HasPrefix(s, "--") || strings.HasPrefix(s, "#"):
+			i := strings.Index(s, "\n")
+			if i < 0 {
+				return true
+			}
+			s = s[i+1:]
+		case strings.HasPrefix(s, "/*") && !strings.HasPrefix(s, "/*!"):
+			i := strings.Index(s, "*/")
+			if i < 0 {
+				return true
+			}
+			s = s[i+2:]
+		default:
+			return false
+		}
+	}
+}
 
-	return err
+// isImplicitCommitStatement returns true if the statement commits the current transaction implicitly.
+// https://dev.mysql.com/doc/refman/8.0/en/implicit-commit.html
+func isImplicitCommitStatement(statement string) bool {
+	return implicitCommitRegexp.MatchString(statement) && !temporaryTableRegexp.MatchString(statement)
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.dbType, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, driver.dbType, driver.db, statement, queryContext)
 }
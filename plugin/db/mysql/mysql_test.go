@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetExecuteStatementList(t *testing.T) {
+	driver := &Driver{dbType: db.MySQL}
+	statementList, err := driver.getExecuteStatementList("-- create table\nCREATE TABLE t(a int);\n/*!40101 SET NAMES utf8 */;\nINSERT INTO t VALUES (1);\n-- trailing comment\n")
+	require.NoError(t, err)
+	var textList []string
+	var lineList []int
+	for _, statement := range statementList {
+		textList = append(textList, statement.Text)
+		lineList = append(lineList, statement.LastLine)
+	}
+	require.Equal(t, []string{"-- create table\nCREATE TABLE t(a int);", "/*!40101 SET NAMES utf8 */;", "INSERT INTO t VALUES (1);"}, textList)
+	require.Equal(t, []int{2, 3, 4}, lineList)
+
+	// The statement containing DELIMITER is executed as a whole.
+	statement := "DELIMITER ;;\nCREATE TRIGGER t1 BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; END;;\nDELIMITER ;\n"
+	statementList, err = driver.getExecuteStatementList(statement)
+	require.NoError(t, err)
+	require.Len(t, statementList, 1)
+	require.Equal(t, statement, statementList[0].Text)
+}
+
+func TestIsImplicitCommitStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      bool
+	}{
+		{"CREATE TABLE t(a int);", true},
+		{"-- comment\n/* comment */ alter table t add column b int;", true},
+		{"DROP TABLE t;", true},
+		{"CREATE TEMPORARY TABLE t(a int);", false},
+		{"INSERT INTO t VALUES (1);", false},
+		{"UPDATE t SET a = 'CREATE ';", false},
+		{"/*!40101 SET NAMES utf8 */;", false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, isImplicitCommitStatement(test.statement), test.statement)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	// Import pg driver.
 	// init() in pgx/v4/stdlib will register it's pgx driver.
@@ -225,11 +226,22 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 		return err
 	}
 
-	var remainingStmts []string
-	f := func(stmt string) error {
+	singleSQLList, err := parser.SplitMultiSQL(parser.Postgres, statement)
+	if err != nil {
+		return err
+	}
+	tracker := db.GetExecuteProgressTracker(ctx)
+	tracker.Start(len(singleSQLList))
+	logger := db.GetExecuteLogger(ctx)
+
+	var remainingStmts []parser.SingleSQL
+	for _, singleSQL := range singleSQLList {
+		stmt := singleSQL.Text
 		// We don't use transaction for creating / altering databases in Postgres.
 		// https://github.com/bytebase/bytebase/issues/202
 		if strings.HasPrefix(stmt, "CREATE DATABASE ") {
+			tracker.StartStatement(stmt, singleSQL.LastLine)
+			logger.Logf(db.ExecuteLogLevelInfo, "Executing statement at line %d: %s", singleSQL.LastLine, stmt)
 			databases, err := driver.getDatabases(ctx)
 			if err != nil {
 				return err
@@ -251,11 +263,17 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 					return err
 				}
 			}
+			tracker.CompleteStatement(true)
 		} else if strings.HasPrefix(stmt, "GRANT") || strings.HasPrefix(stmt, "ALTER DATABASE") && strings.Contains(stmt, " OWNER TO ") {
+			tracker.StartStatement(stmt, singleSQL.LastLine)
+			logger.Logf(db.ExecuteLogLevelInfo, "Executing statement at line %d: %s", singleSQL.LastLine, stmt)
 			if _, err := driver.db.ExecContext(ctx, stmt); err != nil {
 				return err
 			}
+			tracker.CompleteStatement(true)
 		} else if strings.HasPrefix(stmt, "\\connect ") {
+			tracker.StartStatement(stmt, singleSQL.LastLine)
+			logger.Logf(db.ExecuteLogLevelInfo, "Executing statement at line %d: %s", singleSQL.LastLine, stmt)
 			// For the case of `\connect "dbname";`, we need to use GetDBConnection() instead of executing the statement.
 			parts := strings.Split(stmt, `"`)
 			if len(parts) != 3 {
@@ -268,6 +286,7 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 			if owner, err = driver.GetCurrentDatabaseOwner(); err != nil {
 				return err
 			}
+			tracker.CompleteStatement(true)
 		} else if isSuperuserStatement(stmt) {
 			// CREATE EVENT TRIGGER statement only supports EXECUTE PROCEDURE in version 10 and before, while newer version supports both EXECUTE { FUNCTION | PROCEDURE }.
 			// Since we use pg_dump version 14, the dump uses a new style even for an old version of PostgreSQL.
@@ -278,15 +297,10 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 			}
 			// Use superuser privilege to run privileged statements.
 			stmt = fmt.Sprintf("SET LOCAL ROLE NONE;%sSET LOCAL ROLE %s;", stmt, owner)
-			remainingStmts = append(remainingStmts, stmt)
+			remainingStmts = append(remainingStmts, parser.SingleSQL{Text: stmt, LastLine: singleSQL.LastLine})
 		} else {
-			remainingStmts = append(remainingStmts, stmt)
+			remainingStmts = append(remainingStmts, singleSQL)
 		}
-		return nil
-	}
-
-	if _, err := parser.SplitMultiSQLStream(parser.Postgres, strings.NewReader(statement), f); err != nil {
-		return err
 	}
 
 	if len(remainingStmts) == 0 {
@@ -299,16 +313,56 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 	}
 	defer tx.Rollback()
 
+	ctx, stop, err := util.KillOnCancel(ctx, db.Postgres, driver.db, tx)
+	if err != nil {
+		return err
+	}
+	// The stop is deferred for the early returns, and is called explicitly before committing the transaction.
+	defer stop()
+
 	// Set the current transaction role to the database owner so that the owner of created database will be the same as the database owner.
 	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL ROLE %s", owner)); err != nil {
 		return err
 	}
 
-	if _, err := tx.ExecContext(ctx, strings.Join(remainingStmts, "\n")); err != nil {
+	if tracker == nil {
+		var stmtList []string
+		for _, stmt := range remainingStmts {
+			stmtList = append(stmtList, stmt.Text)
+		}
+		if _, err := tx.ExecContext(ctx, strings.Join(stmtList, "\n")); err != nil {
+			return err
+		}
+		stop()
+		return tx.Commit()
+	}
+
+	// Execute the statements one by one to report the progress.
+	// The statements are rolled back together if any of them fails, because Postgres supports transactional DDL.
+	for _, stmt := range remainingStmts {
+		tracker.StartStatement(stmt.Text, stmt.LastLine)
+		logger.Logf(db.ExecuteLogLevelInfo, "Executing statement at line %d: %s", stmt.LastLine, stmt.Text)
+		startTime := time.Now()
+		sqlResult, err := tx.ExecContext(ctx, stmt.Text)
+		if err != nil {
+			logger.Logf(db.ExecuteLogLevelError, "Failed to execute statement at line %d: %v", stmt.LastLine, err)
+			return err
+		}
+		if logger != nil {
+			rowsAffected, err := sqlResult.RowsAffected()
+			if err != nil {
+				return err
+			}
+			logger.Logf(db.ExecuteLogLevelInfo, "Completed statement at line %d in %s, %d rows affected", stmt.LastLine, time.Since(startTime), rowsAffected)
+		}
+		tracker.CompleteStatement(false)
+	}
+	stop()
+	if err := tx.Commit(); err != nil {
 		return err
 	}
-
-	return tx.Commit()
+	tracker.Commit()
+	return nil
 }
 
 func isSuperuserStatement(stmt string) bool {
@@ -365,8 +419,8 @@ func (driver *Driver) GetCurrentDatabaseOwner() (string, error) {
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, db.Postgres, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, db.Postgres, driver.db, statement, queryContext)
 }
 
 func (driver *Driver) switchDatabase(dbName string) error {
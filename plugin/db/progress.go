@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxProgressStatementLength is the max length of the statement recorded in the execute progress.
+const maxProgressStatementLength = 1024
+
+type executeProgressContextKey struct{}
+
+// ExecuteProgress is the progress of executing the statements by Driver.Execute.
+type ExecuteProgress struct {
+	// TotalStatementCount is the count of the statements to execute.
+	TotalStatementCount int `json:"totalStatementCount"`
+	// CompletedStatementCount is the count of the statements executed successfully.
+	CompletedStatementCount int `json:"completedStatementCount"`
+	// CommittedStatementCount is the count of the completed statements committed to the database,
+	// the rest of the completed statements are rolled back if the execution fails.
+	CommittedStatementCount int `json:"committedStatementCount"`
+	// CurrentStatement is the statement being executed, or the failed statement if the execution fails.
+	CurrentStatement string `json:"currentStatement,omitempty"`
+	// CurrentStatementLine is the last line of the current statement in the executed SQL.
+	CurrentStatementLine int `json:"currentStatementLine,omitempty"`
+	// CurrentStatementStartTs is when the current statement starts in milliseconds.
+	CurrentStatementStartTs int64 `json:"currentStatementStartTs,omitempty"`
+	// StartTs is when the execution starts in milliseconds.
+	StartTs int64 `json:"startTs"`
+	// UpdatedTs is when the progress is updated most recently in milliseconds.
+	UpdatedTs int64 `json:"updatedTs"`
+}
+
+// ExecuteProgressTracker tracks the progress of executing the statements.
+// The drivers get the tracker by GetExecuteProgressTracker, and all the methods are no-op on the nil tracker.
+type ExecuteProgressTracker struct {
+	sync.Mutex
+	progress ExecuteProgress
+}
+
+// WithExecuteProgressTracker returns a copy of parent carrying the tracker, which records the execute progress of the drivers.
+func WithExecuteProgressTracker(parent context.Context, tracker *ExecuteProgressTracker) context.Context {
+	return context.WithValue(parent, executeProgressContextKey{}, tracker)
+}
+
+// GetExecuteProgressTracker returns the tracker carried by the context, or nil if there is none.
+func GetExecuteProgressTracker(ctx context.Context) *ExecuteProgressTracker {
+	tracker, _ := ctx.Value(executeProgressContextKey{}).(*ExecuteProgressTracker)
+	return tracker
+}
+
+// Start starts tracking the execution of totalStatementCount statements.
+func (t *ExecuteProgressTracker) Start(totalStatementCount int) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	now := time.Now().UnixMilli()
+	t.progress = ExecuteProgress{
+		TotalStatementCount: totalStatementCount,
+		StartTs:             now,
+		UpdatedTs:           now,
+	}
+}
+
+// StartStatement records the statement starting to execute.
+func (t *ExecuteProgressTracker) StartStatement(statement string, line int) {
+	if t == nil {
+		return
+	}
+	statement = truncateOnRuneBoundary(statement, maxProgressStatementLength)
+	t.Lock()
+	defer t.Unlock()
+	now := time.Now().UnixMilli()
+	t.progress.CurrentStatement = statement
+	t.progress.CurrentStatementLine = line
+	t.progress.CurrentStatementStartTs = now
+	t.progress.UpdatedTs = now
+}
+
+// CompleteStatement records the current statement executed successfully.
+// The statement is committed if it's executed in the auto-commit mode or it commits the transaction implicitly.
+func (t *ExecuteProgressTracker) CompleteStatement(committed bool) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.progress.CompletedStatementCount++
+	if committed {
+		t.progress.CommittedStatementCount = t.progress.CompletedStatementCount
+	}
+	t.progress.UpdatedTs = time.Now().UnixMilli()
+}
+
+// Commit records all the completed statements committed.
+func (t *ExecuteProgressTracker) Commit() {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.progress.CommittedStatementCount = t.progress.CompletedStatementCount
+	t.progress.UpdatedTs = time.Now().UnixMilli()
+}
+
+// GetProgress returns the snapshot of the progress.
+func (t *ExecuteProgressTracker) GetProgress() ExecuteProgress {
+	if t == nil {
+		return ExecuteProgress{}
+	}
+	t.Lock()
+	defer t.Unlock()
+	return t.progress
+}
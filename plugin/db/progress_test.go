@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteProgressTracker(t *testing.T) {
+	// The nil tracker is no-op.
+	tracker := GetExecuteProgressTracker(context.Background())
+	require.Nil(t, tracker)
+	tracker.Start(1)
+	tracker.StartStatement("SELECT 1", 1)
+	tracker.CompleteStatement(true)
+	require.Equal(t, ExecuteProgress{}, tracker.GetProgress())
+
+	tracker = GetExecuteProgressTracker(WithExecuteProgressTracker(context.Background(), &ExecuteProgressTracker{}))
+	require.NotNil(t, tracker)
+	tracker.Start(3)
+	tracker.StartStatement("CREATE TABLE t(a int);", 1)
+	tracker.CompleteStatement(true)
+	tracker.StartStatement("INSERT INTO t VALUES (1);", 2)
+	tracker.CompleteStatement(false)
+	tracker.StartStatement(strings.Repeat("a", maxProgressStatementLength+1), 3)
+	progress := tracker.GetProgress()
+	require.Equal(t, 3, progress.TotalStatementCount)
+	require.Equal(t, 2, progress.CompletedStatementCount)
+	require.Equal(t, 1, progress.CommittedStatementCount)
+	require.Equal(t, 3, progress.CurrentStatementLine)
+	require.Equal(t, strings.Repeat("a", maxProgressStatementLength)+"...", progress.CurrentStatement)
+	// The 3-byte character crossing the max length is dropped as a whole.
+	tracker.StartStatement("ab"+strings.Repeat("数", maxProgressStatementLength/3), 3)
+	progress = tracker.GetProgress()
+	require.Equal(t, "ab"+strings.Repeat("数", (maxProgressStatementLength-2)/3)+"...", progress.CurrentStatement)
+	require.True(t, utf8.ValidString(progress.CurrentStatement))
+
+	tracker.CompleteStatement(false)
+	tracker.Commit()
+	progress = tracker.GetProgress()
+	require.Equal(t, 3, progress.CompletedStatementCount)
+	require.Equal(t, 3, progress.CommittedStatementCount)
+}
@@ -208,17 +208,18 @@ func (Driver) UpdateHistoryAsDone(ctx context.Context, tx *sql.Tx, migrationDura
 	return err
 }
 
-// UpdateHistoryAsFailed will update the migration record as failed.
-func (Driver) UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, insertedID int64) error {
+// UpdateHistoryAsFailed will update the migration record as failed with the payload.
+func (Driver) UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, payload string, insertedID int64) error {
 	const updateHistoryAsFailedQuery = `
 		UPDATE
 			bytebase.public.migration_history
 		SET
 			status = ?,
-			execution_duration_ns = ?
+			execution_duration_ns = ?,
+			payload = ?
 		WHERE id = ?
 	`
-	_, err := tx.ExecContext(ctx, updateHistoryAsFailedQuery, db.Failed, migrationDurationNs, insertedID)
+	_, err := tx.ExecContext(ctx, updateHistoryAsFailedQuery, db.Failed, migrationDurationNs, payload, insertedID)
 	return err
 }
 
@@ -93,7 +93,7 @@ func (driver *Driver) dumpOneDatabase(ctx context.Context, database string, out
 
 		// Dump table data.
 		if !schemaOnly && s.schemaType == "table" {
-			if err := exportTableData(txn, s.name, out); err != nil {
+			if err := exportTableData(ctx, txn, s.name, out); err != nil {
 				return err
 			}
 		}
@@ -103,9 +103,9 @@ func (driver *Driver) dumpOneDatabase(ctx context.Context, database string, out
 }
 
 // exportTableData gets the data of a table.
-func exportTableData(txn *sql.Tx, tblName string, out io.Writer) error {
+func exportTableData(ctx context.Context, txn *sql.Tx, tblName string, out io.Writer) error {
 	query := fmt.Sprintf("SELECT * FROM `%s`;", tblName)
-	rows, err := txn.Query(query)
+	rows, err := txn.QueryContext(ctx, query)
 	if err != nil {
 		return err
 	}
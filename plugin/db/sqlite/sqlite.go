@@ -171,6 +171,6 @@ func (driver *Driver) Execute(ctx context.Context, statement string) error {
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, db.SQLite, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, db.SQLite, driver.db, statement, queryContext)
 }
@@ -6,13 +6,17 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/blang/semver/v4"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
@@ -21,6 +25,9 @@ import (
 	"github.com/bytebase/bytebase/plugin/db"
 )
 
+// endMigrationTimeout is the timeout to end the canceled migration.
+const endMigrationTimeout = 10 * time.Second
+
 // FormatErrorWithQuery will format the error with failed query.
 func FormatErrorWithQuery(err error, query string) error {
 	return common.Wrapf(err, common.DbExecutionError, "failed to execute query %q", query)
@@ -137,8 +144,8 @@ type MigrationExecutor interface {
 	InsertPendingHistory(ctx context.Context, tx *sql.Tx, sequence int, prevSchema string, m *db.MigrationInfo, storedVersion, statement string) (insertedID int64, err error)
 	// UpdateHistoryAsDone will update the migration record as done.
 	UpdateHistoryAsDone(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, updatedSchema string, insertedID int64) error
-	// UpdateHistoryAsFailed will update the migration record as failed.
-	UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, insertedID int64) error
+	// UpdateHistoryAsFailed will update the migration record as failed with the payload.
+	UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, payload string, insertedID int64) error
 }
 
 // ExecuteMigration will execute the database migration.
@@ -167,12 +174,7 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 	startedNs := time.Now().UnixNano()
 
 	defer func() {
-		if err := EndMigration(ctx, executor, startedNs, insertedID, updatedSchema, databaseName, resErr == nil /*isDone*/); err != nil {
-			log.Error("Failed to update migration history record",
-				zap.Error(err),
-				zap.Int64("migration_id", migrationHistoryID),
-			)
-		}
+		resErr = FinishMigration(ctx, executor, m, startedNs, insertedID, updatedSchema, databaseName, resErr)
 	}()
 
 	// Phase 3 - Executing migration
@@ -287,8 +289,54 @@ func BeginMigration(ctx context.Context, executor MigrationExecutor, m *db.Migra
 	return insertedID, nil
 }
 
+// FinishMigration ends the migration started by BeginMigration, and returns the migration error.
+// The migration history must leave the PENDING status even if the migration is canceled, so we use a new context to end it,
+// and the cancel reason is recorded along with the FAILED status.
+func FinishMigration(ctx context.Context, executor MigrationExecutor, m *db.MigrationInfo, startedNs int64, migrationHistoryID int64, updatedSchema string, databaseName string, migrationErr error) error {
+	endCtx := ctx
+	payload := m.Payload
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		endCtx, cancel = context.WithTimeout(context.Background(), endMigrationTimeout)
+		defer cancel()
+		if reason := db.CancelReason(ctx); reason != "" && migrationErr != nil {
+			p, err := getCanceledMigrationPayload(m.Payload, reason)
+			if err != nil {
+				log.Error("Failed to record the migration cancel reason", zap.Error(err), zap.Int64("migration_id", migrationHistoryID))
+			} else {
+				payload = p
+			}
+			migrationErr = errors.Wrapf(migrationErr, "migration is canceled: %s", reason)
+		}
+	}
+	if err := EndMigration(endCtx, executor, startedNs, migrationHistoryID, updatedSchema, payload, databaseName, migrationErr == nil /*isDone*/); err != nil {
+		log.Error("Failed to update migration history record",
+			zap.Error(err),
+			zap.Int64("migration_id", migrationHistoryID),
+		)
+	}
+	return migrationErr
+}
+
+// getCanceledMigrationPayload returns the migration history payload with the cancel reason.
+func getCanceledMigrationPayload(payload string, reason string) (string, error) {
+	miPayload := &db.MigrationInfoPayload{}
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), miPayload); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal migration payload %q", payload)
+		}
+	}
+	miPayload.CancelReason = reason
+	payloadBytes, err := json.Marshal(miPayload)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal migration payload")
+	}
+	return string(payloadBytes), nil
+}
+
 // EndMigration updates the migration history record to DONE or FAILED depending on migration is done or not.
-func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int64, migrationHistoryID int64, updatedSchema string, databaseName string, isDone bool) (err error) {
+// The payload is recorded for the FAILED migration, e.g. the cancel reason.
+func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int64, migrationHistoryID int64, updatedSchema string, payload string, databaseName string, isDone bool) (err error) {
 	migrationDurationNs := time.Now().UnixNano() - startedNs
 
 	sqldb, err := executor.GetDBConnection(ctx, databaseName)
@@ -306,7 +354,7 @@ func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int
 		err = executor.UpdateHistoryAsDone(ctx, tx, migrationDurationNs, updatedSchema, migrationHistoryID)
 	} else {
 		// Otherwise, update the migration history as 'FAILED', execution_duration.
-		err = executor.UpdateHistoryAsFailed(ctx, tx, migrationDurationNs, migrationHistoryID)
+		err = executor.UpdateHistoryAsFailed(ctx, tx, migrationDurationNs, payload, migrationHistoryID)
 	}
 
 	if err != nil {
@@ -317,15 +365,87 @@ func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int
 }
 
 // Query will execute a readonly / SELECT query.
-func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string, limit int) ([]interface{}, error) {
-	// Limit SQL query result size.
-	if dbType == db.MySQL {
-		// MySQL 5.7 doesn't support WITH clause.
-		statement = getMySQLStatementWithResultLimit(statement, limit)
-	} else {
-		statement = getStatementWithResultLimit(statement, limit)
+func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	tx, err := beginReadOnlyTx(ctx, dbType, sqldb)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return queryTx(ctx, dbType, sqldb, tx, getStatementWithResultLimitByType(dbType, statement, queryContext.Limit), queryContext)
+}
+
+// StatementResult is the result of a statement in the multi-statement query.
+type StatementResult struct {
+	// RowSet is the same as the one returned by Query.
+	RowSet     []interface{}
+	Error      error
+	DurationNs int64
+}
+
+// QueryMultiStatements will execute the readonly statements sequentially in one session, and return the result of each statement.
+// The statements are run in one transaction which is always rolled back, so that the session variables and temporary tables
+// are visible to the following statements. The timeout of the query context applies to each statement.
+// It stops at the first failed statement, whose error is in the last result. The returned error is only for failing to start the session.
+func QueryMultiStatements(ctx context.Context, dbType db.Type, sqldb *sql.DB, statementList []string, queryContext *db.QueryContext) ([]*StatementResult, error) {
+	tx, err := beginReadOnlyTx(ctx, dbType, sqldb)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var resultList []*StatementResult
+	for _, statement := range statementList {
+		// Only limit the SELECT statements, because the statements such as SET cannot be wrapped as the subquery.
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(statement)), "SELECT") {
+			statement = getStatementWithResultLimitByType(dbType, statement, queryContext.Limit)
+		}
+		start := time.Now()
+		rowSet, err := queryTx(ctx, dbType, sqldb, tx, statement, queryContext)
+		resultList = append(resultList, &StatementResult{
+			RowSet:     rowSet,
+			Error:      err,
+			DurationNs: time.Since(start).Nanoseconds(),
+		})
+		if err != nil {
+			break
+		}
+	}
+	return resultList, nil
+}
+
+// QueryStream will execute a readonly / SELECT query and stream the result rows without buffering the whole result.
+// The columnFunc is called with the column names and types before the rowFunc is called for each row.
+func QueryStream(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string, queryContext *db.QueryContext, columnFunc func(columnNames []string, columnTypeNames []string) error, rowFunc func(row []interface{}) error) error {
+	tx, err := beginReadOnlyTx(ctx, dbType, sqldb)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return queryStreamTx(ctx, dbType, sqldb, tx, getStatementWithResultLimitByType(dbType, statement, queryContext.Limit), queryContext, columnFunc, rowFunc)
+}
+
+func queryTx(ctx context.Context, dbType db.Type, sqldb *sql.DB, tx *sql.Tx, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	var columnNames, columnTypeNames []string
+	data := []interface{}{}
+	if err := queryStreamTx(ctx, dbType, sqldb, tx, statement, queryContext,
+		func(names []string, typeNames []string) error {
+			columnNames, columnTypeNames = names, typeNames
+			return nil
+		},
+		func(row []interface{}) error {
+			data = append(data, row)
+			return nil
+		},
+	); err != nil {
+		return nil, err
 	}
 
+	return []interface{}{columnNames, columnTypeNames, data}, nil
+}
+
+func beginReadOnlyTx(ctx context.Context, dbType db.Type, sqldb *sql.DB) (*sql.Tx, error) {
 	// Not all sql engines support ReadOnly flag, so we will use tx rollback semantics to enforce readonly.
 	readOnly := true
 	// TiDB doesn't support READ ONLY transactions. We have to skip the flag for it.
@@ -334,26 +454,39 @@ func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string,
 	if dbType == db.TiDB || dbType == db.ClickHouse {
 		readOnly = false
 	}
-	tx, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
+	return sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
+}
+
+// queryStreamTx executes the statement in the transaction, the statement is killed on the timeout or the context cancellation.
+func queryStreamTx(ctx context.Context, dbType db.Type, sqldb *sql.DB, tx *sql.Tx, statement string, queryContext *db.QueryContext, columnFunc func(columnNames []string, columnTypeNames []string) error, rowFunc func(row []interface{}) error) error {
+	if queryContext.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, queryContext.Timeout)
+		defer cancel()
+	}
+
+	ctx, backendQueryID, err := getBackendQueryID(ctx, dbType, tx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer tx.Rollback()
+	// The stop must be called before rolling back the transaction, so that we never kill the query on a connection returned to the pool.
+	stop := killQueryOnDone(ctx, dbType, sqldb, backendQueryID)
+	defer stop()
 
 	rows, err := tx.QueryContext(ctx, statement)
 	if err != nil {
-		return nil, FormatErrorWithQuery(err, statement)
+		return formatQueryError(ctx, err, statement, queryContext.Timeout)
 	}
 	defer rows.Close()
 
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return nil, FormatError(err)
+		return FormatError(err)
 	}
 
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		return nil, FormatError(err)
+		return FormatError(err)
 	}
 
 	colCount := len(columnTypes)
@@ -365,7 +498,10 @@ func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string,
 		columnTypeNames = append(columnTypeNames, strings.ToUpper(v.DatabaseTypeName()))
 	}
 
-	data := []interface{}{}
+	if err := columnFunc(columnNames, columnTypeNames); err != nil {
+		return err
+	}
+
 	for rows.Next() {
 		scanArgs := make([]interface{}, colCount)
 		for i, v := range columnTypeNames {
@@ -385,7 +521,7 @@ func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string,
 		}
 
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, FormatError(err)
+			return FormatError(err)
 		}
 
 		rowData := []interface{}{}
@@ -414,13 +550,125 @@ func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string,
 			rowData = append(rowData, nil)
 		}
 
-		data = append(data, rowData)
+		if err := rowFunc(rowData); err != nil {
+			return err
+		}
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return formatQueryError(ctx, err, statement, queryContext.Timeout)
 	}
+	return nil
+}
 
-	return []interface{}{columnNames, columnTypeNames, data}, nil
+func formatQueryError(ctx context.Context, err error, statement string, timeout time.Duration) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return common.Errorf(common.DbExecutionError, "query exceeds the max execution time %v", timeout)
+	case context.Canceled:
+		return common.Errorf(common.DbExecutionError, "query is canceled")
+	}
+	return FormatErrorWithQuery(err, statement)
+}
+
+// getBackendQueryID returns the ID to kill the query running in the transaction on the server side.
+// The returned context should be used to run the query, and the ID is empty if the engine doesn't support killing the query.
+func getBackendQueryID(ctx context.Context, dbType db.Type, tx *sql.Tx) (context.Context, string, error) {
+	var query string
+	switch dbType {
+	case db.MySQL, db.TiDB:
+		query = "SELECT CONNECTION_ID()"
+	case db.Postgres:
+		query = "SELECT pg_backend_pid()"
+	case db.ClickHouse:
+		// ClickHouse kills the query by the query ID instead of the connection.
+		queryID := uuid.New().String()
+		return clickhouse.Context(ctx, clickhouse.WithQueryID(queryID)), queryID, nil
+	default:
+		return ctx, "", nil
+	}
+	var id int64
+	if err := tx.QueryRowContext(ctx, query).Scan(&id); err != nil {
+		return nil, "", FormatErrorWithQuery(err, query)
+	}
+	return ctx, strconv.FormatInt(id, 10), nil
+}
+
+// KillOnCancel kills the statement running in the transaction on the server side when the context is done.
+// The returned context should be used to run the statement, and the returned stop function must be called before committing or rolling back the transaction,
+// so that we never kill a query on a connection returned to the pool. The stop function can be called more than once.
+// This is used to cancel the long-running statements, such as the migrations, because the database drivers only close the client connection on context done.
+func KillOnCancel(ctx context.Context, dbType db.Type, sqldb *sql.DB, tx *sql.Tx) (context.Context, func(), error) {
+	ctx, backendQueryID, err := getBackendQueryID(ctx, dbType, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctx, killQueryOnDone(ctx, dbType, sqldb, backendQueryID), nil
+}
+
+// killQueryOnDone kills the backend query when the context is done, e.g. the query is canceled or exceeds the timeout.
+// The database drivers only close the client connection on context done, and the server may keep running the query.
+// The returned stop function stops watching the context, and waits for the kill to finish. It's safe to call it more than once.
+func killQueryOnDone(ctx context.Context, dbType db.Type, sqldb *sql.DB, backendQueryID string) func() {
+	if backendQueryID == "" {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+		// Use a new context because the query context is done.
+		killCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := killQuery(killCtx, dbType, sqldb, backendQueryID); err != nil {
+			log.Warn("Failed to kill the backend query",
+				zap.String("type", string(dbType)),
+				zap.String("backendQueryID", backendQueryID),
+				zap.Error(err),
+			)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
+
+func killQuery(ctx context.Context, dbType db.Type, sqldb *sql.DB, backendQueryID string) error {
+	// The connection ID and the backend PID are integers, and the ClickHouse query ID is a UUID generated by us, so it's safe to format them in the query.
+	var query string
+	switch dbType {
+	case db.MySQL:
+		query = fmt.Sprintf("KILL QUERY %s", backendQueryID)
+	case db.TiDB:
+		query = fmt.Sprintf("KILL TIDB QUERY %s", backendQueryID)
+	case db.Postgres:
+		query = fmt.Sprintf("SELECT pg_cancel_backend(%s)", backendQueryID)
+	case db.ClickHouse:
+		query = fmt.Sprintf("KILL QUERY WHERE query_id = '%s'", backendQueryID)
+	default:
+		return errors.Errorf("killing query is not supported for engine %s", dbType)
+	}
+	if _, err := sqldb.ExecContext(ctx, query); err != nil {
+		return FormatErrorWithQuery(err, query)
+	}
+	return nil
+}
+
+// getStatementWithResultLimitByType limits the SQL query result size.
+func getStatementWithResultLimitByType(dbType db.Type, stmt string, limit int) string {
+	if dbType == db.MySQL {
+		// MySQL 5.7 doesn't support WITH clause.
+		return getMySQLStatementWithResultLimit(stmt, limit)
+	}
+	return getStatementWithResultLimit(stmt, limit)
 }
 
 func getStatementWithResultLimit(stmt string, limit int) string {
@@ -1,13 +1,17 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestToStoredVersion(t *testing.T) {
@@ -232,3 +236,49 @@ func generateOneMBInsert() string {
 	}
 	return fmt.Sprintf("INSERT INTO t values('%s')", string(b))
 }
+
+func TestFormatQueryError(t *testing.T) {
+	queryErr := errors.New("driver: bad connection")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.EqualError(t, formatQueryError(ctx, queryErr, "SELECT 1", 0), "query is canceled")
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	require.EqualError(t, formatQueryError(ctx, queryErr, "SELECT 1", 3*time.Second), "query exceeds the max execution time 3s")
+
+	require.Contains(t, formatQueryError(context.Background(), queryErr, "SELECT 1", 0).Error(), "driver: bad connection")
+}
+
+func TestKillQueryOnDoneStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The stop is called before committing the transaction, and again by the deferred call.
+	stop := killQueryOnDone(ctx, db.MySQL, nil /* sqldb */, "1")
+	stop()
+	require.NotPanics(t, stop)
+	// The query isn't killed after the stop, so the nil sqldb is never used.
+	cancel()
+}
+
+func TestGetCanceledMigrationPayload(t *testing.T) {
+	tests := []struct {
+		payload string
+		reason  string
+		want    string
+	}{
+		{"", "canceled by Alice", `{"cancelReason":"canceled by Alice"}`},
+		{"{}", "canceled", `{"cancelReason":"canceled"}`},
+		{`{"cancelReason":"old"}`, "canceled by Bob: too slow", `{"cancelReason":"canceled by Bob: too slow"}`},
+	}
+	for _, tc := range tests {
+		got, err := getCanceledMigrationPayload(tc.payload, tc.reason)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got)
+	}
+
+	_, err := getCanceledMigrationPayload("not json", "canceled")
+	require.Error(t, err)
+}
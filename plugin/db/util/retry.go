@@ -0,0 +1,105 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+)
+
+var (
+	// mysqlRetryableErrorNumbers are the MySQL error numbers caused by the transient server state.
+	// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+	mysqlRetryableErrorNumbers = map[uint16]bool{
+		// ER_LOCK_WAIT_TIMEOUT
+		1205: true,
+		// ER_LOCK_DEADLOCK
+		1213: true,
+		// ER_CON_COUNT_ERROR
+		1040: true,
+		// ER_SERVER_SHUTDOWN
+		1053: true,
+		// ER_QUERY_INTERRUPTED, e.g. killed by the DBA.
+		1317: true,
+		// ER_LOCK_NOWAIT
+		3572: true,
+		// TiDB ErrWriteConflict
+		9007: true,
+		// TiDB ErrTiKVServerBusy
+		9003: true,
+		// TiDB ErrRegionUnavailable
+		9005: true,
+	}
+	// postgresRetryableErrorCodes are the Postgres error codes caused by the transient server state.
+	// https://www.postgresql.org/docs/current/errcodes-appendix.html
+	postgresRetryableErrorCodes = map[string]bool{
+		// serialization_failure
+		"40001": true,
+		// deadlock_detected
+		"40P01": true,
+		// lock_not_available
+		"55P03": true,
+		// too_many_connections
+		"53300": true,
+		// admin_shutdown
+		"57P01": true,
+		// cannot_connect_now
+		"57P03": true,
+	}
+	// retryableErrorMessages are the messages of the retryable errors which may be flattened into strings by the drivers.
+	retryableErrorMessages = []string{
+		"connection reset by peer",
+		"connection refused",
+		"broken pipe",
+		"bad connection",
+		"lock wait timeout exceeded",
+		"deadlock found when trying to get lock",
+		"deadlock detected",
+	}
+)
+
+// IsRetryableError returns true if the error is caused by the transient failures, e.g. connection reset, lock wait timeout and deadlock,
+// so that the failed statements may succeed by retrying.
+// The canceled and timed out contexts are not retryable because they are caused by the users or the policies.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlRetryableErrorNumbers[mysqlErr.Number]
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return postgresRetryableErrorCodes[pgErr.Code]
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, retryableMessage := range retryableErrorMessages {
+		if strings.Contains(message, retryableMessage) {
+			return true
+		}
+	}
+	return false
+}
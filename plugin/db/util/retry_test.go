@@ -0,0 +1,39 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"syscall"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("syntax error"), false},
+		{context.Canceled, false},
+		{errors.Wrap(context.DeadlineExceeded, "failed to execute"), false},
+		{&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}, true},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, true},
+		{&mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}, false},
+		{FormatErrorWithQuery(&mysql.MySQLError{Number: 1213}, "UPDATE t SET a = 1"), true},
+		{&pgconn.PgError{Code: "40P01", Message: "deadlock detected"}, true},
+		{errors.Wrap(&pgconn.PgError{Code: "42P01", Message: "relation does not exist"}, "failed"), false},
+		{common.Wrap(driver.ErrBadConn, common.DbExecutionError), true},
+		{errors.Wrap(syscall.ECONNRESET, "read"), true},
+		{errors.New("read tcp 127.0.0.1:3306: connection reset by peer"), true},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.want, IsRetryableError(tc.err), "%v", tc.err)
+	}
+}
@@ -34,6 +34,8 @@ type SelectStmt struct {
 	// SELECT fields
 	FieldList   []ExpressionNode
 	WhereClause ExpressionNode
+	// TableList is the list of the tables in the FROM clause, including the joined ones but not the ones in subqueries.
+	TableList []*TableDef
 
 	// TODO(rebelice): support all expression and remove them.
 	// We define them because we cannot convert all expression now.
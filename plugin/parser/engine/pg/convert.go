@@ -587,7 +587,7 @@ func convertSelectStmt(in *pgquery.SelectStmt) (*ast.SelectStmt, error) {
 		selectStmt.FieldList = append(selectStmt.FieldList, convertedNode)
 	}
 	// Convert FROM clause
-	// Here we only find the SELECT stmt in FROM clause
+	// Here we only find the tables and the SELECT stmt in FROM clause
 	for _, item := range in.FromClause {
 		if node, ok := item.Node.(*pgquery.Node_RangeSubselect); ok {
 			subselect, err := convertRangeSubselect(node.RangeSubselect)
@@ -596,6 +596,7 @@ func convertSelectStmt(in *pgquery.SelectStmt) (*ast.SelectStmt, error) {
 			}
 			selectStmt.SubqueryList = append(selectStmt.SubqueryList, subselect)
 		}
+		selectStmt.TableList = append(selectStmt.TableList, convertFromItemToTableList(item)...)
 	}
 	// Convert WHERE clause
 	if in.WhereClause != nil {
@@ -610,6 +611,20 @@ func convertSelectStmt(in *pgquery.SelectStmt) (*ast.SelectStmt, error) {
 	return selectStmt, nil
 }
 
+// convertFromItemToTableList returns the tables in the FROM clause item, walking through the joins.
+func convertFromItemToTableList(in *pgquery.Node) []*ast.TableDef {
+	if in == nil {
+		return nil
+	}
+	switch node := in.Node.(type) {
+	case *pgquery.Node_RangeVar:
+		return []*ast.TableDef{convertRangeVarToTableName(node.RangeVar, ast.TableTypeBaseTable)}
+	case *pgquery.Node_JoinExpr:
+		return append(convertFromItemToTableList(node.JoinExpr.Larg), convertFromItemToTableList(node.JoinExpr.Rarg)...)
+	}
+	return nil
+}
+
 func convertRangeSubselect(node *pgquery.RangeSubselect) (*ast.SubqueryDef, error) {
 	subselect, ok := node.Subquery.Node.(*pgquery.Node_SelectStmt)
 	if !ok {
@@ -1166,6 +1166,9 @@ func TestPGSelectStmt(t *testing.T) {
 							ColumnName: "*",
 						},
 					},
+					TableList: []*ast.TableDef{
+						{Type: ast.TableTypeBaseTable, Name: "t1"},
+					},
 					SubqueryList: []*ast.SubqueryDef{
 						{
 							Select: &ast.SelectStmt{
@@ -1176,6 +1179,9 @@ func TestPGSelectStmt(t *testing.T) {
 										ColumnName: "*",
 									},
 								},
+								TableList: []*ast.TableDef{
+									{Type: ast.TableTypeBaseTable, Name: "t"},
+								},
 							},
 						},
 					},
@@ -1210,6 +1216,9 @@ func TestPGSelectStmt(t *testing.T) {
 							ColumnName: "*",
 						},
 					},
+					TableList: []*ast.TableDef{
+						{Type: ast.TableTypeBaseTable, Name: "t"},
+					},
 				},
 			},
 			statementList: []parser.SingleSQL{
@@ -1219,6 +1228,30 @@ func TestPGSelectStmt(t *testing.T) {
 				},
 			},
 		},
+		{
+			stmt: "SELECT * FROM t1 JOIN public.t2 ON t1.id = t2.id",
+			want: []ast.Node{
+				&ast.SelectStmt{
+					SetOperation: ast.SetOperationTypeNone,
+					FieldList: []ast.ExpressionNode{
+						&ast.ColumnNameDef{
+							Table:      &ast.TableDef{},
+							ColumnName: "*",
+						},
+					},
+					TableList: []*ast.TableDef{
+						{Type: ast.TableTypeBaseTable, Name: "t1"},
+						{Type: ast.TableTypeBaseTable, Schema: "public", Name: "t2"},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "SELECT * FROM t1 JOIN public.t2 ON t1.id = t2.id",
+					LastLine: 1,
+				},
+			},
+		},
 		{
 			stmt: `
 				SELECT
@@ -1253,6 +1286,9 @@ func TestPGSelectStmt(t *testing.T) {
 							&ast.UnconvertedExpressionDef{},
 							&ast.UnconvertedExpressionDef{},
 						},
+						TableList: []*ast.TableDef{
+							{Type: ast.TableTypeBaseTable, Name: "t"},
+						},
 						WhereClause: &ast.UnconvertedExpressionDef{},
 						PatternLikeList: []*ast.PatternLikeDef{
 							{
@@ -1281,6 +1317,9 @@ func TestPGSelectStmt(t *testing.T) {
 											ColumnName: "*",
 										},
 									},
+									TableList: []*ast.TableDef{
+										{Type: ast.TableTypeBaseTable, Name: "t1"},
+									},
 									WhereClause: &ast.PatternLikeDef{
 										Expression: &ast.ColumnNameDef{
 											Table:      &ast.TableDef{},
@@ -1315,6 +1354,9 @@ func TestPGSelectStmt(t *testing.T) {
 								ColumnName: "*",
 							},
 						},
+						TableList: []*ast.TableDef{
+							{Type: ast.TableTypeBaseTable, Name: "t"},
+						},
 					},
 				},
 			},
@@ -1398,6 +1440,9 @@ func TestUpdateStmt(t *testing.T) {
 										ColumnName: "*",
 									},
 								},
+								TableList: []*ast.TableDef{
+									{Type: ast.TableTypeBaseTable, Name: "t"},
+								},
 							},
 						},
 					},
@@ -1564,6 +1609,9 @@ func TestExplainStmt(t *testing.T) {
 								ColumnName: "*",
 							},
 						},
+						TableList: []*ast.TableDef{
+							{Type: ast.TableTypeBaseTable, Name: "tech_book"},
+						},
 					},
 				},
 			},
@@ -1663,6 +1711,9 @@ func TestInsertStmt(t *testing.T) {
 								ColumnName: "*",
 							},
 						},
+						TableList: []*ast.TableDef{
+							{Type: ast.TableTypeBaseTable, Name: "book"},
+						},
 						WhereClause: &ast.UnconvertedExpressionDef{},
 					},
 				},
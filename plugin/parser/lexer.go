@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// TokenType is the type of a lexical token.
+type TokenType int
+
+const (
+	// TokenWord is the keyword or unquoted identifier, e.g. SELECT, t1.
+	TokenWord TokenType = iota
+	// TokenQuotedIdentifier is the quoted identifier, e.g. "t1", `t1`. The Text doesn't contain the quotes.
+	TokenQuotedIdentifier
+	// TokenString is the string literal, e.g. 'abc', $$abc$$.
+	TokenString
+	// TokenNumber is the number literal, e.g. 123, 1.5.
+	TokenNumber
+	// TokenPunctuation is the single character operator or punctuation, e.g. *, (, ;.
+	TokenPunctuation
+)
+
+// Token is a lexical token in the statement.
+type Token struct {
+	Type TokenType
+	Text string
+	// Line is the line of the token, starting from 1 at the beginning of the statement.
+	Line int
+}
+
+// IsWord returns true if the token is the word equal to the given word case-insensitively.
+func (t Token) IsWord(word string) bool {
+	return t.Type == TokenWord && strings.EqualFold(t.Text, word)
+}
+
+// IsPunctuation returns true if the token is the given punctuation.
+func (t Token) IsPunctuation(punctuation string) bool {
+	return t.Type == TokenPunctuation && t.Text == punctuation
+}
+
+// Tokenize splits a single statement into lexical tokens with comments skipped.
+// It's used for the engines we don't have a full parser for, such as ClickHouse and Snowflake.
+// It returns an error for the unterminated strings, identifiers, comments and unbalanced parentheses.
+func Tokenize(engineType EngineType, statement string) ([]Token, error) {
+	switch engineType {
+	case ClickHouse, Snowflake:
+	default:
+		return nil, errors.Errorf("engine type is not supported: %s", engineType)
+	}
+
+	t := newTokenizer(statement)
+	var res []Token
+	depth := 0
+	for {
+		t.skipBlank()
+		line := t.line
+		startPos := t.pos()
+		switch {
+		case t.char(0) == eofRune:
+			if depth > 0 {
+				return nil, errors.Errorf("syntax error at line %d: %d unclosed parenthesis", line, depth)
+			}
+			return res, nil
+		case t.char(0) == '/' && t.char(1) == '*':
+			if err := t.scanComment(); err != nil {
+				return nil, err
+			}
+		case t.char(0) == '-' && t.char(1) == '-':
+			if err := t.scanComment(); err != nil {
+				return nil, err
+			}
+		case engineType == Snowflake && t.char(0) == '/' && t.char(1) == '/':
+			t.skipToNewLine()
+		case t.char(0) == '\'':
+			if err := t.scanString('\''); err != nil {
+				return nil, err
+			}
+			res = append(res, Token{Type: TokenString, Text: t.getString(startPos+1, t.pos()-startPos-2), Line: line})
+		case engineType == Snowflake && t.char(0) == '$' && (t.char(1) == '$' || unicode.IsLetter(t.char(1))):
+			if err := t.scanDoubleDollarQuotedString(); err != nil {
+				return nil, err
+			}
+			res = append(res, Token{Type: TokenString, Text: t.getString(startPos, t.pos()-startPos), Line: line})
+		case t.char(0) == '"' || (engineType == ClickHouse && t.char(0) == '`'):
+			delimiter := t.char(0)
+			if err := t.scanIdentifier(delimiter); err != nil {
+				return nil, err
+			}
+			res = append(res, Token{Type: TokenQuotedIdentifier, Text: t.getString(startPos+1, t.pos()-startPos-2), Line: line})
+		case unicode.IsDigit(t.char(0)):
+			for unicode.IsDigit(t.char(0)) || t.char(0) == '.' || unicode.IsLetter(t.char(0)) {
+				t.skip(1)
+			}
+			res = append(res, Token{Type: TokenNumber, Text: t.getString(startPos, t.pos()-startPos), Line: line})
+		case isWordRune(t.char(0)):
+			for isWordRune(t.char(0)) || unicode.IsDigit(t.char(0)) || t.char(0) == '$' {
+				t.skip(1)
+			}
+			res = append(res, Token{Type: TokenWord, Text: t.getString(startPos, t.pos()-startPos), Line: line})
+		default:
+			switch t.char(0) {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth < 0 {
+					return nil, errors.Errorf("syntax error at line %d: unexpected )", line)
+				}
+			}
+			t.skip(1)
+			res = append(res, Token{Type: TokenPunctuation, Text: t.getString(startPos, 1), Line: line})
+		}
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		engine    EngineType
+		statement string
+		want      []Token
+		wantErr   bool
+	}{
+		{
+			engine:    ClickHouse,
+			statement: "-- comment\nSELECT `a`, 'x' /* c */ FROM t WHERE id = 12;",
+			want: []Token{
+				{Type: TokenWord, Text: "SELECT", Line: 2},
+				{Type: TokenQuotedIdentifier, Text: "a", Line: 2},
+				{Type: TokenPunctuation, Text: ",", Line: 2},
+				{Type: TokenString, Text: "x", Line: 2},
+				{Type: TokenWord, Text: "FROM", Line: 2},
+				{Type: TokenWord, Text: "t", Line: 2},
+				{Type: TokenWord, Text: "WHERE", Line: 2},
+				{Type: TokenWord, Text: "id", Line: 2},
+				{Type: TokenPunctuation, Text: "=", Line: 2},
+				{Type: TokenNumber, Text: "12", Line: 2},
+				{Type: TokenPunctuation, Text: ";", Line: 2},
+			},
+		},
+		{
+			engine:    Snowflake,
+			statement: "CREATE FUNCTION f()\n// comment\nAS $$ SELECT 1 $$",
+			want: []Token{
+				{Type: TokenWord, Text: "CREATE", Line: 1},
+				{Type: TokenWord, Text: "FUNCTION", Line: 1},
+				{Type: TokenWord, Text: "f", Line: 1},
+				{Type: TokenPunctuation, Text: "(", Line: 1},
+				{Type: TokenPunctuation, Text: ")", Line: 1},
+				{Type: TokenWord, Text: "AS", Line: 3},
+				{Type: TokenString, Text: "$$ SELECT 1 $$", Line: 3},
+			},
+		},
+		{
+			engine:    ClickHouse,
+			statement: "SELECT count(( FROM t",
+			wantErr:   true,
+		},
+		{
+			engine:    Snowflake,
+			statement: "SELECT 'abc FROM t",
+			wantErr:   true,
+		},
+		{
+			engine:    Postgres,
+			statement: "SELECT 1",
+			wantErr:   true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := Tokenize(test.engine, test.statement)
+		if test.wantErr {
+			a.Error(err, test.statement)
+			continue
+		}
+		a.NoError(err, test.statement)
+		a.Equal(test.want, got, test.statement)
+	}
+}
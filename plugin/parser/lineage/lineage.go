@@ -0,0 +1,270 @@
+// Package lineage provides the plugin resolving the query result columns back to the source table columns.
+package lineage
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+// Resolver is the interface for the column lineage resolver.
+type Resolver interface {
+	// Resolve returns the result columns of the query in the same order as the query result.
+	// It returns nil result columns if the query result doesn't come from the tables, e.g. EXPLAIN.
+	Resolve(statement string, schema *DatabaseSchema) ([]*ResultColumn, error)
+}
+
+var (
+	resolverMu sync.RWMutex
+	resolvers  = make(map[parser.EngineType]Resolver)
+)
+
+// Register makes a column lineage resolver available by the provided id.
+// If Register is called twice with the same name or if resolver is nil,
+// it panics.
+func Register(engineType parser.EngineType, r Resolver) {
+	if r == nil {
+		panic("parser: Register resolver is nil")
+	}
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	if _, dup := resolvers[engineType]; dup {
+		panic("parser: Register called twice for column lineage resolver " + engineType)
+	}
+	resolvers[engineType] = r
+}
+
+// Resolve returns the result columns of the query with the source table columns.
+func Resolve(engineType parser.EngineType, statement string, schema *DatabaseSchema) ([]*ResultColumn, error) {
+	resolverMu.RLock()
+	r, ok := resolvers[engineType]
+	resolverMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("engine: unknown engine type %v", engineType)
+	}
+	return r.Resolve(statement, schema)
+}
+
+// DatabaseSchema is the table structure used to resolve the column lineage.
+type DatabaseSchema struct {
+	TableList []*Table
+	// DefaultSchema is the schema of the tables referenced without schema, e.g. the current database of MySQL.
+	// The tables referenced without schema are found in all schemas if it's empty.
+	DefaultSchema string
+}
+
+// Table is the table with the column names in the defined order.
+type Table struct {
+	// Schema is empty for the engines without schema such as MySQL.
+	Schema     string
+	Name       string
+	ColumnList []string
+}
+
+// FindTable finds the table by the name, the schema is optional.
+// It returns nil if the table is not found or the name without schema is ambiguous.
+func (s *DatabaseSchema) FindTable(schema string, name string) *Table {
+	if schema == "" {
+		schema = s.DefaultSchema
+	}
+	var res *Table
+	for _, table := range s.TableList {
+		if schema != "" && !strings.EqualFold(table.Schema, schema) {
+			continue
+		}
+		if strings.EqualFold(table.Name, name) {
+			if res != nil {
+				return nil
+			}
+			res = table
+		}
+	}
+	return res
+}
+
+// SourceColumn is the column of the source table.
+type SourceColumn struct {
+	Schema string
+	Table  string
+	Column string
+}
+
+// ResultColumn is the column of the query result.
+type ResultColumn struct {
+	Name string
+	// SourceColumnList is the list of the source columns the result column derives from, e.g. CONCAT(first_name, last_name).
+	SourceColumnList []SourceColumn
+}
+
+// MergeResultColumns merges the result columns of the set operation such as UNION by position.
+// The names come from the left query.
+func MergeResultColumns(left []*ResultColumn, right []*ResultColumn) ([]*ResultColumn, error) {
+	if len(left) != len(right) {
+		return nil, errors.Errorf("each set operation query must have the same number of columns, but got %d and %d", len(left), len(right))
+	}
+	var res []*ResultColumn
+	for i := range left {
+		res = append(res, &ResultColumn{
+			Name:             left[i].Name,
+			SourceColumnList: MergeSourceColumnList(left[i].SourceColumnList, right[i].SourceColumnList),
+		})
+	}
+	return res, nil
+}
+
+// MergeSourceColumnList merges the source column lists and removes the duplicates.
+func MergeSourceColumnList(list ...[]SourceColumn) []SourceColumn {
+	var res []SourceColumn
+	seen := make(map[SourceColumn]bool)
+	for _, sourceColumnList := range list {
+		for _, column := range sourceColumnList {
+			if !seen[column] {
+				seen[column] = true
+				res = append(res, column)
+			}
+		}
+	}
+	return res
+}
+
+// Source is the named source in the FROM clause, such as a table, a subquery or a CTE reference.
+type Source struct {
+	Name       string
+	ColumnList []*ResultColumn
+	// Opaque is true if we don't know the columns of the source, e.g. a table function.
+	// Any column can be resolved from the opaque source, and it doesn't derive from any table column.
+	Opaque bool
+}
+
+// NewTableSource returns the source of the table.
+func NewTableSource(table *Table, alias string) *Source {
+	source := &Source{Name: table.Name}
+	if alias != "" {
+		source.Name = alias
+	}
+	for _, column := range table.ColumnList {
+		source.ColumnList = append(source.ColumnList, &ResultColumn{
+			Name: column,
+			SourceColumnList: []SourceColumn{
+				{Schema: table.Schema, Table: table.Name, Column: column},
+			},
+		})
+	}
+	return source
+}
+
+// Scope is the name resolution scope of a query.
+type Scope struct {
+	Parent     *Scope
+	SourceList []*Source
+	// CTEMap is the map from the lower case CTE name to the CTE result columns.
+	CTEMap map[string][]*ResultColumn
+}
+
+// NewScope returns a new child scope.
+func NewScope(parent *Scope) *Scope {
+	return &Scope{
+		Parent: parent,
+		CTEMap: make(map[string][]*ResultColumn),
+	}
+}
+
+// FindCTE finds the CTE by the name from the scope and its ancestors.
+func (s *Scope) FindCTE(name string) ([]*ResultColumn, bool) {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if columnList, ok := scope.CTEMap[strings.ToLower(name)]; ok {
+			return columnList, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveColumn resolves the column reference from the scope and its ancestors for the correlated subquery.
+func (s *Scope) ResolveColumn(table string, column string) ([]SourceColumn, error) {
+	for scope := s; scope != nil; scope = scope.Parent {
+		hasOpaque := false
+		for _, source := range scope.SourceList {
+			if table != "" && !strings.EqualFold(source.Name, table) {
+				continue
+			}
+			if source.Opaque {
+				hasOpaque = true
+				continue
+			}
+			for _, resultColumn := range source.ColumnList {
+				if strings.EqualFold(resultColumn.Name, column) {
+					return resultColumn.SourceColumnList, nil
+				}
+			}
+		}
+		// We look up the known sources first, so that the table columns are never shadowed by the opaque source.
+		if hasOpaque {
+			return nil, nil
+		}
+	}
+	if table != "" {
+		return nil, errors.Errorf("column %q.%q not found", table, column)
+	}
+	return nil, errors.Errorf("column %q not found", column)
+}
+
+// ResolveSource resolves the whole source reference such as the whole-row reference in PostgreSQL.
+func (s *Scope) ResolveSource(name string) ([]SourceColumn, bool) {
+	for scope := s; scope != nil; scope = scope.Parent {
+		for _, source := range scope.SourceList {
+			if strings.EqualFold(source.Name, name) {
+				var list [][]SourceColumn
+				for _, resultColumn := range source.ColumnList {
+					list = append(list, resultColumn.SourceColumnList)
+				}
+				return MergeSourceColumnList(list...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ExpandStar expands the * or table.* in the SELECT list.
+func (s *Scope) ExpandStar(table string) ([]*ResultColumn, error) {
+	var res []*ResultColumn
+	found := false
+	for _, source := range s.SourceList {
+		if table != "" && !strings.EqualFold(source.Name, table) {
+			continue
+		}
+		if source.Opaque {
+			return nil, errors.Errorf("cannot expand * for source %q with unknown columns", source.Name)
+		}
+		found = true
+		res = append(res, source.ColumnList...)
+	}
+	if !found {
+		if table != "" {
+			return nil, errors.Errorf("table %q not found for %q.*", table, table)
+		}
+		return nil, errors.New("SELECT * without FROM clause")
+	}
+	return res, nil
+}
+
+// RenameColumns renames the result columns with the alias column names, e.g. t(a, b).
+func RenameColumns(columnList []*ResultColumn, nameList []string) ([]*ResultColumn, error) {
+	if len(nameList) == 0 {
+		return columnList, nil
+	}
+	if len(nameList) > len(columnList) {
+		return nil, errors.Errorf("%d column aliases are specified, but only %d columns are available", len(nameList), len(columnList))
+	}
+	var res []*ResultColumn
+	for i, column := range columnList {
+		name := column.Name
+		if i < len(nameList) {
+			name = nameList[i]
+		}
+		res = append(res, &ResultColumn{Name: name, SourceColumnList: column.SourceColumnList})
+	}
+	return res, nil
+}
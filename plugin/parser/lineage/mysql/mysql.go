@@ -0,0 +1,278 @@
+// Package mysql provides the MySQL column lineage resolver plugin.
+package mysql
+
+import (
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pkg/errors"
+
+	bbparser "github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/lineage"
+
+	// Register pingcap parser driver.
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+var (
+	_ lineage.Resolver = (*Resolver)(nil)
+)
+
+func init() {
+	lineage.Register(bbparser.MySQL, &Resolver{})
+	lineage.Register(bbparser.TiDB, &Resolver{})
+}
+
+// Resolver is the column lineage resolver for MySQL dialect.
+type Resolver struct {
+}
+
+// Resolve implements the lineage.Resolver interface.
+func (*Resolver) Resolve(statement string, schema *lineage.DatabaseSchema) ([]*lineage.ResultColumn, error) {
+	nodeList, _, err := parser.New().Parse(statement, "", "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse statement %q", statement)
+	}
+	if len(nodeList) != 1 {
+		return nil, errors.Errorf("expect one statement, but found %d", len(nodeList))
+	}
+
+	r := &resolver{schema: schema}
+	switch node := nodeList[0].(type) {
+	case *ast.ExplainStmt:
+		return nil, nil
+	case ast.ResultSetNode:
+		return r.resolveResultSet(node, nil)
+	default:
+		return nil, errors.Errorf("unsupported statement %T", node)
+	}
+}
+
+type resolver struct {
+	schema *lineage.DatabaseSchema
+}
+
+func (r *resolver) resolveResultSet(node ast.ResultSetNode, parent *lineage.Scope) ([]*lineage.ResultColumn, error) {
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		return r.resolveSelect(n, parent)
+	case *ast.SetOprStmt:
+		scope := lineage.NewScope(parent)
+		if err := r.resolveWith(n.With, scope); err != nil {
+			return nil, err
+		}
+		return r.resolveSetOprSelectList(n.SelectList, scope)
+	case *ast.SubqueryExpr:
+		return r.resolveResultSet(n.Query, parent)
+	default:
+		return nil, errors.Errorf("unsupported result set %T", node)
+	}
+}
+
+func (r *resolver) resolveSetOprSelectList(list *ast.SetOprSelectList, parent *lineage.Scope) ([]*lineage.ResultColumn, error) {
+	scope := lineage.NewScope(parent)
+	if err := r.resolveWith(list.With, scope); err != nil {
+		return nil, err
+	}
+	var res []*lineage.ResultColumn
+	for i, node := range list.Selects {
+		var columnList []*lineage.ResultColumn
+		var err error
+		switch n := node.(type) {
+		case *ast.SetOprSelectList:
+			columnList, err = r.resolveSetOprSelectList(n, scope)
+		case ast.ResultSetNode:
+			columnList, err = r.resolveResultSet(n, scope)
+		default:
+			err = errors.Errorf("unsupported set operation query %T", node)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			res = columnList
+			continue
+		}
+		if res, err = lineage.MergeResultColumns(res, columnList); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *resolver) resolveWith(with *ast.WithClause, scope *lineage.Scope) error {
+	if with == nil {
+		return nil
+	}
+	for _, cte := range with.CTEs {
+		var nameList []string
+		for _, name := range cte.ColNameList {
+			nameList = append(nameList, name.O)
+		}
+		// The recursive CTE references itself in the recursive part, so we resolve the seed part first.
+		if cte.IsRecursive {
+			if setOpr, ok := cte.Query.Query.(*ast.SetOprStmt); ok && len(setOpr.SelectList.Selects) > 0 {
+				if seed, ok := setOpr.SelectList.Selects[0].(ast.ResultSetNode); ok {
+					columnList, err := r.resolveResultSet(seed, scope)
+					if err != nil {
+						return err
+					}
+					if columnList, err = lineage.RenameColumns(columnList, nameList); err != nil {
+						return err
+					}
+					scope.CTEMap[cte.Name.L] = columnList
+				}
+			}
+		}
+		columnList, err := r.resolveResultSet(cte.Query, scope)
+		if err != nil {
+			return err
+		}
+		if columnList, err = lineage.RenameColumns(columnList, nameList); err != nil {
+			return err
+		}
+		scope.CTEMap[cte.Name.L] = columnList
+	}
+	return nil
+}
+
+func (r *resolver) resolveSelect(sel *ast.SelectStmt, parent *lineage.Scope) ([]*lineage.ResultColumn, error) {
+	scope := lineage.NewScope(parent)
+	if err := r.resolveWith(sel.With, scope); err != nil {
+		return nil, err
+	}
+	if sel.From != nil && sel.From.TableRefs != nil {
+		if err := r.resolveFrom(sel.From.TableRefs, scope); err != nil {
+			return nil, err
+		}
+	}
+	if sel.Fields == nil {
+		return nil, errors.Errorf("unsupported SELECT statement without fields %q", sel.Text())
+	}
+
+	var res []*lineage.ResultColumn
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard != nil {
+			columnList, err := scope.ExpandStar(field.WildCard.Table.O)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, columnList...)
+			continue
+		}
+		sourceColumnList, err := r.resolveExpr(field.Expr, scope)
+		if err != nil {
+			return nil, err
+		}
+		name := field.AsName.O
+		if name == "" {
+			if column, ok := field.Expr.(*ast.ColumnNameExpr); ok {
+				name = column.Name.Name.O
+			} else {
+				name = field.Text()
+			}
+		}
+		res = append(res, &lineage.ResultColumn{
+			Name:             name,
+			SourceColumnList: sourceColumnList,
+		})
+	}
+	return res, nil
+}
+
+func (r *resolver) resolveFrom(node ast.ResultSetNode, scope *lineage.Scope) error {
+	switch n := node.(type) {
+	case *ast.Join:
+		if n.Left != nil {
+			if err := r.resolveFrom(n.Left, scope); err != nil {
+				return err
+			}
+		}
+		if n.Right != nil {
+			if err := r.resolveFrom(n.Right, scope); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.TableSource:
+		switch source := n.Source.(type) {
+		case *ast.TableName:
+			alias := n.AsName.O
+			if source.Schema.O == "" {
+				if columnList, ok := scope.FindCTE(source.Name.O); ok {
+					name := source.Name.O
+					if alias != "" {
+						name = alias
+					}
+					scope.SourceList = append(scope.SourceList, &lineage.Source{Name: name, ColumnList: columnList})
+					return nil
+				}
+			}
+			// MySQL doesn't have schema, so the schema in the table name is the database name.
+			table := r.schema.FindTable(source.Schema.O, source.Name.O)
+			if table == nil {
+				return errors.Errorf("table %q not found", source.Name.O)
+			}
+			scope.SourceList = append(scope.SourceList, lineage.NewTableSource(table, alias))
+			return nil
+		default:
+			columnList, err := r.resolveResultSet(source, scope)
+			if err != nil {
+				return err
+			}
+			scope.SourceList = append(scope.SourceList, &lineage.Source{Name: n.AsName.O, ColumnList: columnList})
+			return nil
+		}
+	default:
+		return r.resolveFrom(&ast.TableSource{Source: node}, scope)
+	}
+}
+
+// resolveExpr returns the source columns of the expression, including the columns in the scalar subqueries.
+func (r *resolver) resolveExpr(expr ast.ExprNode, scope *lineage.Scope) ([]lineage.SourceColumn, error) {
+	v := &columnVisitor{resolver: r, scope: scope}
+	expr.Accept(v)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return lineage.MergeSourceColumnList(v.sourceColumnList...), nil
+}
+
+type columnVisitor struct {
+	resolver         *resolver
+	scope            *lineage.Scope
+	sourceColumnList [][]lineage.SourceColumn
+	err              error
+}
+
+// Enter implements the ast.Visitor interface.
+func (v *columnVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if v.err != nil {
+		return in, true
+	}
+	switch n := in.(type) {
+	case *ast.ColumnNameExpr:
+		sourceColumnList, err := v.scope.ResolveColumn(n.Name.Table.O, n.Name.Name.O)
+		if err != nil {
+			v.err = err
+			return in, true
+		}
+		v.sourceColumnList = append(v.sourceColumnList, sourceColumnList)
+		return in, true
+	case *ast.SubqueryExpr:
+		columnList, err := v.resolver.resolveResultSet(n.Query, v.scope)
+		if err != nil {
+			v.err = err
+			return in, true
+		}
+		for _, column := range columnList {
+			v.sourceColumnList = append(v.sourceColumnList, column.SourceColumnList)
+		}
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*columnVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
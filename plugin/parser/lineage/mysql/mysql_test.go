@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/parser/lineage"
+)
+
+var testSchema = &lineage.DatabaseSchema{
+	TableList: []*lineage.Table{
+		{Name: "user", ColumnList: []string{"id", "name", "email"}},
+		{Name: "card", ColumnList: []string{"id", "user_id", "number"}},
+	},
+}
+
+func TestResolve(t *testing.T) {
+	userID := lineage.SourceColumn{Table: "user", Column: "id"}
+	userName := lineage.SourceColumn{Table: "user", Column: "name"}
+	userEmail := lineage.SourceColumn{Table: "user", Column: "email"}
+	cardID := lineage.SourceColumn{Table: "card", Column: "id"}
+	cardUserID := lineage.SourceColumn{Table: "card", Column: "user_id"}
+	cardNumber := lineage.SourceColumn{Table: "card", Column: "number"}
+
+	tests := []struct {
+		statement string
+		want      []*lineage.ResultColumn
+	}{
+		{
+			statement: "SELECT * FROM user",
+			want: []*lineage.ResultColumn{
+				{Name: "id", SourceColumnList: []lineage.SourceColumn{userID}},
+				{Name: "name", SourceColumnList: []lineage.SourceColumn{userName}},
+				{Name: "email", SourceColumnList: []lineage.SourceColumn{userEmail}},
+			},
+		},
+		{
+			statement: "SELECT u.name AS n, CONCAT(c.number, u.email) AS x, 1 FROM user u JOIN card c ON u.id = c.user_id",
+			want: []*lineage.ResultColumn{
+				{Name: "n", SourceColumnList: []lineage.SourceColumn{userName}},
+				{Name: "x", SourceColumnList: []lineage.SourceColumn{cardNumber, userEmail}},
+				{Name: "1", SourceColumnList: nil},
+			},
+		},
+		{
+			statement: "SELECT t.a, (SELECT number FROM card WHERE card.user_id = t.a LIMIT 1) AS num FROM (SELECT id AS a FROM user) t",
+			want: []*lineage.ResultColumn{
+				{Name: "a", SourceColumnList: []lineage.SourceColumn{userID}},
+				{Name: "num", SourceColumnList: []lineage.SourceColumn{cardNumber}},
+			},
+		},
+		{
+			statement: "WITH cte(k) AS (SELECT number FROM card) SELECT k FROM cte UNION SELECT name FROM user",
+			want: []*lineage.ResultColumn{
+				{Name: "k", SourceColumnList: []lineage.SourceColumn{cardNumber, userName}},
+			},
+		},
+		{
+			statement: "SELECT c.* FROM user, card c",
+			want: []*lineage.ResultColumn{
+				{Name: "id", SourceColumnList: []lineage.SourceColumn{cardID}},
+				{Name: "user_id", SourceColumnList: []lineage.SourceColumn{cardUserID}},
+				{Name: "number", SourceColumnList: []lineage.SourceColumn{cardNumber}},
+			},
+		},
+		{
+			statement: "EXPLAIN SELECT * FROM user",
+			want:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		res, err := (&Resolver{}).Resolve(test.statement, testSchema)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, res, test.statement)
+	}
+
+	for _, statement := range []string{
+		"SELECT unknown FROM user",
+		"SELECT * FROM unknown",
+		"SELECT 1; SELECT 2",
+	} {
+		_, err := (&Resolver{}).Resolve(statement, testSchema)
+		require.Error(t, err, statement)
+	}
+}
@@ -0,0 +1,343 @@
+// Package pg provides the PostgreSQL column lineage resolver plugin.
+package pg
+
+import (
+	"fmt"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/lineage"
+)
+
+var (
+	_ lineage.Resolver = (*Resolver)(nil)
+)
+
+func init() {
+	lineage.Register(parser.Postgres, &Resolver{})
+}
+
+// Resolver is the column lineage resolver for PostgreSQL dialect.
+type Resolver struct {
+}
+
+// Resolve implements the lineage.Resolver interface.
+func (*Resolver) Resolve(statement string, schema *lineage.DatabaseSchema) ([]*lineage.ResultColumn, error) {
+	res, err := pgquery.Parse(statement)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse statement %q", statement)
+	}
+	if len(res.Stmts) != 1 {
+		return nil, errors.Errorf("expect one statement, but found %d", len(res.Stmts))
+	}
+
+	r := &resolver{schema: schema}
+	node := res.Stmts[0].Stmt
+	if node.GetExplainStmt() != nil {
+		return nil, nil
+	}
+	sel := node.GetSelectStmt()
+	if sel == nil {
+		return nil, errors.Errorf("unsupported statement %q", statement)
+	}
+	return r.resolveSelect(sel, nil)
+}
+
+type resolver struct {
+	schema *lineage.DatabaseSchema
+}
+
+func (r *resolver) resolveSelect(sel *pgquery.SelectStmt, parent *lineage.Scope) ([]*lineage.ResultColumn, error) {
+	scope := lineage.NewScope(parent)
+	if err := r.resolveWith(sel.WithClause, scope); err != nil {
+		return nil, err
+	}
+
+	if sel.Op != pgquery.SetOperation_SETOP_NONE && sel.Larg != nil && sel.Rarg != nil {
+		left, err := r.resolveSelect(sel.Larg, scope)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.resolveSelect(sel.Rarg, scope)
+		if err != nil {
+			return nil, err
+		}
+		return lineage.MergeResultColumns(left, right)
+	}
+
+	if len(sel.ValuesLists) > 0 {
+		var res []*lineage.ResultColumn
+		for i, value := range sel.ValuesLists[0].GetList().GetItems() {
+			sourceColumnList, err := r.resolveExpr(value, scope)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, &lineage.ResultColumn{
+				Name:             fmt.Sprintf("column%d", i+1),
+				SourceColumnList: sourceColumnList,
+			})
+		}
+		return res, nil
+	}
+
+	for _, from := range sel.FromClause {
+		if err := r.resolveFrom(from, scope); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []*lineage.ResultColumn
+	for _, target := range sel.TargetList {
+		resTarget := target.GetResTarget()
+		if resTarget == nil {
+			return nil, errors.Errorf("unsupported target %v", target)
+		}
+		if columnRef := resTarget.Val.GetColumnRef(); columnRef != nil {
+			if fieldList := columnRef.Fields; len(fieldList) > 0 && fieldList[len(fieldList)-1].GetAStar() != nil {
+				table := ""
+				if len(fieldList) > 1 {
+					table = fieldList[len(fieldList)-2].GetString_().GetStr()
+				}
+				columnList, err := scope.ExpandStar(table)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, columnList...)
+				continue
+			}
+		}
+
+		sourceColumnList, err := r.resolveExpr(resTarget.Val, scope)
+		if err != nil {
+			return nil, err
+		}
+		name := resTarget.Name
+		if name == "" {
+			name = "?column?"
+			if columnRef := resTarget.Val.GetColumnRef(); columnRef != nil {
+				name = columnRef.Fields[len(columnRef.Fields)-1].GetString_().GetStr()
+			}
+		}
+		res = append(res, &lineage.ResultColumn{
+			Name:             name,
+			SourceColumnList: sourceColumnList,
+		})
+	}
+	return res, nil
+}
+
+func (r *resolver) resolveWith(with *pgquery.WithClause, scope *lineage.Scope) error {
+	if with == nil {
+		return nil
+	}
+	for _, node := range with.Ctes {
+		cte := node.GetCommonTableExpr()
+		if cte == nil {
+			continue
+		}
+		query := cte.Ctequery.GetSelectStmt()
+		if query == nil {
+			return errors.Errorf("unsupported CTE %q", cte.Ctename)
+		}
+		nameList := getStringList(cte.Aliascolnames)
+		// The recursive CTE references itself in the recursive part, so we resolve the seed part first.
+		if with.Recursive && query.Op != pgquery.SetOperation_SETOP_NONE && query.Larg != nil {
+			columnList, err := r.resolveSelect(query.Larg, scope)
+			if err != nil {
+				return err
+			}
+			if columnList, err = lineage.RenameColumns(columnList, nameList); err != nil {
+				return err
+			}
+			scope.CTEMap[cte.Ctename] = columnList
+		}
+		columnList, err := r.resolveSelect(query, scope)
+		if err != nil {
+			return err
+		}
+		if columnList, err = lineage.RenameColumns(columnList, nameList); err != nil {
+			return err
+		}
+		scope.CTEMap[cte.Ctename] = columnList
+	}
+	return nil
+}
+
+func (r *resolver) resolveFrom(node *pgquery.Node, scope *lineage.Scope) error {
+	switch {
+	case node.GetRangeVar() != nil:
+		rangeVar := node.GetRangeVar()
+		alias := ""
+		var nameList []string
+		if rangeVar.Alias != nil {
+			alias = rangeVar.Alias.Aliasname
+			nameList = getStringList(rangeVar.Alias.Colnames)
+		}
+		var source *lineage.Source
+		if columnList, ok := scope.FindCTE(rangeVar.Relname); ok && rangeVar.Schemaname == "" {
+			source = &lineage.Source{Name: rangeVar.Relname, ColumnList: columnList}
+			if alias != "" {
+				source.Name = alias
+			}
+		} else {
+			table := r.schema.FindTable(rangeVar.Schemaname, rangeVar.Relname)
+			if table == nil {
+				return errors.Errorf("table %q not found", rangeVar.Relname)
+			}
+			source = lineage.NewTableSource(table, alias)
+		}
+		columnList, err := lineage.RenameColumns(source.ColumnList, nameList)
+		if err != nil {
+			return err
+		}
+		source.ColumnList = columnList
+		scope.SourceList = append(scope.SourceList, source)
+		return nil
+	case node.GetRangeSubselect() != nil:
+		subselect := node.GetRangeSubselect()
+		query := subselect.Subquery.GetSelectStmt()
+		if query == nil {
+			return errors.New("unsupported subquery in FROM clause")
+		}
+		columnList, err := r.resolveSelect(query, scope)
+		if err != nil {
+			return err
+		}
+		source := &lineage.Source{}
+		if subselect.Alias != nil {
+			source.Name = subselect.Alias.Aliasname
+			if columnList, err = lineage.RenameColumns(columnList, getStringList(subselect.Alias.Colnames)); err != nil {
+				return err
+			}
+		}
+		source.ColumnList = columnList
+		scope.SourceList = append(scope.SourceList, source)
+		return nil
+	case node.GetJoinExpr() != nil:
+		join := node.GetJoinExpr()
+		if err := r.resolveFrom(join.Larg, scope); err != nil {
+			return err
+		}
+		return r.resolveFrom(join.Rarg, scope)
+	case node.GetRangeFunction() != nil:
+		// The table function such as generate_series() doesn't read the table columns.
+		rangeFunction := node.GetRangeFunction()
+		source := &lineage.Source{Opaque: true}
+		if rangeFunction.Alias != nil {
+			source.Name = rangeFunction.Alias.Aliasname
+		}
+		scope.SourceList = append(scope.SourceList, source)
+		return nil
+	default:
+		return errors.Errorf("unsupported FROM clause %v", node)
+	}
+}
+
+// resolveExpr returns the source columns of the expression, including the columns in the subqueries.
+func (r *resolver) resolveExpr(node *pgquery.Node, scope *lineage.Scope) ([]lineage.SourceColumn, error) {
+	if node == nil {
+		return nil, nil
+	}
+	var list [][]lineage.SourceColumn
+	var resErr error
+	walk(node.ProtoReflect(), func(m protoreflect.Message) bool {
+		if resErr != nil {
+			return false
+		}
+		switch n := m.Interface().(type) {
+		case *pgquery.ColumnRef:
+			sourceColumnList, err := resolveColumnRef(n, scope)
+			if err != nil {
+				resErr = err
+				return false
+			}
+			list = append(list, sourceColumnList)
+			return false
+		case *pgquery.SubLink:
+			query := n.Subselect.GetSelectStmt()
+			if query == nil {
+				return true
+			}
+			columnList, err := r.resolveSelect(query, scope)
+			if err != nil {
+				resErr = err
+				return false
+			}
+			for _, column := range columnList {
+				list = append(list, column.SourceColumnList)
+			}
+			// The test expression such as a in `a IN (SELECT ...)` is checked separately.
+			if n.Testexpr != nil {
+				sourceColumnList, err := r.resolveExpr(n.Testexpr, scope)
+				if err != nil {
+					resErr = err
+					return false
+				}
+				list = append(list, sourceColumnList)
+			}
+			return false
+		}
+		return true
+	})
+	if resErr != nil {
+		return nil, resErr
+	}
+	return lineage.MergeSourceColumnList(list...), nil
+}
+
+func resolveColumnRef(columnRef *pgquery.ColumnRef, scope *lineage.Scope) ([]lineage.SourceColumn, error) {
+	nameList := getStringList(columnRef.Fields)
+	switch len(nameList) {
+	case 1:
+		sourceColumnList, err := scope.ResolveColumn("", nameList[0])
+		if err != nil {
+			// The whole-row reference such as SELECT t FROM t.
+			if sourceColumnList, ok := scope.ResolveSource(nameList[0]); ok {
+				return sourceColumnList, nil
+			}
+			return nil, err
+		}
+		return sourceColumnList, nil
+	case 0:
+		return nil, errors.New("invalid empty column reference")
+	default:
+		return scope.ResolveColumn(nameList[len(nameList)-2], nameList[len(nameList)-1])
+	}
+}
+
+// walk walks the protobuf message tree in depth-first order, and stops walking the children if visit returns false.
+func walk(m protoreflect.Message, visit func(protoreflect.Message) bool) {
+	if !visit(m) {
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walk(list.Get(i).Message(), visit)
+			}
+			return true
+		}
+		if fd.IsMap() {
+			return true
+		}
+		walk(v.Message(), visit)
+		return true
+	})
+}
+
+func getStringList(nodeList []*pgquery.Node) []string {
+	var res []string
+	for _, node := range nodeList {
+		if s := node.GetString_(); s != nil {
+			res = append(res, s.Str)
+		}
+	}
+	return res
+}
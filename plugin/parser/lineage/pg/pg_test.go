@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/parser/lineage"
+)
+
+var testSchema = &lineage.DatabaseSchema{
+	TableList: []*lineage.Table{
+		{Schema: "public", Name: "user", ColumnList: []string{"id", "name", "email"}},
+		{Schema: "public", Name: "card", ColumnList: []string{"id", "user_id", "number"}},
+	},
+}
+
+func TestResolve(t *testing.T) {
+	userID := lineage.SourceColumn{Schema: "public", Table: "user", Column: "id"}
+	userName := lineage.SourceColumn{Schema: "public", Table: "user", Column: "name"}
+	userEmail := lineage.SourceColumn{Schema: "public", Table: "user", Column: "email"}
+	cardUserID := lineage.SourceColumn{Schema: "public", Table: "card", Column: "user_id"}
+	cardNumber := lineage.SourceColumn{Schema: "public", Table: "card", Column: "number"}
+
+	tests := []struct {
+		statement string
+		want      []*lineage.ResultColumn
+	}{
+		{
+			statement: `SELECT * FROM public."user"`,
+			want: []*lineage.ResultColumn{
+				{Name: "id", SourceColumnList: []lineage.SourceColumn{userID}},
+				{Name: "name", SourceColumnList: []lineage.SourceColumn{userName}},
+				{Name: "email", SourceColumnList: []lineage.SourceColumn{userEmail}},
+			},
+		},
+		{
+			statement: `SELECT u.name AS n, c.number || u.email AS x, 1 FROM "user" u JOIN card c ON u.id = c.user_id`,
+			want: []*lineage.ResultColumn{
+				{Name: "n", SourceColumnList: []lineage.SourceColumn{userName}},
+				{Name: "x", SourceColumnList: []lineage.SourceColumn{cardNumber, userEmail}},
+				{Name: "?column?", SourceColumnList: nil},
+			},
+		},
+		{
+			statement: `SELECT t.a, (SELECT number FROM card WHERE card.user_id = t.a LIMIT 1) AS num FROM (SELECT id FROM "user") t(a)`,
+			want: []*lineage.ResultColumn{
+				{Name: "a", SourceColumnList: []lineage.SourceColumn{userID}},
+				{Name: "num", SourceColumnList: []lineage.SourceColumn{cardNumber}},
+			},
+		},
+		{
+			statement: `WITH RECURSIVE cte(k) AS (SELECT user_id FROM card UNION ALL SELECT k FROM cte) SELECT k FROM cte UNION SELECT name FROM "user"`,
+			want: []*lineage.ResultColumn{
+				{Name: "k", SourceColumnList: []lineage.SourceColumn{cardUserID, userName}},
+			},
+		},
+		{
+			statement: `SELECT u FROM "user" u`,
+			want: []*lineage.ResultColumn{
+				{Name: "u", SourceColumnList: []lineage.SourceColumn{userID, userName, userEmail}},
+			},
+		},
+		{
+			statement: `SELECT g, u.id FROM generate_series(1, 3) g, "user" u`,
+			want: []*lineage.ResultColumn{
+				{Name: "g", SourceColumnList: nil},
+				{Name: "id", SourceColumnList: []lineage.SourceColumn{userID}},
+			},
+		},
+		{
+			statement: `EXPLAIN SELECT * FROM "user"`,
+			want:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		res, err := (&Resolver{}).Resolve(test.statement, testSchema)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, res, test.statement)
+	}
+
+	for _, statement := range []string{
+		`SELECT unknown FROM "user"`,
+		`SELECT * FROM unknown`,
+		`SELECT * FROM generate_series(1, 3)`,
+		`SELECT 1; SELECT 2`,
+	} {
+		_, err := (&Resolver{}).Resolve(statement, testSchema)
+		require.Error(t, err, statement)
+	}
+}
@@ -19,6 +19,10 @@ const (
 	Postgres EngineType = "POSTGRES"
 	// TiDB is the engine type for TiDB.
 	TiDB EngineType = "TIDB"
+	// ClickHouse is the engine type for CLICKHOUSE.
+	ClickHouse EngineType = "CLICKHOUSE"
+	// Snowflake is the engine type for SNOWFLAKE.
+	Snowflake EngineType = "SNOWFLAKE"
 )
 
 // ParseContext is the context for parsing.
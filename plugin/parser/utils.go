@@ -22,10 +22,12 @@ type SingleSQL struct {
 // SplitMultiSQL splits statement into a slice of the single SQL.
 func SplitMultiSQL(engineType EngineType, statement string) ([]SingleSQL, error) {
 	switch engineType {
-	case Postgres:
+	// Snowflake shares the PostgreSQL lexical structure of strings, $$ strings and identifiers.
+	case Postgres, Snowflake:
 		t := newTokenizer(statement)
 		return t.splitPostgreSQLMultiSQL()
-	case MySQL, TiDB:
+	// ClickHouse shares the MySQL lexical structure of strings and backtick identifiers.
+	case MySQL, TiDB, ClickHouse:
 		t := newTokenizer(statement)
 		return t.splitMySQLMultiSQL()
 	default:
@@ -36,10 +38,10 @@ func SplitMultiSQL(engineType EngineType, statement string) ([]SingleSQL, error)
 // SplitMultiSQLStream splits statement stream into a slice of the single SQL.
 func SplitMultiSQLStream(engineType EngineType, src io.Reader, f func(string) error) ([]SingleSQL, error) {
 	switch engineType {
-	case Postgres:
+	case Postgres, Snowflake:
 		t := newStreamTokenizer(src, f)
 		return t.splitPostgreSQLMultiSQL()
-	case MySQL, TiDB:
+	case MySQL, TiDB, ClickHouse:
 		t := newStreamTokenizer(src, f)
 		return t.splitMySQLMultiSQL()
 	default:
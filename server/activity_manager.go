@@ -286,6 +286,12 @@ func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool,
 		return true, nil
 	case api.ActivityPipelineTaskEarliestAllowedTimeUpdate:
 		return true, nil
+	case api.ActivityPipelineTaskMaintenanceWindowOverrideUpdate:
+		return true, nil
+	case api.ActivityPipelineTaskApprovalCreate:
+		return true, nil
+	case api.ActivityPipelineStageRolloutHalt:
+		return true, nil
 	case api.ActivityPipelineTaskStatusUpdate:
 		update := new(api.ActivityPipelineTaskStatusUpdatePayload)
 		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
@@ -65,6 +65,8 @@ type Profile struct {
 	MetricConnectionKey string
 	// DisableMetric will disable the metric collector.
 	DisableMetric bool
+	// MaxConcurrentTasks is the max number of tasks running at the same time across all instances, 0 means no limit.
+	MaxConcurrentTasks int
 }
 
 func (prof *Profile) useEmbedDB() bool {
@@ -397,6 +397,50 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.PATCH("/database/:id/table/:tableName/column/:columnName", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		tableName := c.Param("tableName")
+		columnName := c.Param("columnName")
+
+		table, err := s.store.GetTable(ctx, &api.TableFind{DatabaseID: &id, Name: &tableName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch table for database id: %d, table name: %s", id, tableName)).SetInternal(err)
+		}
+		if table == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("table %q not found from database %v", tableName, id))
+		}
+		columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &id, TableID: &table.ID, Name: &columnName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch column for database id: %d, table name: %s, column name: %s", id, tableName, columnName)).SetInternal(err)
+		}
+		if len(columnList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("column %q not found from table %q in database %v", columnName, tableName, id))
+		}
+
+		columnPatch := &api.ColumnPatch{
+			ID:        columnList[0].ID,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, columnPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch column request").SetInternal(err)
+		}
+		if v := columnPatch.Sensitivity; v != nil && !v.IsValid() {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid column sensitivity %q", *v))
+		}
+
+		column, err := s.store.PatchColumn(ctx, columnPatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch column %q of table %q in database %v", columnName, tableName, id)).SetInternal(err)
+		}
+
+		// The column is serialized as a table attribute in JSON, so we keep the same format here.
+		return c.JSON(http.StatusOK, column)
+	})
+
 	g.GET("/database/:id/view", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		id, err := strconv.Atoi(c.Param("id"))
@@ -906,11 +950,56 @@ func getConnectionConfig(instance *api.Instance, databaseName string) (db.Connec
 // We'd like to use read-only data source whenever possible, but fallback to admin data source if there's no read-only data source.
 // Upon successful return, caller MUST call driver.Close, otherwise, it will leak the database connection.
 func tryGetReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, error) {
+	return getReadOnlyDatabaseDriverWithDataSource(ctx, instance, databaseName, getReadOnlyDataSource(instance, 0 /* databaseID */, 0 /* principalID */))
+}
+
+// tryGetPrincipalReadOnlyDatabaseDriver is the same as tryGetReadOnlyDatabaseDriver, except that it routes the query through
+// the read-only data source requested by the principal for the database if there is one.
+// Upon successful return, caller MUST call driver.Close, otherwise, it will leak the database connection.
+func (s *Server) tryGetPrincipalReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string, principalID int) (db.Driver, error) {
+	databaseID := 0
+	// Avoid looking up the database if the principal has no requested data source on the instance, which is the common case.
+	if databaseName != "" && getPrincipalDataSourceCount(instance, principalID) > 0 {
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID, Name: &databaseName})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find database %q", databaseName)
+		}
+		if database != nil {
+			databaseID = database.ID
+		}
+	}
+	return getReadOnlyDatabaseDriverWithDataSource(ctx, instance, databaseName, getReadOnlyDataSource(instance, databaseID, principalID))
+}
+
+// getReadOnlyDataSource returns the data source for the readonly query of the principal on the database.
+// The read-only data source requested by the principal for the database comes first, then the shared read-only data source, and finally the admin data source.
+func getReadOnlyDataSource(instance *api.Instance, databaseID int, principalID int) *api.DataSource {
+	for _, dataSource := range instance.DataSourceList {
+		if dataSource.Type == api.RO && dataSource.PrincipalID != nil && *dataSource.PrincipalID == principalID && dataSource.DatabaseID == databaseID {
+			return dataSource
+		}
+	}
 	dataSource := api.DataSourceFromInstanceWithType(instance, api.RO)
 	// If there are no read-only data source, fall back to admin data source.
 	if dataSource == nil {
 		dataSource = api.DataSourceFromInstanceWithType(instance, api.Admin)
 	}
+	return dataSource
+}
+
+func getPrincipalDataSourceCount(instance *api.Instance, principalID int) int {
+	count := 0
+	for _, dataSource := range instance.DataSourceList {
+		if dataSource.PrincipalID != nil && *dataSource.PrincipalID == principalID {
+			count++
+		}
+	}
+	return count
+}
+
+// getReadOnlyDatabaseDriverWithDataSource opens the read-only connection to the database with the data source.
+// Upon successful return, caller MUST call driver.Close, otherwise, it will leak the database connection.
+func getReadOnlyDatabaseDriverWithDataSource(ctx context.Context, instance *api.Instance, databaseName string, dataSource *api.DataSource) (db.Driver, error) {
 	if dataSource == nil {
 		return nil, common.Errorf(common.Internal, "data source not found for instance %d", instance.ID)
 	}
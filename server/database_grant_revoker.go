@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	// The interval bounds how long an expired database grant can still be used.
+	databaseGrantRevokeInterval = time.Duration(1) * time.Minute
+)
+
+// NewDatabaseGrantRevoker creates a database grant revoker.
+func NewDatabaseGrantRevoker(server *Server) *DatabaseGrantRevoker {
+	return &DatabaseGrantRevoker{
+		server: server,
+	}
+}
+
+// DatabaseGrantRevoker is the runner dropping the expired database users created by the database grant and the data source request issues.
+// The data sources requested with the users are deleted as well.
+type DatabaseGrantRevoker struct {
+	server *Server
+}
+
+// Run will run the database grant revoker.
+func (s *DatabaseGrantRevoker) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(databaseGrantRevokeInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Database grant revoker started and will run every %v", databaseGrantRevokeInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = errors.Errorf("%v", r)
+						}
+						log.Error("Database grant revoker PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+					}
+				}()
+				s.revokeExpiredGrant(ctx)
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (s *DatabaseGrantRevoker) revokeExpiredGrant(ctx context.Context) {
+	now := time.Now().Unix()
+	instanceUserList, err := s.server.store.FindInstanceUser(ctx, &api.InstanceUserFind{
+		ExpireTsBefore: &now,
+	})
+	if err != nil {
+		log.Error("Failed to find the expired instance users", zap.Error(err))
+		return
+	}
+	for _, instanceUser := range instanceUserList {
+		if err := s.revoke(ctx, instanceUser); err != nil {
+			// We will retry in the next round.
+			log.Error("Failed to revoke the expired database grant",
+				zap.Int("instance_id", instanceUser.InstanceID),
+				zap.String("user", instanceUser.Name),
+				zap.Error(err))
+		}
+	}
+}
+
+func (s *DatabaseGrantRevoker) revoke(ctx context.Context, instanceUser *api.InstanceUser) error {
+	var database *api.Database
+	var requestedDataSource *api.DataSource
+	if taskID, ok := getDatabaseGrantTaskID(instanceUser.Name); ok {
+		task, err := s.server.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the database grant task %d", taskID)
+		}
+		if task == nil || task.Database == nil {
+			return errors.Errorf("database grant task %d not found", taskID)
+		}
+		database = task.Database
+	} else if databaseID, principalID, ok := getDataSourceRequestID(instanceUser.Name); ok {
+		var err error
+		database, err = s.server.store.GetDatabase(ctx, &api.DatabaseFind{ID: &databaseID})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find database %d", databaseID)
+		}
+		if database == nil {
+			return errors.Errorf("database %d not found", databaseID)
+		}
+		requestedDataSource, err = s.server.store.GetDataSource(ctx, &api.DataSourceFind{
+			DatabaseID:  &databaseID,
+			PrincipalID: &principalID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the requested data source of database %d", databaseID)
+		}
+	} else {
+		return errors.Errorf("instance user %q is not created by the database grant or the data source request", instanceUser.Name)
+	}
+	// The database composes the instance, but we need the latest instance for the connection.
+	instance, err := s.server.store.GetInstanceByID(ctx, instanceUser.InstanceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find instance %d", instanceUser.InstanceID)
+	}
+	if instance == nil {
+		return errors.Errorf("instance %d not found", instanceUser.InstanceID)
+	}
+
+	statementList, err := getDatabaseRevokeStatementList(instance.Engine, getDatabaseUserName(instanceUser.Name))
+	if err != nil {
+		return err
+	}
+	if err := s.server.executeDatabaseGrantStatementList(ctx, instance, database.Name, statementList); err != nil {
+		return err
+	}
+	// Delete the requested data source before the instance user, so that we retry if it fails.
+	if requestedDataSource != nil {
+		if err := s.server.store.DeleteDataSource(ctx, &api.DataSourceDelete{ID: requestedDataSource.ID}); err != nil {
+			return errors.Wrapf(err, "failed to delete the requested data source %q", requestedDataSource.Name)
+		}
+	}
+	if err := s.server.store.DeleteInstanceUser(ctx, &api.InstanceUserDelete{ID: instanceUser.ID}); err != nil {
+		return errors.Wrapf(err, "failed to delete instance user %q", instanceUser.Name)
+	}
+	log.Info("Revoked the expired database grant",
+		zap.String("instance", instance.Name),
+		zap.String("database", database.Name),
+		zap.String("user", instanceUser.Name))
+	return nil
+}
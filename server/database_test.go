@@ -152,3 +152,52 @@ func TestValidateDatabaseLabelList(t *testing.T) {
 		}
 	}
 }
+
+func TestGetReadOnlyDataSource(t *testing.T) {
+	principalID := 101
+	adminDataSource := &api.DataSource{ID: 1, DatabaseID: 1, Type: api.Admin}
+	sharedDataSource := &api.DataSource{ID: 2, DatabaseID: 1, Type: api.RO}
+	principalDataSource := &api.DataSource{ID: 3, DatabaseID: 2, Type: api.RO, PrincipalID: &principalID}
+
+	tests := []struct {
+		name           string
+		dataSourceList []*api.DataSource
+		databaseID     int
+		principalID    int
+		want           *api.DataSource
+	}{
+		{
+			name:           "principal data source of the database",
+			dataSourceList: []*api.DataSource{adminDataSource, sharedDataSource, principalDataSource},
+			databaseID:     2,
+			principalID:    principalID,
+			want:           principalDataSource,
+		},
+		{
+			name:           "principal data source of another database",
+			dataSourceList: []*api.DataSource{adminDataSource, sharedDataSource, principalDataSource},
+			databaseID:     3,
+			principalID:    principalID,
+			want:           sharedDataSource,
+		},
+		{
+			name:           "other principal",
+			dataSourceList: []*api.DataSource{adminDataSource, principalDataSource},
+			databaseID:     2,
+			principalID:    102,
+			want:           adminDataSource,
+		},
+		{
+			name:           "no data source",
+			dataSourceList: []*api.DataSource{principalDataSource},
+			databaseID:     0,
+			principalID:    0,
+			want:           nil,
+		},
+	}
+
+	for _, test := range tests {
+		instance := &api.Instance{DataSourceList: test.dataSourceList}
+		assert.Equal(t, test.want, getReadOnlyDataSource(instance, test.databaseID, test.principalID), test.name)
+	}
+}
@@ -22,9 +22,24 @@ func isMatchExpression(labels map[string]string, expression *api.LabelSelectorRe
 			}
 		}
 		return false
+	case api.NotInOperatorType:
+		value, ok := labels[expression.Key]
+		if !ok {
+			// A database without the label is not in any of the values.
+			return true
+		}
+		for _, exprValue := range expression.Values {
+			if exprValue == value {
+				return false
+			}
+		}
+		return true
 	case api.ExistsOperatorType:
 		_, ok := labels[expression.Key]
 		return ok
+	case api.DoesNotExistOperatorType:
+		_, ok := labels[expression.Key]
+		return !ok
 	default:
 		return false
 	}
@@ -47,8 +62,26 @@ func isMatchExpressions(labels map[string]string, expressionList []*api.LabelSel
 // getDatabaseMatrixFromDeploymentSchedule gets a pipeline based on deployment schedule.
 // The returned matrix doesn't include deployment with no matched database.
 func getDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, baseDatabaseName, dbNameTemplate string, databaseList []*api.Database) ([]*api.Deployment, [][]*api.Database, error) {
+	deploymentDatabaseList, err := matchDeploymentSchedule(schedule, baseDatabaseName, dbNameTemplate, databaseList)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var matrix [][]*api.Database
 	var deployments []*api.Deployment
+	for i, deployment := range schedule.Deployments {
+		if len(deploymentDatabaseList[i]) > 0 {
+			matrix = append(matrix, deploymentDatabaseList[i])
+			deployments = append(deployments, deployment)
+		}
+	}
+	return deployments, matrix, nil
+}
+
+// matchDeploymentSchedule returns the databases matched by each deployment in the deployment schedule.
+// A database is matched by the first deployment whose selector matches its labels.
+func matchDeploymentSchedule(schedule *api.DeploymentSchedule, baseDatabaseName, dbNameTemplate string, databaseList []*api.Database) ([][]*api.Database, error) {
+	var deploymentDatabaseList [][]*api.Database
 
 	// idToLabels maps databaseID -> label.Key -> label.Value
 	idToLabels := make(map[int]map[string]string)
@@ -60,7 +93,7 @@ func getDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, b
 		}
 		var labelList []*api.DatabaseLabel
 		if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		for _, label := range labelList {
 			idToLabels[database.ID][label.Key] = label.Value
@@ -109,13 +142,59 @@ func getDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, b
 			return databaseList[i].Name > databaseList[j].Name
 		})
 
-		if len(databaseList) > 0 {
-			matrix = append(matrix, databaseList)
-			deployments = append(deployments, deployment)
+		deploymentDatabaseList = append(deploymentDatabaseList, databaseList)
+	}
+
+	return deploymentDatabaseList, nil
+}
+
+// getDeploymentPreview returns the databases matched by each deployment in the deployment schedule, and the databases not matched by any deployment.
+func getDeploymentPreview(schedule *api.DeploymentSchedule, baseDatabaseName, dbNameTemplate string, databaseList []*api.Database) (*api.DeploymentPreview, error) {
+	// Match the databases by labels only without the base database name.
+	if baseDatabaseName == "" {
+		dbNameTemplate = ""
+	}
+	deploymentDatabaseList, err := matchDeploymentSchedule(schedule, baseDatabaseName, dbNameTemplate, databaseList)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &api.DeploymentPreview{
+		Deployments:           []*api.DeploymentPreviewDeployment{},
+		UnmatchedDatabaseList: []*api.DeploymentPreviewDatabase{},
+	}
+	matched := make(map[int]bool)
+	for i, deployment := range schedule.Deployments {
+		previewDeployment := &api.DeploymentPreviewDeployment{
+			Name:         deployment.Name,
+			DatabaseList: []*api.DeploymentPreviewDatabase{},
+		}
+		for _, database := range deploymentDatabaseList[i] {
+			matched[database.ID] = true
+			previewDeployment.DatabaseList = append(previewDeployment.DatabaseList, toDeploymentPreviewDatabase(database))
 		}
+		preview.Deployments = append(preview.Deployments, previewDeployment)
 	}
+	for _, database := range databaseList {
+		if !matched[database.ID] {
+			preview.UnmatchedDatabaseList = append(preview.UnmatchedDatabaseList, toDeploymentPreviewDatabase(database))
+		}
+	}
+	return preview, nil
+}
 
-	return deployments, matrix, nil
+func toDeploymentPreviewDatabase(database *api.Database) *api.DeploymentPreviewDatabase {
+	previewDatabase := &api.DeploymentPreviewDatabase{
+		ID:   database.ID,
+		Name: database.Name,
+	}
+	if database.Instance != nil {
+		previewDatabase.InstanceName = database.Instance.Name
+		if database.Instance.Environment != nil {
+			previewDatabase.EnvironmentName = database.Instance.Environment.Name
+		}
+	}
+	return previewDatabase
 }
 
 // formatDatabaseName will return the full database name given the dbNameTemplate, base database name, and labels.
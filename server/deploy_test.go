@@ -241,6 +241,47 @@ func TestGetDatabaseMatrixFromDeploymentSchedule(t *testing.T) {
 				{dbs[5], dbs[6]},
 			},
 		},
+		{
+			"notInAndDoesNotExistDeployments",
+			&api.DeploymentSchedule{
+				Deployments: []*api.Deployment{
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.location",
+										Operator: "NotIn",
+										Values:   []string{"earth"},
+									},
+								},
+							},
+						},
+					},
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.tenant",
+										Operator: "DoesNotExist",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"",
+			"",
+			[]*api.Database{
+				dbs[0], dbs[1], dbs[3], dbs[4],
+			},
+			[][]*api.Database{
+				{dbs[0]},
+				{dbs[4], dbs[3]},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -248,3 +289,104 @@ func TestGetDatabaseMatrixFromDeploymentSchedule(t *testing.T) {
 		assert.Equal(t, matrix, test.want)
 	}
 }
+
+func TestIsMatchExpression(t *testing.T) {
+	labels := map[string]string{
+		"bb.environment": "Prod",
+		"bb.location":    "us",
+	}
+	tests := []struct {
+		expression *api.LabelSelectorRequirement
+		want       bool
+	}{
+		{&api.LabelSelectorRequirement{Key: "bb.location", Operator: api.InOperatorType, Values: []string{"us", "eu"}}, true},
+		{&api.LabelSelectorRequirement{Key: "bb.location", Operator: api.InOperatorType, Values: []string{"cn"}}, false},
+		{&api.LabelSelectorRequirement{Key: "bb.location", Operator: api.NotInOperatorType, Values: []string{"cn"}}, true},
+		{&api.LabelSelectorRequirement{Key: "bb.location", Operator: api.NotInOperatorType, Values: []string{"us", "cn"}}, false},
+		{&api.LabelSelectorRequirement{Key: "bb.tenant", Operator: api.NotInOperatorType, Values: []string{"bytebase"}}, true},
+		{&api.LabelSelectorRequirement{Key: "bb.location", Operator: api.ExistsOperatorType}, true},
+		{&api.LabelSelectorRequirement{Key: "bb.tenant", Operator: api.ExistsOperatorType}, false},
+		{&api.LabelSelectorRequirement{Key: "bb.location", Operator: api.DoesNotExistOperatorType}, false},
+		{&api.LabelSelectorRequirement{Key: "bb.tenant", Operator: api.DoesNotExistOperatorType}, true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, isMatchExpression(labels, test.expression), "%s %s %v", test.expression.Key, test.expression.Operator, test.expression.Values)
+	}
+}
+
+func TestGetDeploymentPreview(t *testing.T) {
+	environment := &api.Environment{Name: "Prod"}
+	instance := &api.Instance{Name: "instance", Environment: environment}
+	dbs := []*api.Database{
+		{
+			ID:       1,
+			Name:     "db1_us",
+			Instance: instance,
+			Labels:   "[{\"key\":\"bb.location\",\"value\":\"us\"},{\"key\":\"bb.environment\",\"value\":\"Prod\"}]",
+		},
+		{
+			ID:       2,
+			Name:     "db1_cn",
+			Instance: instance,
+			Labels:   "[{\"key\":\"bb.location\",\"value\":\"cn\"},{\"key\":\"bb.environment\",\"value\":\"Prod\"}]",
+		},
+		{
+			ID:       3,
+			Name:     "db2_us",
+			Instance: instance,
+			Labels:   "[{\"key\":\"bb.location\",\"value\":\"us\"},{\"key\":\"bb.environment\",\"value\":\"Prod\"}]",
+		},
+	}
+	schedule := &api.DeploymentSchedule{
+		Deployments: []*api.Deployment{
+			{
+				Name: "except-cn",
+				Spec: &api.DeploymentSpec{
+					Selector: &api.LabelSelector{
+						MatchExpressions: []*api.LabelSelectorRequirement{
+							{Key: "bb.environment", Operator: api.InOperatorType, Values: []string{"Prod"}},
+							{Key: "bb.location", Operator: api.NotInOperatorType, Values: []string{"cn"}},
+						},
+					},
+				},
+			},
+			{
+				Name: "without-location",
+				Spec: &api.DeploymentSpec{
+					Selector: &api.LabelSelector{
+						MatchExpressions: []*api.LabelSelectorRequirement{
+							{Key: "bb.environment", Operator: api.InOperatorType, Values: []string{"Prod"}},
+							{Key: "bb.location", Operator: api.DoesNotExistOperatorType},
+						},
+					},
+				},
+			},
+		},
+	}
+	previewDatabase := func(database *api.Database) *api.DeploymentPreviewDatabase {
+		return &api.DeploymentPreviewDatabase{ID: database.ID, Name: database.Name, InstanceName: "instance", EnvironmentName: "Prod"}
+	}
+
+	// All project databases are matched by labels without the base database name.
+	preview, err := getDeploymentPreview(schedule, "", "{{DB_NAME}}_{{LOCATION}}", dbs)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.DeploymentPreview{
+		Deployments: []*api.DeploymentPreviewDeployment{
+			{Name: "except-cn", DatabaseList: []*api.DeploymentPreviewDatabase{previewDatabase(dbs[2]), previewDatabase(dbs[0])}},
+			{Name: "without-location", DatabaseList: []*api.DeploymentPreviewDatabase{}},
+		},
+		UnmatchedDatabaseList: []*api.DeploymentPreviewDatabase{previewDatabase(dbs[1])},
+	}, preview)
+
+	// Only the tenant databases of the base database are matched with the base database name.
+	preview, err = getDeploymentPreview(schedule, "db1", "{{DB_NAME}}_{{LOCATION}}", dbs)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.DeploymentPreview{
+		Deployments: []*api.DeploymentPreviewDeployment{
+			{Name: "except-cn", DatabaseList: []*api.DeploymentPreviewDatabase{previewDatabase(dbs[0])}},
+			{Name: "without-location", DatabaseList: []*api.DeploymentPreviewDatabase{}},
+		},
+		UnmatchedDatabaseList: []*api.DeploymentPreviewDatabase{previewDatabase(dbs[1]), previewDatabase(dbs[2])},
+	}, preview)
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -464,6 +465,10 @@ func (s *Server) getPipelineCreate(ctx context.Context, issueCreate *api.IssueCr
 	switch issueCreate.Type {
 	case api.IssueDatabaseCreate:
 		return s.getPipelineCreateForDatabaseCreate(ctx, issueCreate)
+	case api.IssueDatabaseGrant:
+		return s.getPipelineCreateForDatabaseGrant(ctx, issueCreate)
+	case api.IssueDataSourceRequest:
+		return s.getPipelineCreateForDataSourceRequest(ctx, issueCreate)
 	case api.IssueDatabaseRestorePITR:
 		return s.getPipelineCreateForDatabasePITR(ctx, issueCreate)
 	case api.IssueDatabaseSchemaUpdate, api.IssueDatabaseDataUpdate:
@@ -567,6 +572,128 @@ func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCr
 	}, nil
 }
 
+func (s *Server) getPipelineCreateForDatabaseGrant(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.DatabaseGrantContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	if c.Access != api.DatabaseGrantRead && c.Access != api.DatabaseGrantWrite {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database grant access %q", c.Access))
+	}
+	if c.ExpireTs <= time.Now().Unix() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "The database grant expiration time must be in the future")
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The issue project %d must be the same as the database project %d.", issueCreate.ProjectID, database.ProjectID))
+	}
+	switch database.Instance.Engine {
+	case db.MySQL, db.TiDB, db.Postgres:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database grant is not supported for engine %s", database.Instance.Engine))
+	}
+
+	payload := api.TaskDatabaseGrantPayload{
+		Access:   c.Access,
+		ExpireTs: c.ExpireTs,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create database grant task, unable to marshal payload")
+	}
+
+	return &api.PipelineCreate{
+		Name: fmt.Sprintf("Pipeline - Grant %s access to database %s", c.Access, database.Name),
+		StageList: []api.StageCreate{
+			{
+				Name:          "Grant database",
+				EnvironmentID: database.Instance.EnvironmentID,
+				TaskList: []api.TaskCreate{
+					{
+						InstanceID:   database.InstanceID,
+						DatabaseID:   &database.ID,
+						Name:         fmt.Sprintf("Grant %s access to database %q", c.Access, database.Name),
+						Status:       api.TaskPendingApproval,
+						Type:         api.TaskDatabaseGrant,
+						DatabaseName: database.Name,
+						Payload:      string(payloadBytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Server) getPipelineCreateForDataSourceRequest(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.DataSourceRequestContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	if !s.feature(api.FeatureReadReplicaConnection) {
+		if c.HostOverride != "" || c.PortOverride != "" {
+			return nil, echo.NewHTTPError(http.StatusForbidden, api.FeatureReadReplicaConnection.AccessErrorMessage())
+		}
+	}
+	if c.ExpireTs <= time.Now().Unix() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "The data source expiration time must be in the future")
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The issue project %d must be the same as the database project %d.", issueCreate.ProjectID, database.ProjectID))
+	}
+	// The read-only user of the data source is created in the same way as the database grant.
+	switch database.Instance.Engine {
+	case db.MySQL, db.TiDB, db.Postgres:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data source request is not supported for engine %s", database.Instance.Engine))
+	}
+
+	payload := api.TaskDataSourceRequestPayload{
+		HostOverride: c.HostOverride,
+		PortOverride: c.PortOverride,
+		ExpireTs:     c.ExpireTs,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create data source request task, unable to marshal payload")
+	}
+
+	return &api.PipelineCreate{
+		Name: fmt.Sprintf("Pipeline - Request read-only data source of database %s", database.Name),
+		StageList: []api.StageCreate{
+			{
+				Name:          "Request data source",
+				EnvironmentID: database.Instance.EnvironmentID,
+				TaskList: []api.TaskCreate{
+					{
+						InstanceID:   database.InstanceID,
+						DatabaseID:   &database.ID,
+						Name:         fmt.Sprintf("Create read-only data source of database %q", database.Name),
+						Status:       api.TaskPendingApproval,
+						Type:         api.TaskDataSourceRequest,
+						DatabaseName: database.Name,
+						Payload:      string(payloadBytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 func (s *Server) getPipelineCreateForDatabasePITR(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
 	c := api.PITRContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
@@ -710,6 +837,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 					Name:          deployments[i].Name,
 					EnvironmentID: environmentID,
 					TaskList:      taskCreateList,
+					Rollout:       deployments[i].Spec.Rollout,
 				})
 			}
 		}
@@ -1445,6 +1573,16 @@ func (s *Server) setTaskProgressForIssue(issue *api.Issue) {
 			if progress, ok := s.TaskScheduler.taskProgress.Load(task.ID); ok {
 				task.Progress = progress.(api.Progress)
 			}
+			if task.Status == api.TaskPending {
+				if allowedTs, ok := s.TaskScheduler.maintenanceWindowAllowedTs.Load(task.ID); ok {
+					task.MaintenanceWindowAllowedTs = allowedTs.(int64)
+				}
+			}
+			if task.Status == api.TaskRunning {
+				if position, ok := s.TaskScheduler.taskQueuePosition.Load(task.ID); ok {
+					task.QueuePosition = position.(int)
+				}
+			}
 		}
 	}
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	metricAPI "github.com/bytebase/bytebase/metric"
+	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
 	"github.com/bytebase/bytebase/plugin/db"
@@ -45,22 +46,37 @@ func (s *Server) registerOpenAPIRoutes(g *echo.Group) {
 }
 
 type sqlCheckRequestBody struct {
-	Statement       string `json:"statement"`
-	DatabaseType    string `json:"databaseType"`
-	DatabaseName    string `json:"databaseName"`
-	EnvironmentName string `json:"environmentName"`
-	Host            string `json:"host"`
-	Port            string `json:"port"`
+	Statement       string          `json:"statement"`
+	FileList        []*sqlCheckFile `json:"fileList"`
+	DatabaseType    string          `json:"databaseType"`
+	DatabaseName    string          `json:"databaseName"`
+	EnvironmentName string          `json:"environmentName"`
+	Host            string          `json:"host"`
+	Port            string          `json:"port"`
 }
 
+// sqlCheckFile is the SQL file for batch check.
+type sqlCheckFile struct {
+	FilePath  string `json:"filePath"`
+	Statement string `json:"statement"`
+}
+
+// defaultSQLCheckFilePath is the file path used in SARIF and JUnit reports for the single statement request.
+const defaultSQLCheckFilePath = "statement.sql"
+
 // sqlCheckController godoc
 // @Summary  Check the SQL statement.
 // @Description  Parse and check the SQL statement according to the SQL review policy.
+// @Description  The response format is negotiated by the Accept header: application/json (default), application/sarif+json (SARIF 2.1.0) or application/xml (JUnit XML).
+// @Description  For the JSON format, the response is the advice list for the statement, or the advice list per file if the file list is specified.
 // @Accept  */*
 // @Tags  SQL review
 // @Produce  json
+// @Produce  application/sarif+json
+// @Produce  application/xml
 // @Param  environmentName  body  string  true   "The environment name. Case sensitive."
-// @Param  statement        body  string  true   "The SQL statement."
+// @Param  statement        body  string  false  "The SQL statement. Required if the file list is not specified."
+// @Param  fileList         body  array   false  "The SQL files for batch check, each with the filePath and statement. Required if the statement is not specified."
 // @Param  databaseType     body  string  false  "The database type. Required if the port, host and database name is not specified."  Enums(MYSQL, POSTGRES, TIDB)
 // @Param  host             body  string  false  "The instance host."
 // @Param  port             body  string  false  "The instance port."
@@ -83,25 +99,28 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing required environment name")
 	}
 
-	if request.Statement == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Missing required SQL statement")
+	if request.Statement == "" && len(request.FileList) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required SQL statement or file list")
+	}
+	if request.Statement != "" && len(request.FileList) != 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "The SQL statement and file list cannot be specified at the same time")
+	}
+	for _, file := range request.FileList {
+		if file.FilePath == "" || file.Statement == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing required file path or SQL statement in the file list")
+		}
 	}
 
 	ctx := c.Request().Context()
 	var databaseType string
-	var catalog catalog.Catalog
+	var database *api.Database
 
 	if request.DatabaseName != "" && request.Host != "" && request.Port != "" {
-		database, err := s.findDatabase(ctx, request.Host, request.Port, request.DatabaseName)
-		if err != nil {
-			return err
-		}
-		dbType := database.Instance.Engine
-		databaseType = string(dbType)
-		catalog, err = s.store.NewCatalog(ctx, database.ID, dbType)
+		database, err = s.findDatabase(ctx, request.Host, request.Port, request.DatabaseName)
 		if err != nil {
 			return err
 		}
+		databaseType = string(database.Instance.Engine)
 	} else {
 		databaseType = request.DatabaseType
 		if databaseType == "" {
@@ -113,9 +132,6 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %s is not support", databaseType))
 	}
-	if catalog == nil {
-		catalog = newCatalogService(advisorDBType)
-	}
 
 	envList, err := s.store.FindEnvironment(ctx, &api.EnvironmentFind{
 		Name: &request.EnvironmentName,
@@ -127,17 +143,44 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid environment %s", request.EnvironmentName))
 	}
 
-	_, adviceList, err := s.sqlCheck(
-		ctx,
-		advisorDBType,
-		"utf8mb4",
-		"utf8mb4_general_ci",
-		envList[0].ID,
-		request.Statement,
-		catalog,
-	)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to run sql check").SetInternal(err)
+	fileList := request.FileList
+	if request.Statement != "" {
+		fileList = []*sqlCheckFile{
+			{
+				FilePath:  defaultSQLCheckFilePath,
+				Statement: request.Statement,
+			},
+		}
+	}
+
+	var fileAdviceList []*advisor.FileAdvice
+	for _, file := range fileList {
+		// The catalog will be changed by the walk-through, so we need a new one for each file.
+		var catalog catalog.Catalog
+		if database != nil {
+			catalog, err = s.store.NewCatalog(ctx, database.ID, database.Instance.Engine)
+			if err != nil {
+				return err
+			}
+		} else {
+			catalog = newCatalogService(advisorDBType)
+		}
+		_, adviceList, err := s.sqlCheck(
+			ctx,
+			advisorDBType,
+			"utf8mb4",
+			"utf8mb4_general_ci",
+			envList[0].ID,
+			file.Statement,
+			catalog,
+		)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to run sql check for %s", file.FilePath)).SetInternal(err)
+		}
+		fileAdviceList = append(fileAdviceList, &advisor.FileAdvice{
+			FilePath:   file.FilePath,
+			AdviceList: adviceList,
+		})
 	}
 
 	if s.MetricReporter != nil {
@@ -151,7 +194,25 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusOK, adviceList)
+	switch advisor.NegotiateReportFormat(c.Request().Header.Get(echo.HeaderAccept)) {
+	case advisor.ReportFormatSARIF:
+		report, err := advisor.ConvertToSARIF(s.profile.Version, fileAdviceList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate SARIF report").SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, string(advisor.ReportFormatSARIF), report)
+	case advisor.ReportFormatJUnit:
+		report, err := advisor.ConvertToJUnit(fileAdviceList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate JUnit report").SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, report)
+	}
+
+	if request.Statement != "" {
+		return c.JSON(http.StatusOK, fileAdviceList[0].AdviceList)
+	}
+	return c.JSON(http.StatusOK, fileAdviceList)
 }
 
 func (s *Server) findDatabase(ctx context.Context, host string, port string, databaseName string) (*api.Database, error) {
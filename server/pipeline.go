@@ -2,18 +2,37 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
+// rolloutValidationTimeout is the timeout of the validation query in the stage rollout.
+const rolloutValidationTimeout = time.Minute
+
 // ScheduleActiveStage tries to schedule the tasks in the active stage.
 func (s *Server) ScheduleActiveStage(ctx context.Context, pipeline *api.Pipeline) error {
 	stage := getActiveStage(pipeline.StageList)
 	if stage == nil {
 		return nil
 	}
+	// Stop the rollout once the failed tasks exceed the tolerated percentage.
+	if isStageHalted(stage) {
+		return nil
+	}
+	canRun := isStagePauseOver(pipeline.StageList, stage, time.Now())
+	runningCount := 0
+	for _, task := range stage.TaskList {
+		if task.Status == api.TaskRunning {
+			runningCount++
+		}
+	}
 	for _, task := range stage.TaskList {
 		switch task.Status {
 		case api.TaskPendingApproval:
@@ -27,6 +46,15 @@ func (s *Server) ScheduleActiveStage(ctx context.Context, pipeline *api.Pipeline
 				if err != nil {
 					return errors.Wrap(err, "failed to check if can auto-approve")
 				}
+				if ok {
+					// The risky tasks require the approvals even if the policy never requires manual approval.
+					// The risk level is unknown if the task checks are rerunning, and we wait for them to finish.
+					approvalRuleList, _, err := s.getTaskApprovalRuleList(ctx, task, api.TaskPending)
+					if err != nil && common.ErrorCode(err) != common.Conflict {
+						return errors.Wrap(err, "failed to get the approval rules")
+					}
+					ok = err == nil && len(approvalRuleList) == 0
+				}
 				if ok {
 					if _, err := s.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
 						IDList:    []int{task.ID},
@@ -38,15 +66,188 @@ func (s *Server) ScheduleActiveStage(ctx context.Context, pipeline *api.Pipeline
 				}
 			}
 		case api.TaskPending:
-			_, err := s.TaskScheduler.ScheduleIfNeeded(ctx, task)
+			if !canRun {
+				continue
+			}
+			if maxCount := stage.Rollout.MaxConcurrentDatabases; maxCount > 0 && runningCount >= maxCount {
+				continue
+			}
+			updatedTask, err := s.TaskScheduler.ScheduleIfNeeded(ctx, task)
 			if err != nil {
 				return errors.Wrap(err, "failed to schedule task")
 			}
+			if updatedTask.Status == api.TaskRunning {
+				runningCount++
+			}
 		}
 	}
 	return nil
 }
 
+// isStagePauseOver returns true if the pause of the stage rollout after the previous stage finishes is over.
+func isStagePauseOver(stageList []*api.Stage, stage *api.Stage, now time.Time) bool {
+	if stage.Rollout.PauseSeconds <= 0 {
+		return true
+	}
+	var previousStage *api.Stage
+	for _, s := range stageList {
+		if s.ID == stage.ID {
+			break
+		}
+		previousStage = s
+	}
+	if previousStage == nil {
+		return true
+	}
+	// The previous stage finishes when its last task is updated.
+	var finishedTs int64
+	for _, task := range previousStage.TaskList {
+		if task.UpdatedTs > finishedTs {
+			finishedTs = task.UpdatedTs
+		}
+	}
+	return now.Unix() >= finishedTs+int64(stage.Rollout.PauseSeconds)
+}
+
+// getStageFailedTaskCount returns the number of failed tasks in the stage.
+func getStageFailedTaskCount(stage *api.Stage) int {
+	count := 0
+	for _, task := range stage.TaskList {
+		if task.Status == api.TaskFailed {
+			count++
+		}
+	}
+	return count
+}
+
+// isStageHalted returns true if the failed tasks in the stage exceed the tolerated percentage of the stage rollout.
+func isStageHalted(stage *api.Stage) bool {
+	return stage.Rollout.IsHalted(getStageFailedTaskCount(stage), len(stage.TaskList))
+}
+
+// isStageCompleted returns true if the stage no longer blocks the following stages.
+// A stage completes when all its tasks are done, or when the rest of the tasks have failed within the tolerated percentage of the stage rollout.
+func isStageCompleted(stage *api.Stage) bool {
+	failedCount := 0
+	for _, task := range stage.TaskList {
+		switch task.Status {
+		case api.TaskDone:
+		case api.TaskFailed:
+			failedCount++
+		default:
+			return false
+		}
+	}
+	if failedCount == 0 {
+		return true
+	}
+	return stage.Rollout.MaxFailurePercent != nil && !stage.Rollout.IsHalted(failedCount, len(stage.TaskList))
+}
+
+// createStageRolloutHaltActivity creates an activity if the failed task makes the stage rollout halted.
+func (s *Server) createStageRolloutHaltActivity(ctx context.Context, task *api.Task, issue *api.Issue) error {
+	stage, err := s.store.GetStageByID(ctx, task.StageID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get stage %d", task.StageID)
+	}
+	if stage == nil {
+		return errors.Errorf("stage %d not found", task.StageID)
+	}
+	failedCount, taskCount := getStageFailedTaskCount(stage), len(stage.TaskList)
+	// Only notify when the stage crosses the tolerated percentage.
+	if !stage.Rollout.IsHalted(failedCount, taskCount) || stage.Rollout.IsHalted(failedCount-1, taskCount) {
+		return nil
+	}
+
+	issueName := ""
+	if issue != nil {
+		issueName = issue.Name
+	}
+	payload, err := json.Marshal(api.ActivityPipelineStageRolloutHaltPayload{
+		StageID:           stage.ID,
+		StageName:         stage.Name,
+		FailedTaskCount:   failedCount,
+		TaskCount:         taskCount,
+		MaxFailurePercent: *stage.Rollout.MaxFailurePercent,
+		IssueName:         issueName,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal activity after halting the stage rollout: %v", stage.Name)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: stage.PipelineID,
+		Type:        api.ActivityPipelineStageRolloutHalt,
+		Level:       api.ActivityError,
+		Payload:     string(payload),
+		Comment:     fmt.Sprintf("Halted the rollout since %d of %d tasks failed in stage %q, exceeding %d%%.", failedCount, taskCount, stage.Name, *stage.Rollout.MaxFailurePercent),
+	}
+	activityMeta := ActivityMeta{}
+	if issue != nil {
+		activityMeta.issue = issue
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activityMeta); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateTaskRollout runs the validation query of the stage rollout against the task database after the task finishes,
+// and returns an error if the result doesn't match the expected one.
+func (s *Server) validateTaskRollout(ctx context.Context, task *api.Task) error {
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate, api.TaskDatabaseSchemaUpdateSDL, api.TaskDatabaseDataUpdate, api.TaskDatabaseSchemaUpdateGhostCutover:
+	default:
+		return nil
+	}
+	if task.Database == nil {
+		return nil
+	}
+	stage, err := s.store.GetStageByID(ctx, task.StageID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get stage %d", task.StageID)
+	}
+	if stage == nil || stage.Rollout.ValidationQuery == "" {
+		return nil
+	}
+
+	driver, err := tryGetReadOnlyDatabaseDriver(ctx, task.Instance, task.Database.Name)
+	if err != nil {
+		return common.Wrapf(err, common.TaskRolloutValidationFailed, "failed to connect to database %q for the rollout validation", task.Database.Name)
+	}
+	defer driver.Close(ctx)
+	rowSet, err := driver.Query(ctx, stage.Rollout.ValidationQuery, &db.QueryContext{
+		Limit:   1,
+		Timeout: rolloutValidationTimeout,
+	})
+	if err != nil {
+		return common.Wrapf(err, common.TaskRolloutValidationFailed, "failed to run the rollout validation query")
+	}
+	_, rowList, err := convertRowSet(rowSet)
+	if err != nil {
+		return common.Wrapf(err, common.TaskRolloutValidationFailed, "failed to read the rollout validation result")
+	}
+	if len(rowList) == 0 || len(rowList[0]) == 0 {
+		return common.Errorf(common.TaskRolloutValidationFailed, "rollout validation query returned no result, expected %q", stage.Rollout.ValidationExpectedResult)
+	}
+	if got := formatRolloutValidationResult(rowList[0][0]); got != stage.Rollout.ValidationExpectedResult {
+		return common.Errorf(common.TaskRolloutValidationFailed, "rollout validation query returned %q, expected %q", got, stage.Rollout.ValidationExpectedResult)
+	}
+	return nil
+}
+
+// formatRolloutValidationResult formats the value returned by the validation query for comparison.
+func formatRolloutValidationResult(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func (s *Server) schedulePipelineTaskCheck(ctx context.Context, pipeline *api.Pipeline) error {
 	for _, stage := range pipeline.StageList {
 		for _, task := range stage.TaskList {
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func newTestStage(id int, rollout api.DeploymentRollout, statusList ...api.TaskStatus) *api.Stage {
+	stage := &api.Stage{ID: id, Rollout: rollout}
+	for _, status := range statusList {
+		stage.TaskList = append(stage.TaskList, &api.Task{Status: status})
+	}
+	return stage
+}
+
+func TestGetActiveStageWithRollout(t *testing.T) {
+	tolerance := 25
+	tests := []struct {
+		name       string
+		stageList  []*api.Stage
+		wantID     int
+		wantHalted bool
+	}{
+		{
+			name: "failedTaskBlocksWithoutTolerance",
+			stageList: []*api.Stage{
+				newTestStage(1, api.DeploymentRollout{}, api.TaskDone, api.TaskFailed),
+				newTestStage(2, api.DeploymentRollout{}, api.TaskPendingApproval),
+			},
+			wantID:     1,
+			wantHalted: false,
+		},
+		{
+			name: "failedTaskWithinTolerance",
+			stageList: []*api.Stage{
+				newTestStage(1, api.DeploymentRollout{MaxFailurePercent: &tolerance}, api.TaskDone, api.TaskDone, api.TaskDone, api.TaskFailed),
+				newTestStage(2, api.DeploymentRollout{}, api.TaskPendingApproval),
+			},
+			wantID:     2,
+			wantHalted: false,
+		},
+		{
+			name: "runningTaskWithinTolerance",
+			stageList: []*api.Stage{
+				newTestStage(1, api.DeploymentRollout{MaxFailurePercent: &tolerance}, api.TaskDone, api.TaskDone, api.TaskRunning, api.TaskFailed),
+				newTestStage(2, api.DeploymentRollout{}, api.TaskPendingApproval),
+			},
+			wantID:     1,
+			wantHalted: false,
+		},
+		{
+			name: "failedTasksExceedTolerance",
+			stageList: []*api.Stage{
+				newTestStage(1, api.DeploymentRollout{MaxFailurePercent: &tolerance}, api.TaskDone, api.TaskRunning, api.TaskFailed, api.TaskFailed),
+				newTestStage(2, api.DeploymentRollout{}, api.TaskPendingApproval),
+			},
+			wantID:     1,
+			wantHalted: true,
+		},
+		{
+			name: "allStagesCompleted",
+			stageList: []*api.Stage{
+				newTestStage(1, api.DeploymentRollout{MaxFailurePercent: &tolerance}, api.TaskDone, api.TaskDone, api.TaskDone, api.TaskFailed),
+				newTestStage(2, api.DeploymentRollout{}, api.TaskDone),
+			},
+			wantID: 0,
+		},
+	}
+
+	for _, test := range tests {
+		stage := getActiveStage(test.stageList)
+		if test.wantID == 0 {
+			assert.Nil(t, stage, test.name)
+			continue
+		}
+		assert.Equal(t, test.wantID, stage.ID, test.name)
+		assert.Equal(t, test.wantHalted, isStageHalted(stage), test.name)
+	}
+}
+
+func TestIsStagePauseOver(t *testing.T) {
+	now := time.Unix(10000, 0)
+	previousStage := newTestStage(1, api.DeploymentRollout{}, api.TaskDone, api.TaskDone)
+	previousStage.TaskList[0].UpdatedTs = 9000
+	previousStage.TaskList[1].UpdatedTs = 9500
+
+	tests := []struct {
+		name  string
+		stage *api.Stage
+		want  bool
+	}{
+		{
+			name:  "noPause",
+			stage: newTestStage(2, api.DeploymentRollout{}, api.TaskPending),
+			want:  true,
+		},
+		{
+			name:  "pauseOver",
+			stage: newTestStage(2, api.DeploymentRollout{PauseSeconds: 500}, api.TaskPending),
+			want:  true,
+		},
+		{
+			name:  "pausing",
+			stage: newTestStage(2, api.DeploymentRollout{PauseSeconds: 600}, api.TaskPending),
+			want:  false,
+		},
+	}
+
+	for _, test := range tests {
+		got := isStagePauseOver([]*api.Stage{previousStage, test.stage}, test.stage, now)
+		assert.Equal(t, test.want, got, test.name)
+	}
+	// The first stage has no previous stage to pause after.
+	firstStage := newTestStage(1, api.DeploymentRollout{PauseSeconds: 600}, api.TaskPending)
+	assert.True(t, isStagePauseOver([]*api.Stage{firstStage}, firstStage, now))
+}
+
+func TestFormatRolloutValidationResult(t *testing.T) {
+	assert.Equal(t, "NULL", formatRolloutValidationResult(nil))
+	assert.Equal(t, "abc", formatRolloutValidationResult([]byte("abc")))
+	assert.Equal(t, "42", formatRolloutValidationResult(int64(42)))
+	assert.Equal(t, "true", formatRolloutValidationResult(true))
+}
@@ -651,6 +651,54 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.POST("/project/:id/deployment/preview", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		deploymentConfigPreview := &api.DeploymentConfigPreview{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, deploymentConfigPreview); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed preview deployment configuration request").SetInternal(err)
+		}
+		deploymentConfigPreview.ProjectID = id
+
+		project, err := s.store.GetProjectByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", id)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", id))
+		}
+
+		payload := deploymentConfigPreview.Payload
+		if payload == "" {
+			deploymentConfig, err := s.store.GetDeploymentConfigByProjectID(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get deployment configuration for project id: %d", id)).SetInternal(err)
+			}
+			if deploymentConfig == nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Deployment config missing for project ID: %v", id))
+			}
+			payload = deploymentConfig.Payload
+		}
+		schedule, err := api.ValidateAndGetDeploymentSchedule(payload)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid deployment configuration: %v", err)).SetInternal(err)
+		}
+
+		databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{ProjectID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases in project ID: %v", id)).SetInternal(err)
+		}
+		preview, err := getDeploymentPreview(schedule, deploymentConfigPreview.DatabaseName, project.DBNameTemplate, databaseList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to preview deployment configuration").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, preview)
+	})
 }
 
 func (s *Server) setupVCSSQLReviewCI(ctx context.Context, repository *api.Repository) (*vcsPlugin.PullRequest, error) {
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func (s *Server) registerQueryHistoryRoutes(g *echo.Group) {
+	g.GET("/query-history", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+
+		find := &api.QueryHistoryFind{}
+
+		pageToken := c.QueryParams().Get("token")
+		// We use descending order for query history.
+		sinceID, err := unmarshalPageToken(pageToken, api.DESC)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed page token").SetInternal(err)
+		}
+		find.SinceID = &sinceID
+
+		limit := api.DefaultPageSize
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit query parameter is not a number: %s", limitStr)).SetInternal(err)
+			}
+			if limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit query parameter must be positive: %s", limitStr))
+			}
+		}
+		// The statements may be large, so we cap the page size.
+		if limit > api.DefaultPageSize {
+			limit = api.DefaultPageSize
+		}
+		find.Limit = &limit
+
+		// Only the workspace Owner and DBA can view the query history of others.
+		if userIDStr := c.QueryParam("user"); userIDStr != "" {
+			userID, err := strconv.Atoi(userIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("user query parameter is not a number: %s", userIDStr)).SetInternal(err)
+			}
+			find.CreatorID = &userID
+		}
+		if role != api.Owner && role != api.DBA {
+			if find.CreatorID != nil && *find.CreatorID != principalID {
+				return echo.NewHTTPError(http.StatusForbidden, "Only Owner and DBA can view the query history of other users")
+			}
+			find.CreatorID = &principalID
+		}
+
+		if instanceIDStr := c.QueryParam("instance"); instanceIDStr != "" {
+			instanceID, err := strconv.Atoi(instanceIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("instance query parameter is not a number: %s", instanceIDStr)).SetInternal(err)
+			}
+			find.InstanceID = &instanceID
+		}
+		if databaseName := c.QueryParam("database"); databaseName != "" {
+			find.DatabaseName = &databaseName
+		}
+		if statusStr := c.QueryParam("status"); statusStr != "" {
+			status := api.QueryHistoryStatus(statusStr)
+			switch status {
+			case api.QueryHistorySuccess, api.QueryHistoryWarn, api.QueryHistoryError:
+			default:
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid status query parameter: %s", statusStr))
+			}
+			find.Status = &status
+		}
+		if statement := c.QueryParam("statement"); statement != "" {
+			find.Statement = &statement
+		}
+		for name, field := range map[string]**int64{
+			"createdTsAfter":  &find.CreatedTsAfter,
+			"createdTsBefore": &find.CreatedTsBefore,
+			"minDurationNs":   &find.MinDurationNs,
+			"maxDurationNs":   &find.MaxDurationNs,
+		} {
+			valueStr := c.QueryParam(name)
+			if valueStr == "" {
+				continue
+			}
+			value, err := strconv.ParseInt(valueStr, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s query parameter is not a number: %s", name, valueStr)).SetInternal(err)
+			}
+			*field = &value
+		}
+
+		queryHistoryList, err := s.store.FindQueryHistory(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch query history list").SetInternal(err)
+		}
+
+		response := &api.QueryHistoryResponse{
+			QueryHistoryList: queryHistoryList,
+		}
+		nextSinceID := sinceID
+		if len(queryHistoryList) > 0 {
+			// Decrement the ID as we use decreasing order.
+			nextSinceID = queryHistoryList[len(queryHistoryList)-1].ID - 1
+		}
+		if response.NextToken, err = marshalPageToken(nextSinceID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal page token").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, response); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal query history list response").SetInternal(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/store"
+)
+
+func (s *Server) registerSchemaLintRoutes(g *echo.Group) {
+	// Lint the synced schema of a database with the SQL review policy of its environment, and persist the report.
+	g.POST("/database/:id/schema-lint", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database with ID %d", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		policyID, policy, err := s.getSchemaLintPolicy(ctx, database.Instance.EnvironmentID)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("SQL review policy is not configured or disabled for environment %q", database.Instance.Environment.Name))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get SQL review policy").SetInternal(err)
+		}
+
+		report, err := s.lintDatabaseSchema(ctx, database, policyID, policy, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to lint schema for database %q", database.Name)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal schema lint report response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/schema-lint", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		report, err := s.store.GetSchemaLintReportByDatabaseID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema lint report for database ID %d", id)).SetInternal(err)
+		}
+		if report == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema lint report not found for database ID %d", id))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal schema lint report response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Lint all the databases in the environment, and return the environment-wide summary.
+	g.POST("/environment/:id/schema-lint", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		policyID, policy, err := s.getSchemaLintPolicy(ctx, id)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("SQL review policy is not configured or disabled for environment ID %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get SQL review policy").SetInternal(err)
+		}
+
+		databaseList, err := s.findEnvironmentDatabaseList(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases for environment ID %d", id)).SetInternal(err)
+		}
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		var failedList []*api.SchemaLintFailure
+		for _, database := range databaseList {
+			// Lint the databases one by one and report the failed ones in the summary, so that one bad database doesn't block the others.
+			if _, err := s.lintDatabaseSchema(ctx, database, policyID, policy, principalID); err != nil {
+				failedList = append(failedList, &api.SchemaLintFailure{
+					DatabaseID:   database.ID,
+					DatabaseName: database.Name,
+					Error:        err.Error(),
+				})
+			}
+		}
+
+		summary, err := s.getSchemaLintSummary(ctx, id, len(databaseList))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to summarize schema lint reports for environment ID %d", id)).SetInternal(err)
+		}
+		summary.FailedDatabaseList = append(summary.FailedDatabaseList, failedList...)
+
+		return c.JSON(http.StatusOK, summary)
+	})
+
+	g.GET("/environment/:id/schema-lint-summary", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		databaseList, err := s.findEnvironmentDatabaseList(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases for environment ID %d", id)).SetInternal(err)
+		}
+
+		summary, err := s.getSchemaLintSummary(ctx, id, len(databaseList))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to summarize schema lint reports for environment ID %d", id)).SetInternal(err)
+		}
+
+		return c.JSON(http.StatusOK, summary)
+	})
+}
+
+func (s *Server) getSchemaLintPolicy(ctx context.Context, environmentID int) (int, *advisor.SQLReviewPolicy, error) {
+	policy, err := s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{EnvironmentID: &environmentID})
+	if err != nil {
+		return 0, nil, err
+	}
+	policyID, err := s.store.GetSQLReviewPolicyIDByEnvID(ctx, environmentID)
+	if err != nil {
+		return 0, nil, err
+	}
+	return policyID, policy, nil
+}
+
+// lintDatabaseSchema lints the synced schema of the database and upserts the report.
+// If the database fails to lint, its previous report is deleted, so that the stale report isn't counted in the summary.
+func (s *Server) lintDatabaseSchema(ctx context.Context, database *api.Database, policyID int, policy *advisor.SQLReviewPolicy, principalID int) (*api.SchemaLintReport, error) {
+	upsert, err := s.getSchemaLintReportUpsert(ctx, database, policyID, policy, principalID)
+	if err != nil {
+		if deleteErr := s.store.DeleteSchemaLintReport(ctx, &api.SchemaLintReportDelete{DatabaseID: database.ID}); deleteErr != nil {
+			return nil, errors.Wrapf(deleteErr, "failed to delete the stale schema lint report for database %q after lint error: %v", database.Name, err)
+		}
+		return nil, err
+	}
+	return s.store.UpsertSchemaLintReport(ctx, upsert)
+}
+
+// getSchemaLintReportUpsert lints the synced schema of the database against the policy.
+func (s *Server) getSchemaLintReportUpsert(ctx context.Context, database *api.Database, policyID int, policy *advisor.SQLReviewPolicy, principalID int) (*api.SchemaLintReportUpsert, error) {
+	if database.SyncStatus != api.OK {
+		return nil, common.Errorf(common.Invalid, "database %q is not synced, sync status: %s", database.Name, database.SyncStatus)
+	}
+
+	c, err := s.store.NewCatalog(ctx, database.ID, database.Instance.Engine)
+	if err != nil {
+		return nil, common.Wrapf(err, common.Invalid, "failed to create a catalog for database %q", database.Name)
+	}
+	databaseCatalog, ok := c.(*store.Catalog)
+	if !ok || databaseCatalog == nil {
+		return nil, errors.Errorf("failed to get the catalog for database %q", database.Name)
+	}
+
+	result, err := advisor.SchemaLint(databaseCatalog.Database, policy.RuleList)
+	if err != nil {
+		return nil, err
+	}
+
+	upsert := &api.SchemaLintReportUpsert{
+		UpdaterID:  principalID,
+		DatabaseID: database.ID,
+		Status:     api.SchemaLintReportSuccess,
+	}
+	for _, advice := range result.AdviceList {
+		switch advice.Status {
+		case advisor.Error:
+			upsert.ErrorCount++
+			upsert.Status = api.SchemaLintReportError
+		case advisor.Warn:
+			upsert.WarningCount++
+			if upsert.Status == api.SchemaLintReportSuccess {
+				upsert.Status = api.SchemaLintReportWarn
+			}
+		}
+	}
+	payload, err := json.Marshal(&api.SchemaLintReportPayload{
+		PolicyID:        policyID,
+		AdviceList:      result.AdviceList,
+		SkippedRuleList: result.SkippedRuleList,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal schema lint report payload")
+	}
+	upsert.Payload = string(payload)
+
+	return upsert, nil
+}
+
+func (s *Server) findEnvironmentDatabaseList(ctx context.Context, environmentID int) ([]*api.Database, error) {
+	rowStatus := api.Normal
+	instanceList, err := s.store.FindInstance(ctx, &api.InstanceFind{
+		RowStatus:     &rowStatus,
+		EnvironmentID: &environmentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var databaseList []*api.Database
+	for _, instance := range instanceList {
+		list, err := s.store.FindDatabase(ctx, &api.DatabaseFind{InstanceID: &instance.ID})
+		if err != nil {
+			return nil, err
+		}
+		databaseList = append(databaseList, list...)
+	}
+	return databaseList, nil
+}
+
+// getSchemaLintSummary aggregates the persisted schema lint reports of the environment.
+func (s *Server) getSchemaLintSummary(ctx context.Context, environmentID int, databaseCount int) (*api.SchemaLintSummary, error) {
+	reportList, err := s.store.FindSchemaLintReport(ctx, &api.SchemaLintReportFind{EnvironmentID: &environmentID})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &api.SchemaLintSummary{
+		EnvironmentID:       environmentID,
+		DatabaseCount:       databaseCount,
+		LintedDatabaseCount: len(reportList),
+		FailedDatabaseList:  []*api.SchemaLintFailure{},
+		RuleViolationCount:  make(map[string]int),
+	}
+	for _, report := range reportList {
+		switch report.Status {
+		case api.SchemaLintReportSuccess:
+			summary.SuccessDatabaseCount++
+		case api.SchemaLintReportWarn:
+			summary.WarnDatabaseCount++
+		case api.SchemaLintReportError:
+			summary.ErrorDatabaseCount++
+		}
+		summary.ErrorCount += report.ErrorCount
+		summary.WarningCount += report.WarningCount
+
+		payload := &api.SchemaLintReportPayload{}
+		if err := json.Unmarshal([]byte(report.Payload), payload); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal schema lint report payload for database ID %d", report.DatabaseID)
+		}
+		for _, advice := range payload.AdviceList {
+			summary.RuleViolationCount[advice.Title]++
+		}
+	}
+	return summary, nil
+}
@@ -50,6 +50,8 @@ type Server struct {
 	SchemaSyncer       *SchemaSyncer
 	BackupRunner       *BackupRunner
 	AnomalyScanner     *AnomalyScanner
+	GrantRevoker       *DatabaseGrantRevoker
+	TaskRunLogCleaner  *TaskRunLogCleaner
 	runnerWG           sync.WaitGroup
 
 	ActivityManager *ActivityManager
@@ -69,6 +71,12 @@ type Server struct {
 
 	s3Client *s3bb.Client
 
+	// runningQueryMap is the SQL editor queries in progress, so that we can cancel them.
+	runningQueryMap sync.Map // map[queryID]*runningQuery
+
+	// taskApprovalMuList serializes the approvals of each task, striped by the task ID.
+	taskApprovalMuList [taskApprovalMuCount]sync.Mutex
+
 	// boot specifies that whether the server boot correctly
 	cancel context.CancelFunc
 }
@@ -227,6 +235,8 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		taskScheduler.Register(api.TaskGeneral, NewDefaultTaskExecutor)
 
 		taskScheduler.Register(api.TaskDatabaseCreate, NewDatabaseCreateTaskExecutor)
+		taskScheduler.Register(api.TaskDatabaseGrant, NewDatabaseGrantTaskExecutor)
+		taskScheduler.Register(api.TaskDataSourceRequest, NewDataSourceRequestTaskExecutor)
 
 		taskScheduler.Register(api.TaskDatabaseSchemaBaseline, NewSchemaBaselineTaskExecutor)
 
@@ -287,6 +297,12 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 		// Anomaly scanner
 		s.AnomalyScanner = NewAnomalyScanner(s)
 
+		// Database grant revoker
+		s.GrantRevoker = NewDatabaseGrantRevoker(s)
+
+		// Task run log cleaner
+		s.TaskRunLogCleaner = NewTaskRunLogCleaner(s)
+
 		// Metric reporter
 		s.initMetricReporter(config.workspaceID)
 	}
@@ -364,6 +380,8 @@ func NewServer(ctx context.Context, prof Profile) (*Server, error) {
 	s.registerSheetRoutes(apiGroup)
 	s.registerSheetOrganizerRoutes(apiGroup)
 	s.registerAnomalyRoutes(apiGroup)
+	s.registerSchemaLintRoutes(apiGroup)
+	s.registerQueryHistoryRoutes(apiGroup)
 
 	// Register healthz endpoint.
 	e.GET("/healthz", func(c echo.Context) error {
@@ -472,6 +490,9 @@ func (s *Server) Run(ctx context.Context, port int) error {
 		if err := s.TaskScheduler.ClearRunningTasks(ctx); err != nil {
 			return errors.Wrap(err, "failed to clear existing RUNNING tasks before start the task scheduler")
 		}
+		if err := s.TaskScheduler.ResumeTaskRetry(ctx); err != nil {
+			return errors.Wrap(err, "failed to resume the task retries before start the task scheduler")
+		}
 		// runnerWG waits for all goroutines to complete.
 		s.runnerWG.Add(1)
 		go s.TaskScheduler.Run(ctx, &s.runnerWG)
@@ -483,6 +504,10 @@ func (s *Server) Run(ctx context.Context, port int) error {
 		go s.BackupRunner.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
 		go s.AnomalyScanner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.GrantRevoker.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.TaskRunLogCleaner.Run(ctx, &s.runnerWG)
 
 		if s.MetricReporter != nil {
 			s.runnerWG.Add(1)
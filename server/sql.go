@@ -11,6 +11,9 @@ import (
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
@@ -166,10 +169,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing sql statement")
 		}
 		if !exec.Readonly {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support readonly sql statement")
-		}
-		if !validateSQLSelectStatement(exec.Statement) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support SELECT sql statement")
+			return s.adminExecuteSQL(c, exec)
 		}
 
 		instance, err := s.store.GetInstanceByID(ctx, exec.InstanceID)
@@ -180,114 +180,168 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
 		}
 
-		adviceLevel := advisor.Success
-		adviceList := []advisor.Advice{}
-
-		if api.IsSQLReviewSupported(instance.Engine, s.profile.Mode) && exec.DatabaseName != "" {
-			dbType, err := advisorDB.ConvertToAdvisorDBType(string(instance.Engine))
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert db type %v into advisor db type", instance.Engine))
+		stmtList, err := splitSQLEditorStatement(instance.Engine, exec.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to split statement: %s", err.Error())).SetInternal(err)
+		}
+		if len(stmtList) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing sql statement")
+		}
+		for _, stmt := range stmtList {
+			if !validateSQLEditorStatement(instance.Engine, stmt) {
+				allowedStatements := "SELECT, EXPLAIN, SET and CREATE TEMPORARY TABLE"
+				if instance.Engine == db.Postgres {
+					allowedStatements = "SELECT, EXPLAIN and SET"
+				}
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, only support %s sql statements, but found: %s", allowedStatements, stmt))
 			}
+		}
 
-			databaseFind := &api.DatabaseFind{
-				InstanceID: &instance.ID,
-				Name:       &exec.DatabaseName,
-			}
-			dbList, err := s.store.FindDatabase(ctx, databaseFind)
+		// We check each statement separately, so that the advice belongs to the statement result.
+		adviceLevel := advisor.Success
+		var adviceList []advisor.Advice
+		var resultList []*api.SQLStatementResult
+		for _, stmt := range stmtList {
+			stmtAdviceLevel, stmtAdviceList, err := s.sqlEditorCheck(ctx, instance, exec.DatabaseName, stmt)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", exec.DatabaseName, instance.ID)).SetInternal(err)
+				return err
 			}
-			if len(dbList) == 0 {
-				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database `%s` for instance ID: %d not found", exec.DatabaseName, instance.ID))
-			}
-			if len(dbList) > 1 {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("There are multiple database `%s` for instance ID: %d", exec.DatabaseName, instance.ID))
+			adviceLevel = maxAdviceStatus(adviceLevel, stmtAdviceLevel)
+			adviceList = append(adviceList, stmtAdviceList...)
+			resultList = append(resultList, &api.SQLStatementResult{
+				Statement:  stmt,
+				AdviceList: stmtAdviceList,
+			})
+		}
+		if adviceLevel == advisor.Error {
+			if err := s.createSQLEditorQueryActivity(ctx, c, api.ActivityError, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
+				Statement:    exec.Statement,
+				DurationNs:   0,
+				InstanceName: instance.Name,
+				DatabaseName: exec.DatabaseName,
+				Error:        "",
+				AdviceList:   adviceList,
+			}); err != nil {
+				return err
 			}
-			db := dbList[0]
 
-			catalog, err := s.store.NewCatalog(ctx, db.ID, instance.Engine)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create a catalog")
-			}
+			return writeSQLResultSet(c, &api.SQLResultSet{
+				AdviceList: adviceList,
+				ResultList: resultList,
+			})
+		}
 
-			adviceLevel, adviceList, err = s.sqlCheck(
-				ctx,
-				dbType,
-				db.CharacterSet,
-				db.Collation,
-				instance.EnvironmentID,
-				exec.Statement,
-				catalog,
-			)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check SQL review policy").SetInternal(err)
-			}
-
-			if adviceLevel == advisor.Error {
-				if err := s.createSQLEditorQueryActivity(ctx, c, api.ActivityError, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
-					Statement:    exec.Statement,
-					DurationNs:   0,
-					InstanceName: instance.Name,
-					DatabaseName: exec.DatabaseName,
-					Error:        "",
-					AdviceList:   adviceList,
-				}); err != nil {
-					return err
+		// We check the statements before running the query, because we cannot return the error response after flushing the query ID.
+		var stmtInfoList []*sqlEditorStatementInfo
+		for _, stmt := range stmtList {
+			info := &sqlEditorStatementInfo{}
+			if info.masker, err = s.getSQLResultMasker(ctx, instance, exec.DatabaseName, stmt, c.Get(getRoleContextKey()).(api.Role)); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the sensitive columns for the query").SetInternal(err)
+			}
+			switch instance.Engine {
+			case db.Postgres:
+				nodeList, err := parser.Parse(parser.Postgres, parser.ParseContext{}, stmt)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to parse: %s", stmt)).SetInternal(err)
 				}
-
-				resultSet := &api.SQLResultSet{
-					AdviceList: adviceList,
+				if len(nodeList) != 1 {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Expected one statement, but found %d, statement: %s", len(nodeList), stmt))
 				}
-
-				c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-				if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+				_, info.isPostgresExplain = nodeList[0].(*ast.ExplainStmt)
+			case db.MySQL, db.TiDB:
+				// For MySQL and TiDB, we explain the statement in the format we can analyze by ourselves.
+				// The analysis is best effort, so we still run the statement the parser cannot handle without the analysis.
+				if info.mysqlExplainedStatement, err = getMySQLExplainedStatement(stmt); err != nil {
+					log.Debug("Failed to get the explained statement", zap.String("statement", stmt), zap.Error(err))
 				}
-				return nil
 			}
+			stmtInfoList = append(stmtInfoList, info)
 		}
 
+		queryContext, err := s.getQueryContext(ctx, instance, exec.Limit)
+		if err != nil {
+			return err
+		}
+
+		queryCtx, queryID, finish := s.startQuery(ctx, c.Get(getPrincipalIDContextKey()).(int))
+		defer finish()
+		// Flush the query ID before running the query, so that the client can cancel it.
+		c.Response().Header().Set(queryIDHeader, queryID)
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		c.Response().Flush()
+
 		start := time.Now().UnixNano()
 
-		bytes, queryErr := func() ([]byte, error) {
-			driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
+		executedResultList, queryErr := func() ([]*api.SQLStatementResult, error) {
+			driver, err := s.tryGetPrincipalReadOnlyDatabaseDriver(queryCtx, instance, exec.DatabaseName, c.Get(getPrincipalIDContextKey()).(int))
 			if err != nil {
 				return nil, err
 			}
 			defer driver.Close(ctx)
 
-			rowSet, err := driver.Query(ctx, exec.Statement, exec.Limit)
+			sqlDB, err := driver.GetDBConnection(queryCtx, exec.DatabaseName)
 			if err != nil {
 				return nil, err
 			}
-
-			return json.Marshal(rowSet)
-		}()
-
-		if instance.Engine == db.Postgres {
-			stmts, err := parser.Parse(parser.Postgres, parser.ParseContext{}, exec.Statement)
+			// The statements are run in one session, so that the SET statements and temporary tables take effect on the following statements.
+			stmtResultList, err := util.QueryMultiStatements(queryCtx, instance.Engine, sqlDB, stmtList, queryContext)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to parse: %s", exec.Statement)).SetInternal(err)
-			}
-			if len(stmts) != 1 {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Expected one statement, but found %d, statement: %s", len(stmts), exec.Statement))
+				return nil, err
 			}
-			if _, ok := stmts[0].(*ast.ExplainStmt); ok {
-				indexAdvice := checkPostgreSQLIndexHit(exec.Statement, string(bytes))
-				if len(indexAdvice) > 0 {
-					adviceLevel = advisor.Error
-					adviceList = append(adviceList, indexAdvice...)
+
+			var executedResultList []*api.SQLStatementResult
+			for i, stmtResult := range stmtResultList {
+				result, info := resultList[i], stmtInfoList[i]
+				result.DurationNs = stmtResult.DurationNs
+				executedResultList = append(executedResultList, result)
+				if stmtResult.Error != nil {
+					result.Error = stmtResult.Error.Error()
+					return executedResultList, stmtResult.Error
+				}
+
+				info.masker.maskRowSet(stmtResult.RowSet)
+				bytes, err := json.Marshal(stmtResult.RowSet)
+				if err != nil {
+					return executedResultList, err
+				}
+				result.Data = string(bytes)
+
+				var explainAdviceList []advisor.Advice
+				if info.isPostgresExplain {
+					explainAdviceList = checkPostgreSQLIndexHit(result.Statement, result.Data)
+				}
+				if info.mysqlExplainedStatement != "" {
+					// The analysis is best effort, so we don't fail the query on the analysis error.
+					if explainAdviceList, err = explainMySQLQuery(queryCtx, driver, instance.Engine, info.mysqlExplainedStatement); err != nil {
+						log.Debug("Failed to analyze the query plan",
+							zap.String("statement", info.mysqlExplainedStatement),
+							zap.Error(err),
+						)
+					}
+				}
+				for _, advice := range explainAdviceList {
+					adviceLevel = maxAdviceStatus(adviceLevel, advice.Status)
 				}
+				result.AdviceList = append(result.AdviceList, explainAdviceList...)
+				adviceList = append(adviceList, explainAdviceList...)
 			}
-		}
+			return executedResultList, nil
+		}()
 
+		okAdvice := advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		}
 		if len(adviceList) == 0 {
-			adviceList = append(adviceList, advisor.Advice{
-				Status:  advisor.Success,
-				Code:    advisor.Ok,
-				Title:   "OK",
-				Content: "",
-			})
+			adviceList = append(adviceList, okAdvice)
+		}
+		for _, result := range executedResultList {
+			if len(result.AdviceList) == 0 {
+				result.AdviceList = append(result.AdviceList, okAdvice)
+			}
 		}
 
 		level := api.ActivityInfo
@@ -302,20 +356,24 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			level = api.ActivityError
 			errMessage = queryErr.Error()
 		}
-		if err := s.createSQLEditorQueryActivity(ctx, c, level, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
+		// The response header has been flushed, so we cannot return the error response, and the error is logged by createSQLEditorQueryActivity.
+		_ = s.createSQLEditorQueryActivity(ctx, c, level, exec.InstanceID, api.ActivitySQLEditorQueryPayload{
 			Statement:    exec.Statement,
 			DurationNs:   time.Now().UnixNano() - start,
 			InstanceName: instance.Name,
 			DatabaseName: exec.DatabaseName,
 			Error:        errMessage,
 			AdviceList:   adviceList,
-		}); err != nil {
-			return err
-		}
+		})
 
-		resultSet := &api.SQLResultSet{AdviceList: adviceList}
+		resultSet := &api.SQLResultSet{
+			AdviceList: adviceList,
+			ResultList: executedResultList,
+		}
+		if len(executedResultList) > 0 {
+			resultSet.Data = executedResultList[len(executedResultList)-1].Data
+		}
 		if queryErr == nil {
-			resultSet.Data = string(bytes)
 			log.Debug("Query result advice",
 				zap.String("statement", exec.Statement),
 				zap.Array("advice", advisor.ZapAdviceArray(resultSet.AdviceList)),
@@ -324,25 +382,31 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			resultSet.Error = queryErr.Error()
 			if s.profile.Mode == common.ReleaseModeDev {
 				log.Error("Failed to execute query",
-					zap.Error(err),
+					zap.Error(queryErr),
 					zap.String("statement", exec.Statement),
 					zap.Array("advice", advisor.ZapAdviceArray(resultSet.AdviceList)),
 				)
 			} else {
 				log.Debug("Failed to execute query",
-					zap.Error(err),
+					zap.Error(queryErr),
 					zap.String("statement", exec.Statement),
 					zap.Array("advice", advisor.ZapAdviceArray(resultSet.AdviceList)),
 				)
 			}
 		}
 
-		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+			// The response header has been flushed, so we cannot return the error response.
+			log.Warn("Failed to marshal sql result set response", zap.Int("instance_id", exec.InstanceID), zap.Error(err))
 		}
 		return nil
 	})
+
+	g.POST("/sql/query/:queryID/cancel", s.cancelQuery)
+
+	g.POST("/sql/export", s.exportSQL)
+
+	g.POST("/sql/batch-query", s.batchQuerySQL)
 }
 
 func (s *Server) syncInstance(ctx context.Context, instance *api.Instance) ([]string, error) {
@@ -634,6 +698,161 @@ func validateSQLSelectStatement(sqlStatement string) bool {
 	return false
 }
 
+// sqlEditorStatementInfo is the information of a statement in the readonly SQL editor script, which is collected before the execution.
+type sqlEditorStatementInfo struct {
+	masker                  *sqlResultMasker
+	isPostgresExplain       bool
+	mysqlExplainedStatement string
+}
+
+// splitSQLEditorStatement splits the SQL editor script into statements.
+func splitSQLEditorStatement(engine db.Type, statement string) ([]string, error) {
+	var stmtList []string
+	if engine == db.SQLite {
+		if err := util.ApplyMultiStatements(strings.NewReader(statement), func(stmt string) error {
+			stmtList = append(stmtList, stmt)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return stmtList, nil
+	}
+
+	singleSQLList, err := parser.SplitMultiSQL(parser.EngineType(engine), statement)
+	if err != nil {
+		return nil, err
+	}
+	for _, singleSQL := range singleSQLList {
+		if strings.TrimSpace(strings.TrimRight(strings.TrimSpace(singleSQL.Text), ";")) == "" {
+			continue
+		}
+		stmtList = append(stmtList, singleSQL.Text)
+	}
+	return stmtList, nil
+}
+
+// sqlEditorSessionVariableAllowList is the session variables the readonly SQL editor script can set, keyed by the engine.
+// They only change how the queries are run and how the results are presented, so they cannot lift the readonly guarantee.
+var sqlEditorSessionVariableAllowList = map[db.Type]map[string]bool{
+	db.MySQL: mysqlSQLEditorSessionVariableAllowList,
+	db.TiDB:  mysqlSQLEditorSessionVariableAllowList,
+	db.Postgres: {
+		"search_path":          true,
+		"timezone":             true,
+		"datestyle":            true,
+		"intervalstyle":        true,
+		"extra_float_digits":   true,
+		"bytea_output":         true,
+		"client_encoding":      true,
+		"application_name":     true,
+		"statement_timeout":    true,
+		"work_mem":             true,
+		"jit":                  true,
+		"random_page_cost":     true,
+		"enable_seqscan":       true,
+		"enable_indexscan":     true,
+		"enable_indexonlyscan": true,
+		"enable_bitmapscan":    true,
+		"enable_hashjoin":      true,
+		"enable_mergejoin":     true,
+		"enable_nestloop":      true,
+		"enable_sort":          true,
+	},
+}
+
+var mysqlSQLEditorSessionVariableAllowList = map[string]bool{
+	strings.ToLower(tidbast.SetNames):   true,
+	strings.ToLower(tidbast.SetCharset): true,
+	"time_zone":                         true,
+	"sql_mode":                          true,
+	"character_set_client":              true,
+	"character_set_connection":          true,
+	"character_set_results":             true,
+	"collation_connection":              true,
+	"lc_time_names":                     true,
+	"sql_select_limit":                  true,
+	"group_concat_max_len":              true,
+	"div_precision_increment":           true,
+	"max_execution_time":                true,
+	"optimizer_switch":                  true,
+	"cte_max_recursion_depth":           true,
+}
+
+// validateSQLEditorStatement returns true if the single statement is allowed in the readonly SQL editor script.
+// Besides the SELECT and EXPLAIN queries, the statements changing the session only, such as SET and CREATE TEMPORARY TABLE, are allowed.
+// The script runs in a READ ONLY transaction, where Postgres rejects creating the temporary tables, so they are not allowed for Postgres.
+func validateSQLEditorStatement(engine db.Type, statement string) bool {
+	formattedStr := strings.ToUpper(strings.TrimSpace(statement))
+	if regexp.MustCompile(`^SET\s+`).MatchString(formattedStr) {
+		return isSQLEditorSessionVariableStatement(engine, statement)
+	}
+	whiteListRegs := []string{`^SELECT\s+?`, `^EXPLAIN\s+?`}
+	if engine != db.Postgres {
+		whiteListRegs = append(whiteListRegs, `^CREATE\s+(TEMPORARY|TEMP)\s+TABLE\s+`)
+	}
+	for _, reg := range whiteListRegs {
+		if matchResult, _ := regexp.MatchString(reg, formattedStr); matchResult {
+			return true
+		}
+	}
+	return false
+}
+
+// isSQLEditorSessionVariableStatement returns true if the SET statement only sets the user variables or the allowed session variables.
+// The SET statements of the engines we cannot parse are not allowed.
+func isSQLEditorSessionVariableStatement(engine db.Type, statement string) bool {
+	allowList := sqlEditorSessionVariableAllowList[engine]
+	switch engine {
+	case db.MySQL, db.TiDB:
+		nodeList, _, err := tidbparser.New().Parse(statement, "", "")
+		if err != nil || len(nodeList) != 1 {
+			return false
+		}
+		// SET TRANSACTION and SET PASSWORD are parsed as the other statements or the variables not in the allow list.
+		node, ok := nodeList[0].(*tidbast.SetStmt)
+		if !ok {
+			return false
+		}
+		for _, variable := range node.Variables {
+			if variable.IsGlobal {
+				return false
+			}
+			// The user variables, such as @a, only live in the session.
+			if !variable.IsSystem && variable.Name != tidbast.SetNames && variable.Name != tidbast.SetCharset {
+				continue
+			}
+			if !allowList[strings.ToLower(variable.Name)] {
+				return false
+			}
+		}
+		return true
+	case db.Postgres:
+		result, err := pgquery.Parse(statement)
+		if err != nil || len(result.Stmts) != 1 {
+			return false
+		}
+		// SET TRANSACTION, SET SESSION CHARACTERISTICS and SET ROLE are parsed as the variables not in the allow list.
+		node := result.Stmts[0].Stmt.GetVariableSetStmt()
+		if node == nil {
+			return false
+		}
+		return allowList[strings.ToLower(node.Name)]
+	default:
+		return false
+	}
+}
+
+// maxAdviceStatus returns the more severe advice status.
+func maxAdviceStatus(a, b advisor.Status) advisor.Status {
+	if a == advisor.Error || b == advisor.Error {
+		return advisor.Error
+	}
+	if a == advisor.Warn || b == advisor.Warn {
+		return advisor.Warn
+	}
+	return advisor.Success
+}
+
 func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorQueryPayload) error {
 	activityBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -663,9 +882,83 @@ func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Contex
 			zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
 	}
+
+	status := api.QueryHistorySuccess
+	switch level {
+	case api.ActivityWarn:
+		status = api.QueryHistoryWarn
+	case api.ActivityError:
+		status = api.QueryHistoryError
+	}
+	if _, err := s.store.CreateQueryHistory(ctx, &api.QueryHistoryCreate{
+		CreatorID:    activityCreate.CreatorID,
+		InstanceID:   containerID,
+		DatabaseName: payload.DatabaseName,
+		Statement:    payload.Statement,
+		Status:       status,
+		DurationNs:   payload.DurationNs,
+		Error:        payload.Error,
+	}); err != nil {
+		log.Warn("Failed to create query history after executing sql statement",
+			zap.String("database_name", payload.DatabaseName),
+			zap.String("instance_name", payload.InstanceName),
+			zap.String("statement", payload.Statement),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create query history").SetInternal(err)
+	}
 	return nil
 }
 
+// sqlEditorCheck runs the SQL review policy of the instance environment against the statement executed in the SQL editor.
+// The returned error is the echo HTTP error.
+func (s *Server) sqlEditorCheck(ctx context.Context, instance *api.Instance, databaseName string, statement string) (advisor.Status, []advisor.Advice, error) {
+	adviceLevel := advisor.Success
+	adviceList := []advisor.Advice{}
+
+	if api.IsSQLReviewSupported(instance.Engine, s.profile.Mode) && databaseName != "" {
+		dbType, err := advisorDB.ConvertToAdvisorDBType(string(instance.Engine))
+		if err != nil {
+			return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert db type %v into advisor db type", instance.Engine))
+		}
+
+		databaseFind := &api.DatabaseFind{
+			InstanceID: &instance.ID,
+			Name:       &databaseName,
+		}
+		dbList, err := s.store.FindDatabase(ctx, databaseFind)
+		if err != nil {
+			return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", databaseName, instance.ID)).SetInternal(err)
+		}
+		if len(dbList) == 0 {
+			return advisor.Error, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database `%s` for instance ID: %d not found", databaseName, instance.ID))
+		}
+		if len(dbList) > 1 {
+			return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("There are multiple database `%s` for instance ID: %d", databaseName, instance.ID))
+		}
+		db := dbList[0]
+
+		catalog, err := s.store.NewCatalog(ctx, db.ID, instance.Engine)
+		if err != nil {
+			return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create a catalog")
+		}
+
+		adviceLevel, adviceList, err = s.sqlCheck(
+			ctx,
+			dbType,
+			db.CharacterSet,
+			db.Collation,
+			instance.EnvironmentID,
+			statement,
+			catalog,
+		)
+		if err != nil {
+			return advisor.Error, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check SQL review policy").SetInternal(err)
+		}
+	}
+
+	return adviceLevel, adviceList, nil
+}
+
 func (s *Server) sqlCheck(
 	ctx context.Context,
 	dbType advisorDB.Type,
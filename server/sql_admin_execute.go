@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// pgDataModifyingNodeRegexp matches the data-modifying statements in the JSON parse tree of pg_query,
+// e.g. the DELETE in "WITH t AS (DELETE FROM t1 RETURNING *) SELECT * FROM t".
+var pgDataModifyingNodeRegexp = regexp.MustCompile(`"(InsertStmt|UpdateStmt|DeleteStmt)":`)
+
+// adminExecuteSQL runs the statement in the admin execution mode of the SQL editor.
+// Unlike the readonly query, the statement can change the data and schema, so that
+//  1. only workspace owners and DBAs can use it, and only workspace owners can use it in the protected environments.
+//  2. the justification is required, and the SQL review policy is checked before execution.
+//  3. the DML statements are run in a transaction, which is only committed after the caller confirms the affected row count.
+//  4. every execution is recorded as an activity.
+func (s *Server) adminExecuteSQL(c echo.Context, exec *api.SQLExecute) error {
+	ctx := c.Request().Context()
+	role := c.Get(getRoleContextKey()).(api.Role)
+	if role != api.Owner && role != api.DBA {
+		return echo.NewHTTPError(http.StatusForbidden, "Only workspace owners and DBAs can execute statements in the admin execution mode")
+	}
+	if strings.TrimSpace(exec.Justification) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing justification for the admin execution mode")
+	}
+	if exec.DatabaseName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing databaseName for the admin execution mode")
+	}
+
+	instance, err := s.store.GetInstanceByID(ctx, exec.InstanceID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exec.InstanceID)).SetInternal(err)
+	}
+	if instance == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
+	}
+	if instance.Environment.Tier == api.EnvironmentTierValueProtected && role != api.Owner {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Only workspace owners can execute statements in the admin execution mode in the protected environment %q", instance.Environment.Name))
+	}
+
+	var engine parser.EngineType
+	switch instance.Engine {
+	case db.MySQL:
+		engine = parser.MySQL
+	case db.TiDB:
+		engine = parser.TiDB
+	case db.Postgres:
+		engine = parser.Postgres
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The admin execution mode doesn't support database engine %s", instance.Engine))
+	}
+	singleSQLList, err := parser.SplitMultiSQL(engine, exec.Statement)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to split statement: %s", err.Error())).SetInternal(err)
+	}
+	var stmtList []string
+	dmlCount := 0
+	for _, singleSQL := range singleSQLList {
+		stmtList = append(stmtList, singleSQL.Text)
+		if isDMLStatement(engine, singleSQL.Text) {
+			dmlCount++
+		}
+	}
+	if len(stmtList) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, missing sql statement")
+	}
+	isDML := dmlCount == len(stmtList)
+	if dmlCount > 0 && !isDML {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, cannot mix DML statements with other statements in the admin execution mode")
+	}
+
+	payload := api.ActivitySQLEditorAdminExecutePayload{
+		Statement:     exec.Statement,
+		InstanceName:  instance.Name,
+		DatabaseName:  exec.DatabaseName,
+		Justification: exec.Justification,
+	}
+	adviceLevel, adviceList, err := s.sqlEditorCheck(ctx, instance, exec.DatabaseName, exec.Statement)
+	if err != nil {
+		return err
+	}
+	payload.AdviceList = adviceList
+	if adviceLevel == advisor.Error {
+		if err := s.createSQLEditorAdminExecuteActivity(ctx, c, api.ActivityError, exec.InstanceID, payload); err != nil {
+			return err
+		}
+		return writeSQLResultSet(c, &api.SQLResultSet{AdviceList: adviceList})
+	}
+
+	start := time.Now().UnixNano()
+	resultSet := &api.SQLResultSet{AdviceList: adviceList}
+	execErr := func() error {
+		driver, err := s.getAdminDatabaseDriver(ctx, instance, exec.DatabaseName)
+		if err != nil {
+			return err
+		}
+		defer driver.Close(ctx)
+
+		if !isDML {
+			return driver.Execute(ctx, exec.Statement)
+		}
+		affectedRowCount, committed, err := executeDMLInTransaction(ctx, driver, exec.DatabaseName, stmtList, exec.ConfirmedAffectedRowCount)
+		resultSet.AffectedRowCount = affectedRowCount
+		if err != nil {
+			return err
+		}
+		if !committed && exec.ConfirmedAffectedRowCount != nil {
+			return errors.Errorf("the affected row count %d is different from the confirmed affected row count %d, the transaction is rolled back", affectedRowCount, *exec.ConfirmedAffectedRowCount)
+		}
+		resultSet.RequireConfirmation = !committed
+		return nil
+	}()
+
+	// The dry run for the affected row count confirmation doesn't change anything, so we don't record it.
+	if resultSet.RequireConfirmation {
+		return writeSQLResultSet(c, resultSet)
+	}
+
+	level := api.ActivityInfo
+	if adviceLevel == advisor.Warn {
+		level = api.ActivityWarn
+	}
+	if execErr != nil {
+		level = api.ActivityError
+		payload.Error = execErr.Error()
+		resultSet.Error = execErr.Error()
+		log.Debug("Failed to execute statement in the admin execution mode",
+			zap.Error(execErr),
+			zap.String("statement", exec.Statement),
+		)
+	}
+	payload.DurationNs = time.Now().UnixNano() - start
+	payload.AffectedRowCount = resultSet.AffectedRowCount
+	if err := s.createSQLEditorAdminExecuteActivity(ctx, c, level, exec.InstanceID, payload); err != nil {
+		return err
+	}
+
+	return writeSQLResultSet(c, resultSet)
+}
+
+// executeDMLInTransaction executes the DML statements in a transaction and returns the affected row count.
+// The transaction is only committed if the affected row count is the same as the confirmed one, otherwise it's rolled back.
+func executeDMLInTransaction(ctx context.Context, driver db.Driver, databaseName string, stmtList []string, confirmedAffectedRowCount *int64) (int64, bool, error) {
+	sqlDB, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return 0, false, err
+	}
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var affectedRowCount int64
+	for _, stmt := range stmtList {
+		sqlResult, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			return 0, false, err
+		}
+		rowCount, err := sqlResult.RowsAffected()
+		if err != nil {
+			return 0, false, err
+		}
+		affectedRowCount += rowCount
+	}
+
+	if confirmedAffectedRowCount == nil || *confirmedAffectedRowCount != affectedRowCount {
+		if err := tx.Rollback(); err != nil {
+			return 0, false, err
+		}
+		return affectedRowCount, false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return affectedRowCount, true, nil
+}
+
+// isDMLStatement returns true if the statement may change the data, which requires the affected row count confirmation.
+// The statement is classified by the parser, and the statements that the parser fails to parse or classify are regarded as DML for safety.
+func isDMLStatement(engine parser.EngineType, statement string) bool {
+	switch engine {
+	case parser.MySQL, parser.TiDB:
+		return isMySQLDMLStatement(statement)
+	case parser.Postgres:
+		return isPostgresDMLStatement(statement)
+	}
+	return true
+}
+
+func isMySQLDMLStatement(statement string) bool {
+	p := tidbparser.New()
+	// To support MySQL8 window function syntax.
+	// See https://github.com/bytebase/bytebase/issues/175.
+	p.EnableWindowFunc(true)
+	nodeList, _, err := p.Parse(statement, "", "")
+	if err != nil || len(nodeList) != 1 {
+		return true
+	}
+	switch node := nodeList[0].(type) {
+	case tidbast.DDLNode:
+		return false
+	case *tidbast.SelectStmt, *tidbast.SetOprStmt, *tidbast.ShowStmt, *tidbast.UseStmt, *tidbast.SetStmt:
+		return false
+	case *tidbast.ExplainStmt:
+		// EXPLAIN ANALYZE executes the statement.
+		return node.Analyze
+	}
+	return true
+}
+
+func isPostgresDMLStatement(statement string) bool {
+	nodeList, err := parser.Parse(parser.Postgres, parser.ParseContext{}, statement)
+	if err != nil || len(nodeList) != 1 {
+		return true
+	}
+	switch node := nodeList[0].(type) {
+	case ast.DDLNode:
+		return false
+	case *ast.SelectStmt, *ast.ExplainStmt:
+		// The parser only converts EXPLAIN SELECT, and SELECT can still change the data in its WITH clause.
+		if explain, ok := node.(*ast.ExplainStmt); ok && explain.Statement == nil {
+			return true
+		}
+		tree, err := pgquery.ParseToJSON(statement)
+		if err != nil {
+			return true
+		}
+		return pgDataModifyingNodeRegexp.MatchString(tree)
+	}
+	return true
+}
+
+func writeSQLResultSet(c echo.Context, resultSet *api.SQLResultSet) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+	}
+	return nil
+}
+
+func (s *Server) createSQLEditorAdminExecuteActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorAdminExecutePayload) error {
+	activityBytes, err := json.Marshal(payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+		Type:        api.ActivitySQLEditorAdminExecute,
+		ContainerID: containerID,
+		Level:       level,
+		Comment: fmt.Sprintf("Executed `%q` in database %q of instance %q in the admin execution mode, justification: %q.",
+			payload.Statement, payload.DatabaseName, payload.InstanceName, payload.Justification),
+		Payload: string(activityBytes),
+	}
+	if _, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		log.Warn("Failed to create activity after executing statement in the admin execution mode",
+			zap.String("database_name", payload.DatabaseName),
+			zap.String("instance_name", payload.InstanceName),
+			zap.String("statement", payload.Statement),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// batchQueryMaxConcurrency is the maximum number of databases queried concurrently in a batch query.
+const batchQueryMaxConcurrency = 8
+
+// batchQuerySQL runs the readonly query against the databases of a project matched by the label selector,
+// and returns the result of each database with the merged result.
+func (s *Server) batchQuerySQL(c echo.Context) error {
+	ctx := c.Request().Context()
+	batchQuery := &api.SQLBatchQuery{}
+	if err := jsonapi.UnmarshalPayload(c.Request().Body, batchQuery); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql batch query request").SetInternal(err)
+	}
+	if batchQuery.ProjectID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql batch query request, missing projectId")
+	}
+	if !validateSQLSelectStatement(batchQuery.Statement) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql batch query request, only support SELECT sql statement")
+	}
+	selector := &api.LabelSelector{}
+	if batchQuery.Selector != "" {
+		if err := json.Unmarshal([]byte(batchQuery.Selector), selector); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql batch query request, invalid selector").SetInternal(err)
+		}
+	}
+
+	project, err := s.store.GetProjectByID(ctx, batchQuery.ProjectID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", batchQuery.ProjectID)).SetInternal(err)
+	}
+	if project == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", batchQuery.ProjectID))
+	}
+	// Only the workspace Owner and DBA and the project members can query the databases of the project.
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	role := c.Get(getRoleContextKey()).(api.Role)
+	if role != api.Owner && role != api.DBA {
+		isMember := false
+		for _, projectMember := range project.ProjectMemberList {
+			if projectMember.PrincipalID == principalID {
+				isMember = true
+				break
+			}
+		}
+		if !isMember {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Only the project members can query the databases of project %q", project.Name))
+		}
+	}
+
+	okSyncStatus := api.OK
+	databaseList, err := s.store.FindDatabase(ctx, &api.DatabaseFind{
+		ProjectID:  &project.ID,
+		SyncStatus: &okSyncStatus,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases of project ID: %v", project.ID)).SetInternal(err)
+	}
+	matchedDatabaseList, err := getDatabaseListBySelector(databaseList, selector)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to match databases with the selector").SetInternal(err)
+	}
+	if len(matchedDatabaseList) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("No database in project %q matches the selector", project.Name))
+	}
+
+	// We check the statement and prepare the masker and query context for each database before running the query,
+	// because we cannot return the error response after flushing the query ID.
+	var queryList []*batchQueryDatabase
+	for _, database := range matchedDatabaseList {
+		// The SQL review policy is checked against each database, since the databases can be in different environments.
+		adviceLevel, adviceList, err := s.sqlEditorCheck(ctx, database.Instance, database.Name, batchQuery.Statement)
+		if err != nil {
+			return err
+		}
+		if adviceLevel == advisor.Error {
+			queryList = append(queryList, &batchQueryDatabase{
+				database: database,
+				blocked:  true,
+				result: &api.SQLDatabaseResult{
+					DatabaseID:   database.ID,
+					DatabaseName: database.Name,
+					InstanceName: database.Instance.Name,
+					Error:        "The query is blocked by the SQL review policy",
+					AdviceList:   adviceList,
+				},
+			})
+			continue
+		}
+		masker, err := s.getSQLResultMasker(ctx, database.Instance, database.Name, batchQuery.Statement, role)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the sensitive columns for the query on database %q", database.Name)).SetInternal(err)
+		}
+		queryContext, err := s.getQueryContext(ctx, database.Instance, batchQuery.Limit)
+		if err != nil {
+			return err
+		}
+		queryList = append(queryList, &batchQueryDatabase{
+			database:     database,
+			dataSource:   getReadOnlyDataSource(database.Instance, database.ID, principalID),
+			masker:       masker,
+			queryContext: queryContext,
+			result: &api.SQLDatabaseResult{
+				DatabaseID:   database.ID,
+				DatabaseName: database.Name,
+				InstanceName: database.Instance.Name,
+				AdviceList:   adviceList,
+			},
+		})
+	}
+
+	queryCtx, queryID, finish := s.startQuery(ctx, principalID)
+	defer finish()
+	// Flush the query ID before running the query, so that the client can cancel it.
+	c.Response().Header().Set(queryIDHeader, queryID)
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+
+	// Fan out the query with bounded concurrency.
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, batchQueryMaxConcurrency)
+	for _, query := range queryList {
+		if query.blocked {
+			continue
+		}
+		wg.Add(1)
+		go func(query *batchQueryDatabase) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			query.run(queryCtx, batchQuery.Statement)
+		}(query)
+	}
+	wg.Wait()
+
+	resultSet := &api.SQLBatchResultSet{}
+	var rowSetList []batchQueryRowSet
+	for _, query := range queryList {
+		resultSet.ResultList = append(resultSet.ResultList, query.result)
+		if query.result.Error == "" {
+			rowSetList = append(rowSetList, batchQueryRowSet{databaseName: query.database.Name, rowSet: query.rowSet})
+		}
+
+		// Every database query is recorded, so that it can be audited like the query in the SQL editor.
+		level := api.ActivityInfo
+		if query.result.Error != "" {
+			level = api.ActivityError
+		}
+		// The response header has been flushed, so we cannot return the error response, and the error is logged by createSQLEditorQueryActivity.
+		_ = s.createSQLEditorQueryActivity(ctx, c, level, query.database.InstanceID, api.ActivitySQLEditorQueryPayload{
+			Statement:    batchQuery.Statement,
+			DurationNs:   query.result.DurationNs,
+			InstanceName: query.database.Instance.Name,
+			DatabaseName: query.database.Name,
+			Error:        query.result.Error,
+			AdviceList:   query.result.AdviceList,
+		})
+	}
+	if mergedData, err := getBatchQueryMergedData(rowSetList); err != nil {
+		// The merged result is a convenience on top of the result of each database, so we still return the latter.
+		log.Warn("Failed to merge the batch query result", zap.Int("project_id", project.ID), zap.Error(err))
+	} else {
+		resultSet.MergedData = mergedData
+	}
+
+	if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+		// The response header has been flushed, so we cannot return the error response.
+		log.Warn("Failed to marshal sql batch result set response", zap.Int("project_id", project.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// getBatchQueryMergedData returns the merged row set of the batch query marshalled into a JSON.
+func getBatchQueryMergedData(rowSetList []batchQueryRowSet) (string, error) {
+	mergedRowSet, err := mergeBatchQueryRowSet(rowSetList)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to merge the batch query result")
+	}
+	mergedData, err := json.Marshal(mergedRowSet)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the merged batch query result")
+	}
+	return string(mergedData), nil
+}
+
+// batchQueryDatabase is the query on a database in the batch query.
+type batchQueryDatabase struct {
+	database     *api.Database
+	dataSource   *api.DataSource
+	masker       *sqlResultMasker
+	queryContext *db.QueryContext
+	// blocked is true if the query is blocked by the SQL review policy and not run.
+	blocked bool
+
+	rowSet []interface{}
+	result *api.SQLDatabaseResult
+}
+
+func (q *batchQueryDatabase) run(ctx context.Context, statement string) {
+	start := time.Now()
+	err := func() error {
+		driver, err := getReadOnlyDatabaseDriverWithDataSource(ctx, q.database.Instance, q.database.Name, q.dataSource)
+		if err != nil {
+			return err
+		}
+		defer driver.Close(ctx)
+
+		rowSet, err := driver.Query(ctx, statement, q.queryContext)
+		if err != nil {
+			return err
+		}
+		q.masker.maskRowSet(rowSet)
+		data, err := json.Marshal(rowSet)
+		if err != nil {
+			return err
+		}
+		q.rowSet = rowSet
+		q.result.Data = string(data)
+		return nil
+	}()
+	q.result.DurationNs = time.Since(start).Nanoseconds()
+	if err != nil {
+		q.result.Error = err.Error()
+	}
+}
+
+// getDatabaseListBySelector returns the databases matched by the label selector in the ascending order of database name.
+// All databases are matched if the selector has no expression.
+func getDatabaseListBySelector(databaseList []*api.Database, selector *api.LabelSelector) ([]*api.Database, error) {
+	var matchedDatabaseList []*api.Database
+	for _, database := range databaseList {
+		if len(selector.MatchExpressions) > 0 {
+			var labelList []*api.DatabaseLabel
+			if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal labels of database %q", database.Name)
+			}
+			labels := make(map[string]string)
+			for _, label := range labelList {
+				labels[label.Key] = label.Value
+			}
+			if !isMatchExpressions(labels, selector.MatchExpressions) {
+				continue
+			}
+		}
+		matchedDatabaseList = append(matchedDatabaseList, database)
+	}
+	sort.SliceStable(matchedDatabaseList, func(i, j int) bool {
+		return matchedDatabaseList[i].Name < matchedDatabaseList[j].Name
+	})
+	return matchedDatabaseList, nil
+}
+
+// batchQueryRowSet is the row set of a database in the batch query.
+type batchQueryRowSet struct {
+	databaseName string
+	// rowSet is the same as the one returned by the driver Query.
+	rowSet []interface{}
+}
+
+// mergeBatchQueryRowSet merges the row sets with the database name as the first column.
+// The row sets whose column names are different from the first one are excluded.
+func mergeBatchQueryRowSet(rowSetList []batchQueryRowSet) ([]interface{}, error) {
+	columnNames := []string{"database"}
+	columnTypeNames := []string{"TEXT"}
+	data := []interface{}{}
+	var firstColumnNames []string
+	for i, rowSet := range rowSetList {
+		names, rowList, err := convertRowSet(rowSet.rowSet)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			typeNames, ok := rowSet.rowSet[1].([]string)
+			if !ok {
+				return nil, errors.Errorf("expect column type names of []string, but got %T", rowSet.rowSet[1])
+			}
+			firstColumnNames = names
+			columnNames = append(columnNames, names...)
+			columnTypeNames = append(columnTypeNames, typeNames...)
+		} else if !equalStringList(firstColumnNames, names) {
+			continue
+		}
+		for _, row := range rowList {
+			data = append(data, append([]interface{}{rowSet.databaseName}, row...))
+		}
+	}
+	return []interface{}{columnNames, columnTypeNames, data}, nil
+}
+
+func equalStringList(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetDatabaseListBySelector(t *testing.T) {
+	databaseList := []*api.Database{
+		{ID: 1, Name: "tenant_b", Labels: `[{"key":"bb.tenant","value":"b"},{"key":"bb.environment","value":"Prod"}]`},
+		{ID: 2, Name: "tenant_a", Labels: `[{"key":"bb.tenant","value":"a"},{"key":"bb.environment","value":"Prod"}]`},
+		{ID: 3, Name: "tenant_c", Labels: `[{"key":"bb.environment","value":"Test"}]`},
+	}
+	tests := []struct {
+		selector *api.LabelSelector
+		wantID   []int
+	}{
+		{
+			selector: &api.LabelSelector{},
+			wantID:   []int{2, 1, 3},
+		},
+		{
+			selector: &api.LabelSelector{
+				MatchExpressions: []*api.LabelSelectorRequirement{
+					{Key: "bb.environment", Operator: api.InOperatorType, Values: []string{"Prod"}},
+				},
+			},
+			wantID: []int{2, 1},
+		},
+		{
+			selector: &api.LabelSelector{
+				MatchExpressions: []*api.LabelSelectorRequirement{
+					{Key: "bb.environment", Operator: api.InOperatorType, Values: []string{"Prod", "Test"}},
+					{Key: "bb.tenant", Operator: api.ExistsOperatorType},
+				},
+			},
+			wantID: []int{2, 1},
+		},
+	}
+
+	for _, test := range tests {
+		matchedList, err := getDatabaseListBySelector(databaseList, test.selector)
+		require.NoError(t, err)
+		var idList []int
+		for _, database := range matchedList {
+			idList = append(idList, database.ID)
+		}
+		require.Equal(t, test.wantID, idList)
+	}
+}
+
+func TestMergeBatchQueryRowSet(t *testing.T) {
+	rowSetList := []batchQueryRowSet{
+		{
+			databaseName: "tenant_a",
+			rowSet:       []interface{}{[]string{"id", "name"}, []string{"INT", "TEXT"}, []interface{}{[]interface{}{int64(1), "x"}}},
+		},
+		{
+			databaseName: "tenant_b",
+			rowSet:       []interface{}{[]string{"id", "name"}, []string{"INT", "TEXT"}, []interface{}{[]interface{}{int64(2), "y"}, []interface{}{int64(3), "z"}}},
+		},
+		{
+			// The database with different columns is excluded.
+			databaseName: "tenant_c",
+			rowSet:       []interface{}{[]string{"id"}, []string{"INT"}, []interface{}{[]interface{}{int64(4)}}},
+		},
+	}
+
+	merged, err := mergeBatchQueryRowSet(rowSetList)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		[]string{"database", "id", "name"},
+		[]string{"TEXT", "INT", "TEXT"},
+		[]interface{}{
+			[]interface{}{"tenant_a", int64(1), "x"},
+			[]interface{}{"tenant_b", int64(2), "y"},
+			[]interface{}{"tenant_b", int64(3), "z"},
+		},
+	}, merged)
+
+	merged, err = mergeBatchQueryRowSet(nil)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{[]string{"database"}, []string{"TEXT"}, []interface{}{}}, merged)
+}
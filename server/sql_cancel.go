@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// queryIDHeader is the response header carrying the ID of the SQL editor query.
+// The header is flushed before the query runs, so that the client can cancel the query with the ID before the result returns.
+const queryIDHeader = "X-Bytebase-Query-Id"
+
+// runningQuery is the SQL editor query in progress.
+type runningQuery struct {
+	principalID int
+	cancel      context.CancelFunc
+}
+
+// startQuery registers the query, and returns the query ID with the context to run the query.
+// The returned finish function must be called after the query finishes.
+func (s *Server) startQuery(ctx context.Context, principalID int) (context.Context, string, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	queryID := uuid.New().String()
+	s.runningQueryMap.Store(queryID, &runningQuery{
+		principalID: principalID,
+		cancel:      cancel,
+	})
+	return ctx, queryID, func() {
+		s.runningQueryMap.Delete(queryID)
+		cancel()
+	}
+}
+
+// getQueryContext returns the query context with the max execution time of the environment.
+func (s *Server) getQueryContext(ctx context.Context, instance *api.Instance, limit int) (*db.QueryContext, error) {
+	policy, err := s.store.GetQueryTimeoutPolicyByEnvID(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get query timeout policy for environment ID: %d", instance.EnvironmentID)).SetInternal(err)
+	}
+	return &db.QueryContext{
+		Limit:   limit,
+		Timeout: time.Duration(policy.MaxExecutionSeconds) * time.Second,
+	}, nil
+}
+
+func (s *Server) cancelQuery(c echo.Context) error {
+	queryID := c.Param("queryID")
+	value, ok := s.runningQueryMap.Load(queryID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query %q not found, it may have finished", queryID))
+	}
+	query := value.(*runningQuery)
+
+	// Only the query issuer and the workspace Owner and DBA can cancel the query.
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	role := c.Get(getRoleContextKey()).(api.Role)
+	if query.principalID != principalID && role != api.Owner && role != api.DBA {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the query issuer, Owner and DBA can cancel the query")
+	}
+
+	// The driver kills the backend query on the context cancellation.
+	query.cancel()
+	return c.NoContent(http.StatusOK)
+}
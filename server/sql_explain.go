@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// explainLargeTableRowCount is the estimated scanned row count above which we consider the table large.
+// The full table scan on the large table is reported as not using index.
+const explainLargeTableRowCount = 10000
+
+// mysqlExplainStatementRegexp matches the statements starting with EXPLAIN or its synonyms.
+var mysqlExplainStatementRegexp = regexp.MustCompile(`(?i)^\s*(EXPLAIN|DESCRIBE|DESC)\s`)
+
+// getMySQLExplainedStatement returns the statement explained by the EXPLAIN statement, or empty if the statement is not EXPLAIN.
+func getMySQLExplainedStatement(statement string) (string, error) {
+	// Only parse the EXPLAIN statements, so that the queries the parser cannot handle are not affected.
+	if !mysqlExplainStatementRegexp.MatchString(statement) {
+		return "", nil
+	}
+	nodeList, _, err := tidbparser.New().Parse(statement, "", "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse statement %q", statement)
+	}
+	if len(nodeList) != 1 {
+		return "", errors.Errorf("expect one statement, but found %d", len(nodeList))
+	}
+	explain, ok := nodeList[0].(*tidbast.ExplainStmt)
+	if !ok {
+		return "", nil
+	}
+	// EXPLAIN FOR CONNECTION and DESCRIBE table don't explain a statement.
+	switch explain.Stmt.(type) {
+	case *tidbast.SelectStmt, *tidbast.SetOprStmt:
+	default:
+		return "", nil
+	}
+	var sb strings.Builder
+	if err := explain.Stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", errors.Wrapf(err, "failed to restore the explained statement of %q", statement)
+	}
+	return sb.String(), nil
+}
+
+// explainMySQLQuery explains the query in the format we can analyze, and returns the advices on the query plan.
+// MySQL uses EXPLAIN FORMAT=JSON, while TiDB uses the default row format because it doesn't support the JSON format.
+func explainMySQLQuery(ctx context.Context, driver db.Driver, engine db.Type, statement string) ([]advisor.Advice, error) {
+	switch engine {
+	case db.MySQL:
+		rowSet, err := driver.Query(ctx, fmt.Sprintf("EXPLAIN FORMAT=JSON %s", statement), &db.QueryContext{})
+		if err != nil {
+			return nil, err
+		}
+		_, rowList, err := convertRowSet(rowSet)
+		if err != nil {
+			return nil, err
+		}
+		if len(rowList) != 1 || len(rowList[0]) != 1 {
+			return nil, errors.Errorf("expect one row with one column for EXPLAIN FORMAT=JSON, but got %v", rowList)
+		}
+		plan, ok := rowList[0][0].(string)
+		if !ok {
+			return nil, errors.Errorf("expect JSON string for EXPLAIN FORMAT=JSON, but got %T", rowList[0][0])
+		}
+		return checkMySQLExplain(statement, plan)
+	case db.TiDB:
+		rowSet, err := driver.Query(ctx, fmt.Sprintf("EXPLAIN %s", statement), &db.QueryContext{})
+		if err != nil {
+			return nil, err
+		}
+		columnNames, rowList, err := convertRowSet(rowSet)
+		if err != nil {
+			return nil, err
+		}
+		return checkTiDBExplain(statement, columnNames, rowList), nil
+	default:
+		return nil, errors.Errorf("unsupported engine %s for EXPLAIN analysis", engine)
+	}
+}
+
+// convertRowSet converts the row set returned by the driver Query to the column names and rows.
+func convertRowSet(rowSet []interface{}) ([]string, [][]interface{}, error) {
+	if len(rowSet) != 3 {
+		return nil, nil, errors.Errorf("expect row set with 3 elements, but got %d", len(rowSet))
+	}
+	columnNames, ok := rowSet[0].([]string)
+	if !ok {
+		return nil, nil, errors.Errorf("expect column names of []string, but got %T", rowSet[0])
+	}
+	data, ok := rowSet[2].([]interface{})
+	if !ok {
+		return nil, nil, errors.Errorf("expect rows of []interface{}, but got %T", rowSet[2])
+	}
+	var rowList [][]interface{}
+	for _, row := range data {
+		r, ok := row.([]interface{})
+		if !ok {
+			return nil, nil, errors.Errorf("expect row of []interface{}, but got %T", row)
+		}
+		rowList = append(rowList, r)
+	}
+	return columnNames, rowList, nil
+}
+
+// mysqlExplainFinding is the finding on the MySQL query plan.
+type mysqlExplainFinding struct {
+	fullScanTableList   []string
+	largeTableList      []string
+	usingFilesort       bool
+	usingTemporaryTable bool
+}
+
+// checkMySQLExplain analyzes the MySQL EXPLAIN FORMAT=JSON output.
+func checkMySQLExplain(statement string, plan string) ([]advisor.Advice, error) {
+	var node interface{}
+	if err := json.Unmarshal([]byte(plan), &node); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal EXPLAIN FORMAT=JSON output %q", plan)
+	}
+	finding := &mysqlExplainFinding{}
+	walkMySQLExplain(node, finding)
+	return finding.toAdviceList(statement), nil
+}
+
+// walkMySQLExplain walks the query plan, because the tables and operations can be nested in many kinds of query blocks,
+// such as nested_loop, ordering_operation, grouping_operation, union_result and the subqueries.
+func walkMySQLExplain(node interface{}, finding *mysqlExplainFinding) {
+	switch n := node.(type) {
+	case []interface{}:
+		for _, child := range n {
+			walkMySQLExplain(child, finding)
+		}
+	case map[string]interface{}:
+		if v, ok := n["using_filesort"].(bool); ok && v {
+			finding.usingFilesort = true
+		}
+		if v, ok := n["using_temporary_table"].(bool); ok && v {
+			finding.usingTemporaryTable = true
+		}
+		tableName, hasTableName := n["table_name"].(string)
+		// The access type ALL means the full table scan.
+		// We skip the derived tables such as <derived2> and <union1,2>, which are materialized by MySQL.
+		if accessType, ok := n["access_type"].(string); ok && hasTableName && accessType == "ALL" && !strings.HasPrefix(tableName, "<") {
+			_, hasKey := n["key"]
+			if !hasKey && getMySQLExplainRowCount(n) >= explainLargeTableRowCount {
+				finding.largeTableList = append(finding.largeTableList, tableName)
+			} else {
+				finding.fullScanTableList = append(finding.fullScanTableList, tableName)
+			}
+		}
+		// Sort the keys for the deterministic output.
+		var keyList []string
+		for key := range n {
+			keyList = append(keyList, key)
+		}
+		sort.Strings(keyList)
+		for _, key := range keyList {
+			walkMySQLExplain(n[key], finding)
+		}
+	}
+}
+
+// getMySQLExplainRowCount returns the estimated scanned row count of the table.
+// MySQL 5.7 and later use rows_examined_per_scan, while MySQL 5.6 uses rows.
+func getMySQLExplainRowCount(table map[string]interface{}) float64 {
+	for _, key := range []string{"rows_examined_per_scan", "rows"} {
+		if v, ok := table[key].(float64); ok {
+			return v
+		}
+	}
+	return 0
+}
+
+func (f *mysqlExplainFinding) toAdviceList(statement string) []advisor.Advice {
+	var adviceList []advisor.Advice
+	if len(f.largeTableList) > 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Error,
+			Code:    advisor.NotUseIndex,
+			Title:   "Query does not use index",
+			Content: fmt.Sprintf("statement %q scans the large tables %s fully without any index", statement, formatTableList(f.largeTableList)),
+		})
+	}
+	if len(f.fullScanTableList) > 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Warn,
+			Code:    advisor.StatementFullTableScan,
+			Title:   "Full table scan",
+			Content: fmt.Sprintf("statement %q scans the tables %s fully", statement, formatTableList(f.fullScanTableList)),
+		})
+	}
+	if f.usingFilesort {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Warn,
+			Code:    advisor.StatementUseFilesort,
+			Title:   "Using filesort",
+			Content: fmt.Sprintf("statement %q sorts the rows without index, consider adding an index for the ORDER BY or GROUP BY columns", statement),
+		})
+	}
+	if f.usingTemporaryTable {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Warn,
+			Code:    advisor.StatementUseTemporaryTable,
+			Title:   "Using temporary table",
+			Content: fmt.Sprintf("statement %q creates temporary tables to resolve the query", statement),
+		})
+	}
+	return adviceList
+}
+
+// checkTiDBExplain analyzes the TiDB EXPLAIN output in the row format.
+// The columns are id, estRows, task, access object and operator info.
+func checkTiDBExplain(statement string, columnNames []string, rowList [][]interface{}) []advisor.Advice {
+	idIndex, estRowsIndex, accessObjectIndex := -1, -1, -1
+	for i, name := range columnNames {
+		switch strings.ToLower(name) {
+		case "id":
+			idIndex = i
+		case "estrows", "count":
+			estRowsIndex = i
+		case "access object":
+			accessObjectIndex = i
+		}
+	}
+	if idIndex < 0 {
+		return nil
+	}
+
+	finding := &mysqlExplainFinding{}
+	for _, row := range rowList {
+		id := fmt.Sprintf("%v", row[idIndex])
+		switch {
+		case strings.Contains(id, "TableFullScan"):
+			table := "?"
+			if accessObjectIndex >= 0 {
+				table = strings.TrimPrefix(fmt.Sprintf("%v", row[accessObjectIndex]), "table:")
+			}
+			var estRows float64
+			if estRowsIndex >= 0 {
+				estRows, _ = strconv.ParseFloat(fmt.Sprintf("%v", row[estRowsIndex]), 64)
+			}
+			if estRows >= explainLargeTableRowCount {
+				finding.largeTableList = append(finding.largeTableList, table)
+			} else {
+				finding.fullScanTableList = append(finding.fullScanTableList, table)
+			}
+		case strings.Contains(id, "Sort_"):
+			// TopN is the sort with limit, and it's cheap enough.
+			finding.usingFilesort = true
+		}
+	}
+	return finding.toAdviceList(statement)
+}
+
+func formatTableList(tableList []string) string {
+	var list []string
+	for _, table := range tableList {
+		list = append(list, fmt.Sprintf("%q", table))
+	}
+	return strings.Join(list, ", ")
+}
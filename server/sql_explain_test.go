@@ -0,0 +1,152 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestGetMySQLExplainedStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{statement: "EXPLAIN SELECT * FROM t WHERE a = 1", want: "SELECT * FROM `t` WHERE `a`=1"},
+		{statement: "EXPLAIN FORMAT=JSON SELECT a FROM t", want: "SELECT `a` FROM `t`"},
+		{statement: "SELECT * FROM t", want: ""},
+		{statement: "DESC t", want: ""},
+		{statement: "EXPLAIN FOR CONNECTION 1", want: ""},
+		{statement: "describe select a from t", want: "SELECT `a` FROM `t`"},
+		// The statements not starting with EXPLAIN are not parsed.
+		{statement: "SELECT * FROM t WHERE a = 1 FOR SHARE SKIP LOCKED NOWAIT NONSENSE", want: ""},
+	}
+
+	for _, test := range tests {
+		got, err := getMySQLExplainedStatement(test.statement)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, got, test.statement)
+	}
+}
+
+func TestCheckMySQLExplain(t *testing.T) {
+	tests := []struct {
+		plan     string
+		wantCode []advisor.Code
+	}{
+		{
+			// Index lookup.
+			plan: `{
+  "query_block": {
+    "select_id": 1,
+    "table": {"table_name": "t", "access_type": "ref", "key": "idx_a", "rows_examined_per_scan": 1}
+  }
+}`,
+			wantCode: nil,
+		},
+		{
+			// Full scan on a small table with filesort.
+			plan: `{
+  "query_block": {
+    "select_id": 1,
+    "ordering_operation": {
+      "using_filesort": true,
+      "table": {"table_name": "t", "access_type": "ALL", "rows_examined_per_scan": 10}
+    }
+  }
+}`,
+			wantCode: []advisor.Code{advisor.StatementFullTableScan, advisor.StatementUseFilesort},
+		},
+		{
+			// Full scan on a large table in the join with temporary table.
+			plan: `{
+  "query_block": {
+    "select_id": 1,
+    "grouping_operation": {
+      "using_temporary_table": true,
+      "using_filesort": false,
+      "nested_loop": [
+        {"table": {"table_name": "t1", "access_type": "ALL", "rows_examined_per_scan": 100000}},
+        {"table": {"table_name": "t2", "access_type": "eq_ref", "key": "PRIMARY", "rows_examined_per_scan": 1}}
+      ]
+    }
+  }
+}`,
+			wantCode: []advisor.Code{advisor.NotUseIndex, advisor.StatementUseTemporaryTable},
+		},
+		{
+			// The derived table is skipped.
+			plan: `{
+  "query_block": {
+    "select_id": 1,
+    "table": {
+      "table_name": "<derived2>",
+      "access_type": "ALL",
+      "rows_examined_per_scan": 100000,
+      "materialized_from_subquery": {
+        "query_block": {
+          "select_id": 2,
+          "table": {"table_name": "t", "access_type": "range", "key": "idx_a", "rows_examined_per_scan": 100}
+        }
+      }
+    }
+  }
+}`,
+			wantCode: nil,
+		},
+	}
+
+	for _, test := range tests {
+		adviceList, err := checkMySQLExplain("SELECT 1", test.plan)
+		require.NoError(t, err)
+		var codeList []advisor.Code
+		for _, advice := range adviceList {
+			codeList = append(codeList, advice.Code)
+		}
+		require.Equal(t, test.wantCode, codeList, test.plan)
+	}
+
+	_, err := checkMySQLExplain("SELECT 1", "not json")
+	require.Error(t, err)
+}
+
+func TestCheckTiDBExplain(t *testing.T) {
+	columnNames := []string{"id", "estRows", "task", "access object", "operator info"}
+	tests := []struct {
+		rowList  [][]interface{}
+		wantCode []advisor.Code
+	}{
+		{
+			rowList: [][]interface{}{
+				{"IndexLookUp_10", "10.00", "root", "", ""},
+				{"├─IndexRangeScan_8(Build)", "10.00", "cop[tikv]", "table:t, index:idx_a(a)", "range:[1,1]"},
+				{"└─TableRowIDScan_9(Probe)", "10.00", "cop[tikv]", "table:t", "keep order:false"},
+			},
+			wantCode: nil,
+		},
+		{
+			rowList: [][]interface{}{
+				{"Sort_5", "10000.00", "root", "", "test.t.b"},
+				{"└─TableReader_9", "10000.00", "root", "", "data:TableFullScan_8"},
+				{"  └─TableFullScan_8", "10000.00", "cop[tikv]", "table:t", "keep order:false"},
+			},
+			wantCode: []advisor.Code{advisor.NotUseIndex, advisor.StatementUseFilesort},
+		},
+		{
+			rowList: [][]interface{}{
+				{"TableReader_5", "10.00", "root", "", "data:TableFullScan_4"},
+				{"└─TableFullScan_4", "10.00", "cop[tikv]", "table:t", "keep order:false"},
+			},
+			wantCode: []advisor.Code{advisor.StatementFullTableScan},
+		},
+	}
+
+	for _, test := range tests {
+		var codeList []advisor.Code
+		for _, advice := range checkTiDBExplain("SELECT 1", columnNames, test.rowList) {
+			codeList = append(codeList, advice.Code)
+		}
+		require.Equal(t, test.wantCode, codeList)
+	}
+}
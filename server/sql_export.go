@@ -0,0 +1,475 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// exportSQL re-runs the query and streams the result as a file download.
+// The rows are written to the response as soon as they are read, so that we never buffer the whole result.
+func (s *Server) exportSQL(c echo.Context) error {
+	ctx := c.Request().Context()
+	export := &api.SQLExport{}
+	if err := jsonapi.UnmarshalPayload(c.Request().Body, export); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql export request").SetInternal(err)
+	}
+	if export.InstanceID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql export request, missing instanceId")
+	}
+	if !validateSQLSelectStatement(export.Statement) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql export request, only support SELECT sql statement")
+	}
+
+	instance, err := s.store.GetInstanceByID(ctx, export.InstanceID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", export.InstanceID)).SetInternal(err)
+	}
+	if instance == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", export.InstanceID))
+	}
+
+	var writer queryResultWriter
+	switch export.Format {
+	case api.SQLExportFormatCSV:
+		writer = newCSVExportWriter(c.Response())
+	case api.SQLExportFormatJSON:
+		writer = newJSONExportWriter(c.Response())
+	case api.SQLExportFormatSQL:
+		writer = newSQLExportWriter(c.Response(), instance.Engine)
+	case api.SQLExportFormatXLSX:
+		writer = newXLSXExportWriter(c.Response())
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid export format %q", export.Format))
+	}
+
+	// Only the query passing the SQL review policy can be exported.
+	adviceLevel, adviceList, err := s.sqlEditorCheck(ctx, instance, export.DatabaseName, export.Statement)
+	if err != nil {
+		return err
+	}
+	if adviceLevel == advisor.Error {
+		var contentList []string
+		for _, advice := range adviceList {
+			if advice.Status == advisor.Error {
+				contentList = append(contentList, fmt.Sprintf("%s: %s", advice.Title, advice.Content))
+			}
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The statement violates the SQL review policy: %s", strings.Join(contentList, "; ")))
+	}
+
+	policy, err := s.store.GetSQLExportPolicyByEnvID(ctx, instance.EnvironmentID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get SQL export policy for environment ID: %d", instance.EnvironmentID)).SetInternal(err)
+	}
+	limit := policy.MaxRowCount
+	if export.Limit > 0 && export.Limit < limit {
+		limit = export.Limit
+	}
+	queryContext, err := s.getQueryContext(ctx, instance, limit)
+	if err != nil {
+		return err
+	}
+
+	masker, err := s.getSQLResultMasker(ctx, instance, export.DatabaseName, export.Statement, c.Get(getRoleContextKey()).(api.Role))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the sensitive columns for the query").SetInternal(err)
+	}
+
+	driver, err := s.tryGetPrincipalReadOnlyDatabaseDriver(ctx, instance, export.DatabaseName, c.Get(getPrincipalIDContextKey()).(int))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get database driver").SetInternal(err)
+	}
+	defer driver.Close(ctx)
+	sqlDB, err := driver.GetDBConnection(ctx, export.DatabaseName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get database connection").SetInternal(err)
+	}
+
+	start := time.Now().UnixNano()
+	var rowCount int64
+	headerWritten := false
+	exportErr := util.QueryStream(ctx, instance.Engine, sqlDB, export.Statement, queryContext,
+		func(columnNames []string, _ []string) error {
+			// We write the header after the query succeeds, so that we can still return the error response on query failure.
+			c.Response().Header().Set(echo.HeaderContentType, writer.contentType())
+			c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("export-%d.%s", time.Now().Unix(), writer.fileExtension())))
+			c.Response().WriteHeader(http.StatusOK)
+			headerWritten = true
+			return writer.writeHeader(columnNames)
+		},
+		func(row []interface{}) error {
+			rowCount++
+			masker.maskRow(row)
+			if err := writer.writeRow(row); err != nil {
+				return err
+			}
+			// Flush every batch of rows to keep the memory usage flat.
+			if rowCount%1000 == 0 {
+				c.Response().Flush()
+			}
+			return nil
+		},
+	)
+	if exportErr == nil {
+		exportErr = writer.close()
+	}
+
+	level := api.ActivityInfo
+	errMessage := ""
+	if exportErr != nil {
+		level = api.ActivityError
+		errMessage = exportErr.Error()
+		log.Debug("Failed to export query result",
+			zap.Error(exportErr),
+			zap.String("statement", export.Statement),
+		)
+	}
+	if err := s.createSQLEditorExportActivity(ctx, c, level, export.InstanceID, api.ActivitySQLEditorExportPayload{
+		Statement:    export.Statement,
+		DurationNs:   time.Now().UnixNano() - start,
+		InstanceName: instance.Name,
+		DatabaseName: export.DatabaseName,
+		Format:       export.Format,
+		RowCount:     rowCount,
+		Error:        errMessage,
+	}); err != nil && !headerWritten {
+		return err
+	}
+
+	if exportErr != nil && !headerWritten {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to export query result: %s", exportErr.Error())).SetInternal(exportErr)
+	}
+	// The response is already partially sent, so we can only abort it.
+	return nil
+}
+
+func (s *Server) createSQLEditorExportActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorExportPayload) error {
+	activityBytes, err := json.Marshal(payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+		Type:        api.ActivitySQLEditorExport,
+		ContainerID: containerID,
+		Level:       level,
+		Comment: fmt.Sprintf("Exported %d rows of `%q` in database %q of instance %q as %s.",
+			payload.RowCount, payload.Statement, payload.DatabaseName, payload.InstanceName, payload.Format),
+		Payload: string(activityBytes),
+	}
+	if _, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		log.Warn("Failed to create activity after exporting query result",
+			zap.String("database_name", payload.DatabaseName),
+			zap.String("instance_name", payload.InstanceName),
+			zap.String("statement", payload.Statement),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+	}
+	return nil
+}
+
+// queryResultWriter writes the query result in a specific format.
+type queryResultWriter interface {
+	contentType() string
+	fileExtension() string
+	writeHeader(columnNames []string) error
+	writeRow(row []interface{}) error
+	// close writes the remaining content after the last row.
+	close() error
+}
+
+func formatExportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w)}
+}
+
+func (*csvExportWriter) contentType() string {
+	return "text/csv; charset=UTF-8"
+}
+
+func (*csvExportWriter) fileExtension() string {
+	return "csv"
+}
+
+func (w *csvExportWriter) writeHeader(columnNames []string) error {
+	return w.writer.Write(columnNames)
+}
+
+func (w *csvExportWriter) writeRow(row []interface{}) error {
+	var record []string
+	for _, value := range row {
+		record = append(record, formatExportValue(value))
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvExportWriter) close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonExportWriter struct {
+	encoder     *json.Encoder
+	columnNames []string
+}
+
+func newJSONExportWriter(w io.Writer) *jsonExportWriter {
+	return &jsonExportWriter{encoder: json.NewEncoder(w)}
+}
+
+func (*jsonExportWriter) contentType() string {
+	return "application/x-ndjson; charset=UTF-8"
+}
+
+func (*jsonExportWriter) fileExtension() string {
+	return "jsonl"
+}
+
+func (w *jsonExportWriter) writeHeader(columnNames []string) error {
+	w.columnNames = columnNames
+	return nil
+}
+
+func (w *jsonExportWriter) writeRow(row []interface{}) error {
+	// Build the object manually to keep the column order.
+	var buf strings.Builder
+	buf.WriteString("{")
+	for i, value := range row {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(w.columnNames[i])
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+	buf.WriteString("}")
+	return w.encoder.Encode(json.RawMessage(buf.String()))
+}
+
+func (*jsonExportWriter) close() error {
+	return nil
+}
+
+type sqlExportWriter struct {
+	writer  io.Writer
+	engine  db.Type
+	columns string
+}
+
+func newSQLExportWriter(w io.Writer, engine db.Type) *sqlExportWriter {
+	return &sqlExportWriter{writer: w, engine: engine}
+}
+
+func (*sqlExportWriter) contentType() string {
+	return "application/sql; charset=UTF-8"
+}
+
+func (*sqlExportWriter) fileExtension() string {
+	return "sql"
+}
+
+func (w *sqlExportWriter) writeHeader(columnNames []string) error {
+	var quotedList []string
+	for _, name := range columnNames {
+		quotedList = append(quotedList, w.quoteIdentifier(name))
+	}
+	w.columns = strings.Join(quotedList, ", ")
+	return nil
+}
+
+func (w *sqlExportWriter) writeRow(row []interface{}) error {
+	var valueList []string
+	for _, value := range row {
+		valueList = append(valueList, w.quoteValue(value))
+	}
+	_, err := fmt.Fprintf(w.writer, "INSERT INTO %s (%s) VALUES (%s);\n", w.quoteIdentifier("result"), w.columns, strings.Join(valueList, ", "))
+	return err
+}
+
+func (*sqlExportWriter) close() error {
+	return nil
+}
+
+func (w *sqlExportWriter) quoteIdentifier(name string) string {
+	switch w.engine {
+	case db.MySQL, db.TiDB, db.ClickHouse:
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+	default:
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+	}
+}
+
+func (w *sqlExportWriter) quoteValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64, int32, float64:
+		return formatExportValue(v)
+	default:
+		s := formatExportValue(v)
+		switch w.engine {
+		case db.MySQL, db.TiDB, db.ClickHouse:
+			s = strings.ReplaceAll(s, `\`, `\\`)
+		}
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+	}
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// xlsxExportWriter writes the minimal XLSX file with a single sheet.
+// The XLSX file is a zip archive, and the zip writer streams each file entry, so we write the static entries first
+// and the sheet entry at last, with the rows written as inline strings to avoid the shared string table.
+type xlsxExportWriter struct {
+	zipWriter *zip.Writer
+	sheet     io.Writer
+}
+
+func newXLSXExportWriter(w io.Writer) *xlsxExportWriter {
+	return &xlsxExportWriter{zipWriter: zip.NewWriter(w)}
+}
+
+func (*xlsxExportWriter) contentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (*xlsxExportWriter) fileExtension() string {
+	return "xlsx"
+}
+
+func (w *xlsxExportWriter) writeHeader(columnNames []string) error {
+	for _, entry := range []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: xlsxContentTypes},
+		{name: "_rels/.rels", content: xlsxRootRels},
+		{name: "xl/workbook.xml", content: xlsxWorkbook},
+		{name: "xl/_rels/workbook.xml.rels", content: xlsxWorkbookRels},
+	} {
+		f, err := w.zipWriter.Create(entry.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, entry.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := w.zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.sheet = sheet
+	if _, err := io.WriteString(w.sheet, xlsxSheetHeader); err != nil {
+		return err
+	}
+	var row []interface{}
+	for _, name := range columnNames {
+		row = append(row, name)
+	}
+	return w.writeRow(row)
+}
+
+func (w *xlsxExportWriter) writeRow(row []interface{}) error {
+	var buf strings.Builder
+	buf.WriteString("<row>")
+	for _, value := range row {
+		switch v := value.(type) {
+		case nil:
+			buf.WriteString("<c/>")
+		case int64, int32, float64:
+			fmt.Fprintf(&buf, "<c><v>%s</v></c>", formatExportValue(v))
+		case bool:
+			if v {
+				buf.WriteString(`<c t="b"><v>1</v></c>`)
+			} else {
+				buf.WriteString(`<c t="b"><v>0</v></c>`)
+			}
+		default:
+			buf.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&buf, []byte(formatExportValue(v))); err != nil {
+				return errors.Wrap(err, "failed to escape xlsx cell")
+			}
+			buf.WriteString("</t></is></c>")
+		}
+	}
+	buf.WriteString("</row>")
+	_, err := io.WriteString(w.sheet, buf.String())
+	return err
+}
+
+func (w *xlsxExportWriter) close() error {
+	if _, err := io.WriteString(w.sheet, xlsxSheetFooter); err != nil {
+		return err
+	}
+	return w.zipWriter.Close()
+}
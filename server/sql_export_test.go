@@ -0,0 +1,75 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func writeQueryResult(t *testing.T, writer queryResultWriter) {
+	require.NoError(t, writer.writeHeader([]string{"id", "name", "active"}))
+	require.NoError(t, writer.writeRow([]interface{}{int64(1), "a,'b\"", true}))
+	require.NoError(t, writer.writeRow([]interface{}{int64(2), nil, false}))
+	require.NoError(t, writer.close())
+}
+
+func TestQueryResultWriter(t *testing.T) {
+	tests := []struct {
+		newWriter func(w io.Writer) queryResultWriter
+		want      string
+	}{
+		{
+			newWriter: func(w io.Writer) queryResultWriter { return newCSVExportWriter(w) },
+			want:      "id,name,active\n1,\"a,'b\"\"\",true\n2,,false\n",
+		},
+		{
+			newWriter: func(w io.Writer) queryResultWriter { return newJSONExportWriter(w) },
+			want:      "{\"id\":1,\"name\":\"a,'b\\\"\",\"active\":true}\n{\"id\":2,\"name\":null,\"active\":false}\n",
+		},
+		{
+			newWriter: func(w io.Writer) queryResultWriter { return newSQLExportWriter(w, db.MySQL) },
+			want:      "INSERT INTO `result` (`id`, `name`, `active`) VALUES (1, 'a,''b\"', TRUE);\nINSERT INTO `result` (`id`, `name`, `active`) VALUES (2, NULL, FALSE);\n",
+		},
+		{
+			newWriter: func(w io.Writer) queryResultWriter { return newSQLExportWriter(w, db.Postgres) },
+			want:      "INSERT INTO \"result\" (\"id\", \"name\", \"active\") VALUES (1, 'a,''b\"', TRUE);\nINSERT INTO \"result\" (\"id\", \"name\", \"active\") VALUES (2, NULL, FALSE);\n",
+		},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		writeQueryResult(t, test.newWriter(&buf))
+		require.Equal(t, test.want, buf.String())
+	}
+}
+
+func TestXLSXExportWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writeQueryResult(t, newXLSXExportWriter(&buf))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var nameList []string
+	var sheet []byte
+	for _, f := range reader.File {
+		nameList = append(nameList, f.Name)
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			sheet, err = io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+		}
+	}
+	require.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, nameList)
+	require.Equal(t, xlsxSheetHeader+
+		`<row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c><c t="inlineStr"><is><t xml:space="preserve">name</t></is></c><c t="inlineStr"><is><t xml:space="preserve">active</t></is></c></row>`+
+		`<row><c><v>1</v></c><c t="inlineStr"><is><t xml:space="preserve">a,&#39;b&#34;</t></is></c><c t="b"><v>1</v></c></row>`+
+		`<row><c><v>2</v></c><c/><c t="b"><v>0</v></c></row>`+
+		xlsxSheetFooter, string(sheet))
+}
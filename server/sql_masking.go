@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/lineage"
+)
+
+const fullMask = "******"
+
+// sqlResultMasker masks the query result columns derived from the sensitive columns.
+type sqlResultMasker struct {
+	// maskingTypeList is the masking type of each result column in order.
+	maskingTypeList []api.MaskingType
+	// maskAll is true if we fail to resolve the result columns, and we have to fully mask all of them.
+	maskAll bool
+	// hashKey is the server secret keying the hash masking, so that the hashes of the guessable values cannot be looked up.
+	hashKey []byte
+}
+
+// maskingDatabase is the database with the tables and columns used to resolve the sensitive columns of the query result.
+type maskingDatabase struct {
+	name       string
+	tableList  []*api.Table
+	columnList []*api.Column
+}
+
+// getSQLResultMasker returns the masker for the query result, and nil if the result doesn't need masking.
+// The result columns are resolved back to the source table columns, so that the aliases, expressions and joins don't leak the sensitive data.
+func (s *Server) getSQLResultMasker(ctx context.Context, instance *api.Instance, databaseName string, statement string, role api.Role) (*sqlResultMasker, error) {
+	databaseFind := &api.DatabaseFind{
+		InstanceID: &instance.ID,
+	}
+	// The query may read the tables from any database in the instance, e.g. "SELECT * FROM db.t" in MySQL,
+	// except for PostgreSQL whose connection is bound to the database.
+	if databaseName != "" && instance.Engine == db.Postgres {
+		databaseFind.Name = &databaseName
+	}
+	databaseList, err := s.store.FindDatabase(ctx, databaseFind)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find databases of instance %q", instance.Name)
+	}
+
+	var maskingDatabaseList []*maskingDatabase
+	needMasking := false
+	for _, database := range databaseList {
+		columnList, err := s.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &database.ID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find columns of database %q", database.Name)
+		}
+		for _, column := range columnList {
+			if api.GetColumnMaskingType(column.Sensitivity, role) != api.MaskingTypeNone {
+				needMasking = true
+				break
+			}
+		}
+		maskingDatabaseList = append(maskingDatabaseList, &maskingDatabase{name: database.Name, columnList: columnList})
+	}
+	if !needMasking {
+		return nil, nil
+	}
+	// We load the tables of all the databases, so that the query referencing the databases without sensitive columns can still be resolved.
+	for i, database := range databaseList {
+		tableList, err := s.store.FindTable(ctx, &api.TableFind{DatabaseID: &database.ID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find tables of database %q", database.Name)
+		}
+		maskingDatabaseList[i].tableList = tableList
+	}
+	masker := newSQLResultMasker(instance.Engine, databaseName, statement, maskingDatabaseList, role)
+	if masker != nil {
+		masker.hashKey = []byte(s.secret)
+	}
+	return masker, nil
+}
+
+// newSQLResultMasker resolves the result columns of the statement run in the database against the tables of the databases.
+// It masks all the result columns if the statement references any table that cannot be resolved.
+func newSQLResultMasker(engine db.Type, databaseName string, statement string, databaseList []*maskingDatabase, role api.Role) *sqlResultMasker {
+	schema := &lineage.DatabaseSchema{}
+	if engine != db.Postgres {
+		// The tables without the database name are in the current database.
+		schema.DefaultSchema = databaseName
+	}
+	columnMaskingType := make(map[lineage.SourceColumn]api.MaskingType)
+	for _, database := range databaseList {
+		tableMap := make(map[int]*lineage.Table)
+		for _, table := range database.tableList {
+			lineageTable := &lineage.Table{Name: table.Name}
+			switch engine {
+			case db.Postgres:
+				// The PostgreSQL table name is synced in the schema.table format.
+				if i := strings.Index(table.Name, "."); i >= 0 {
+					lineageTable.Schema, lineageTable.Name = table.Name[:i], table.Name[i+1:]
+				}
+			default:
+				lineageTable.Schema = database.name
+			}
+			tableMap[table.ID] = lineageTable
+			schema.TableList = append(schema.TableList, lineageTable)
+		}
+		for _, column := range database.columnList {
+			table, ok := tableMap[column.TableID]
+			if !ok {
+				continue
+			}
+			table.ColumnList = append(table.ColumnList, column.Name)
+			columnMaskingType[lineage.SourceColumn{Schema: table.Schema, Table: table.Name, Column: column.Name}] = api.GetColumnMaskingType(column.Sensitivity, role)
+		}
+	}
+
+	resultColumnList, err := lineage.Resolve(parser.EngineType(engine), statement, schema)
+	if err != nil {
+		log.Debug("Failed to resolve the query result columns, mask all of them",
+			zap.String("database", databaseName),
+			zap.String("statement", statement),
+			zap.Error(err),
+		)
+		return &sqlResultMasker{maskAll: true}
+	}
+	if resultColumnList == nil {
+		return nil
+	}
+
+	masker := &sqlResultMasker{}
+	for _, resultColumn := range resultColumnList {
+		maskingType := api.MaskingTypeNone
+		for _, sourceColumn := range resultColumn.SourceColumnList {
+			maskingType = api.StrongerMaskingType(maskingType, columnMaskingType[sourceColumn])
+		}
+		masker.maskingTypeList = append(masker.maskingTypeList, maskingType)
+	}
+	return masker
+}
+
+// maskRow masks the row in place.
+func (m *sqlResultMasker) maskRow(row []interface{}) {
+	if m == nil {
+		return
+	}
+	// The result columns mismatch the resolved ones, so we cannot tell the column lineage.
+	maskAll := m.maskAll || len(row) != len(m.maskingTypeList)
+	for i, value := range row {
+		maskingType := api.MaskingTypeFull
+		if !maskAll {
+			maskingType = m.maskingTypeList[i]
+		}
+		row[i] = maskValue(value, maskingType, m.hashKey)
+	}
+}
+
+// maskRowSet masks the rows of the row set returned by the driver Query.
+func (m *sqlResultMasker) maskRowSet(rowSet []interface{}) {
+	if m == nil || len(rowSet) != 3 {
+		return
+	}
+	data, ok := rowSet[2].([]interface{})
+	if !ok {
+		return
+	}
+	for _, row := range data {
+		if row, ok := row.([]interface{}); ok {
+			m.maskRow(row)
+		}
+	}
+}
+
+// maskValue masks the value with the masking type, and the hash masking is an HMAC-SHA256 keyed with the hash key.
+func maskValue(value interface{}, maskingType api.MaskingType, hashKey []byte) interface{} {
+	if value == nil || maskingType == api.MaskingTypeNone {
+		return value
+	}
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		str = fmt.Sprintf("%v", v)
+	}
+
+	switch maskingType {
+	case api.MaskingTypePartial:
+		runeList := []rune(str)
+		if len(runeList) <= 2 {
+			return strings.Repeat("*", len(runeList))
+		}
+		return string(runeList[0]) + strings.Repeat("*", len(runeList)-2) + string(runeList[len(runeList)-1])
+	case api.MaskingTypeHash:
+		mac := hmac.New(sha256.New, hashKey)
+		mac.Write([]byte(str))
+		return hex.EncodeToString(mac.Sum(nil))
+	default:
+		return fullMask
+	}
+}
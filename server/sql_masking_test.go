@@ -0,0 +1,108 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register the column lineage resolver.
+	_ "github.com/bytebase/bytebase/plugin/parser/lineage/mysql"
+)
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		value       interface{}
+		maskingType api.MaskingType
+		want        interface{}
+	}{
+		{value: "alice@example.com", maskingType: api.MaskingTypeNone, want: "alice@example.com"},
+		{value: "alice@example.com", maskingType: api.MaskingTypeFull, want: "******"},
+		{value: "alice@example.com", maskingType: api.MaskingTypePartial, want: "a***************m"},
+		{value: "数据库", maskingType: api.MaskingTypePartial, want: "数*库"},
+		{value: "ab", maskingType: api.MaskingTypePartial, want: "**"},
+		{value: int64(123), maskingType: api.MaskingTypeHash, want: "77de38e4b50e618a0ebb95db61e2f42697391659d82c064a5f81b9f48d85ccd5"},
+		{value: nil, maskingType: api.MaskingTypeFull, want: nil},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, maskValue(test.value, test.maskingType, []byte("secret")), test.value)
+	}
+	// The hash depends on the key, so that it cannot be looked up without the server secret.
+	require.Equal(t, "2d7955a373e3354e62e7913ade571671732753b454602e2521d8e2b8df553890", maskValue("123", api.MaskingTypeHash, []byte("other")))
+}
+
+func TestSQLResultMasker(t *testing.T) {
+	masker := &sqlResultMasker{maskingTypeList: []api.MaskingType{api.MaskingTypeNone, api.MaskingTypeFull}}
+	row := []interface{}{int64(1), "secret"}
+	masker.maskRow(row)
+	require.Equal(t, []interface{}{int64(1), "******"}, row)
+
+	// The column count mismatch masks all columns.
+	row = []interface{}{int64(1), "secret", "other"}
+	masker.maskRow(row)
+	require.Equal(t, []interface{}{"******", "******", "******"}, row)
+
+	rowSet := []interface{}{[]string{"id", "name"}, []string{"INT", "TEXT"}, []interface{}{[]interface{}{int64(2), "secret"}}}
+	masker.maskRowSet(rowSet)
+	require.Equal(t, []interface{}{[]interface{}{int64(2), "******"}}, rowSet[2])
+
+	var nilMasker *sqlResultMasker
+	row = []interface{}{"secret"}
+	nilMasker.maskRow(row)
+	require.Equal(t, []interface{}{"secret"}, row)
+}
+
+func TestNewSQLResultMasker(t *testing.T) {
+	// The current database "app" has no sensitive column, and the database "hr" has the sensitive column "users.ssn".
+	databaseList := []*maskingDatabase{
+		{
+			name:       "app",
+			tableList:  []*api.Table{{ID: 1, Name: "orders"}},
+			columnList: []*api.Column{{TableID: 1, Name: "id"}, {TableID: 1, Name: "amount"}},
+		},
+		{
+			name:      "hr",
+			tableList: []*api.Table{{ID: 2, Name: "users"}},
+			columnList: []*api.Column{
+				{TableID: 2, Name: "id"},
+				{TableID: 2, Name: "ssn", Sensitivity: api.ColumnSensitivityHigh},
+			},
+		},
+	}
+
+	tests := []struct {
+		statement string
+		want      *sqlResultMasker
+	}{
+		{
+			statement: "SELECT id, amount FROM orders",
+			want:      &sqlResultMasker{maskingTypeList: []api.MaskingType{api.MaskingTypeNone, api.MaskingTypeNone}},
+		},
+		{
+			// The query reads the sensitive column from the other database.
+			statement: "SELECT id, ssn FROM hr.users",
+			want:      &sqlResultMasker{maskingTypeList: []api.MaskingType{api.MaskingTypeNone, api.MaskingTypeFull}},
+		},
+		{
+			statement: "SELECT o.amount, u.ssn AS x FROM orders o JOIN hr.users u ON o.id = u.id",
+			want:      &sqlResultMasker{maskingTypeList: []api.MaskingType{api.MaskingTypeNone, api.MaskingTypeFull}},
+		},
+		{
+			// The table "users" is not in the current database.
+			statement: "SELECT ssn FROM users",
+			want:      &sqlResultMasker{maskAll: true},
+		},
+		{
+			statement: "SELECT * FROM unknown.users",
+			want:      &sqlResultMasker{maskAll: true},
+		},
+	}
+
+	for _, test := range tests {
+		masker := newSQLResultMasker(db.MySQL, "app", test.statement, databaseList, api.Developer)
+		require.Equal(t, test.want, masker, test.statement)
+	}
+}
@@ -1,7 +1,11 @@
 package server
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
 )
 
 func TestValidateSQLSelectStatement(t *testing.T) {
@@ -94,3 +98,242 @@ func TestValidateSQLSelectStatement(t *testing.T) {
 		}
 	}
 }
+
+func TestIsDMLStatement(t *testing.T) {
+	tests := []struct {
+		engine    parser.EngineType
+		statement string
+		want      bool
+	}{
+		{
+			engine:    parser.MySQL,
+			statement: "UPDATE t SET a = 1 WHERE id = 1",
+			want:      true,
+		},
+		{
+			engine:    parser.MySQL,
+			statement: "\n  insert into t values (1)",
+			want:      true,
+		},
+		{
+			engine:    parser.MySQL,
+			statement: "/* cleanup */ delete from t",
+			want:      true,
+		},
+		{
+			engine:    parser.MySQL,
+			statement: "WITH c AS (SELECT id FROM s) UPDATE t, c SET t.a = 1 WHERE t.id = c.id",
+			want:      true,
+		},
+		{
+			engine:    parser.MySQL,
+			statement: "ALTER TABLE t ADD COLUMN a INT",
+			want:      false,
+		},
+		{
+			engine:    parser.MySQL,
+			statement: "-- list\nSELECT * FROM t",
+			want:      false,
+		},
+		{
+			// The statements failing to parse are regarded as DML.
+			engine:    parser.MySQL,
+			statement: "UPDATE t SET",
+			want:      true,
+		},
+		{
+			engine:    parser.Postgres,
+			statement: "/* cleanup */ DELETE FROM t",
+			want:      true,
+		},
+		{
+			engine:    parser.Postgres,
+			statement: "WITH c AS (SELECT id FROM s) UPDATE t SET a = 1 FROM c WHERE t.id = c.id",
+			want:      true,
+		},
+		{
+			engine:    parser.Postgres,
+			statement: "WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d",
+			want:      true,
+		},
+		{
+			engine:    parser.Postgres,
+			statement: "EXPLAIN ANALYZE UPDATE t SET a = 1",
+			want:      true,
+		},
+		{
+			engine:    parser.Postgres,
+			statement: "SELECT * FROM t WHERE name = '\"UpdateStmt\":'",
+			want:      false,
+		},
+		{
+			engine:    parser.Postgres,
+			statement: "CREATE TABLE t (a INT)",
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		got := isDMLStatement(test.engine, test.statement)
+		if got != test.want {
+			t.Errorf("isDMLStatement(%q, %q) = %v, want %v", test.engine, test.statement, got, test.want)
+		}
+	}
+}
+
+func TestValidateSQLEditorStatement(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      bool
+	}{
+		{
+			engine:    db.MySQL,
+			statement: "SELECT * FROM t;",
+			want:      true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "\n explain select * from t",
+			want:      true,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SET search_path TO public;",
+			want:      true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "set @a = 1",
+			want:      true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "CREATE TEMPORARY TABLE tmp AS SELECT * FROM t",
+			want:      true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "create temp table tmp (id int)",
+			want:      true,
+		},
+		{
+			// Postgres rejects creating the temporary tables in the READ ONLY transaction.
+			engine:    db.Postgres,
+			statement: "CREATE TEMPORARY TABLE tmp AS SELECT * FROM t",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "CREATE TABLE t (id int)",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SET GLOBAL max_connections = 1",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "set @@global.max_connections = 1",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SET TRANSACTION READ WRITE",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "INSERT INTO t VALUES (1)",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SET NAMES utf8mb4",
+			want:      true,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SET @@SESSION.time_zone = '+00:00', @b = 2",
+			want:      true,
+		},
+		{
+			engine:    db.TiDB,
+			statement: "SET SESSION TRANSACTION READ WRITE",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SET @@SESSION.transaction_read_only = 0",
+			want:      false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SET time_zone = '+00:00', transaction_read_only = 0",
+			want:      false,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SET TIME ZONE 'UTC'",
+			want:      true,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SET default_transaction_read_only = off",
+			want:      false,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SET SESSION CHARACTERISTICS AS TRANSACTION READ WRITE",
+			want:      false,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SET ROLE postgres",
+			want:      false,
+		},
+		{
+			// The SET statements of the engines we cannot parse are not allowed.
+			engine:    db.Snowflake,
+			statement: "SET a = 1",
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		got := validateSQLEditorStatement(test.engine, test.statement)
+		if got != test.want {
+			t.Errorf("validateSQLEditorStatement(%q, %q) = %v, want %v", test.engine, test.statement, got, test.want)
+		}
+	}
+}
+
+func TestSplitSQLEditorStatement(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      []string
+	}{
+		{
+			engine:    db.MySQL,
+			statement: "SET @a = 1;\nSELECT ';', @a;",
+			want:      []string{"SET @a = 1;", "SELECT ';', @a;"},
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SELECT 1",
+			want:      []string{"SELECT 1"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := splitSQLEditorStatement(test.engine, test.statement)
+		if err != nil {
+			t.Fatalf("splitSQLEditorStatement(%q) got error: %v", test.statement, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitSQLEditorStatement(%q) = %q, want %q", test.statement, got, test.want)
+		}
+	}
+}
@@ -53,20 +53,39 @@ func (s *Server) registerStageRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
 		}
-		if !ok {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to change task status")
-		}
 		var tasksPatched []*api.Task
 		for _, task := range tasks {
-			taskPatched, err := s.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
-				IDList:    []int{task.ID},
-				UpdaterID: stageAllTaskStatusPatch.UpdaterID,
-				Status:    stageAllTaskStatusPatch.Status,
-			})
+			// The approval rules depend on the risk level of each task.
+			approvalRuleList, riskLevel, err := s.getTaskApprovalRuleList(ctx, task, stageAllTaskStatusPatch.Status)
+			if err != nil {
+				if common.ErrorCode(err) == common.Conflict {
+					return echo.NewHTTPError(http.StatusConflict, common.ErrorMessage(err))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the approval rules of the task").SetInternal(err)
+			}
+			if len(approvalRuleList) == 0 && !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to change task status")
+			}
+			var taskPatched *api.Task
+			if len(approvalRuleList) > 0 {
+				taskPatched, err = s.approveTask(ctx, task, currentPrincipalID, approvalRuleList, riskLevel, nil /* comment */)
+			} else {
+				taskPatched, err = s.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+					IDList:    []int{task.ID},
+					UpdaterID: stageAllTaskStatusPatch.UpdaterID,
+					Status:    stageAllTaskStatusPatch.Status,
+				})
+			}
 			if err != nil {
 				if common.ErrorCode(err) == common.Invalid {
 					return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessage(err))
 				}
+				if common.ErrorCode(err) == common.NotAuthorized {
+					return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessage(err))
+				}
+				if common.ErrorCode(err) == common.Conflict {
+					return echo.NewHTTPError(http.StatusConflict, common.ErrorMessage(err))
+				}
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update task \"%v\" status", task.Name)).SetInternal(err)
 			}
 			tasksPatched = append(tasksPatched, taskPatched)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/google/jsonapi"
@@ -26,11 +27,24 @@ var (
 		api.TaskFailed:          {api.TaskRunning, api.TaskPendingApproval},
 		api.TaskCanceled:        {api.TaskPendingApproval},
 	}
-	taskCancellationImplemented = map[api.TaskType]bool{
-		api.TaskDatabaseSchemaUpdateGhostSync: true,
-	}
 )
 
+// getTaskCancelReason returns the reason of canceling the task, which is recorded in the task run result and the migration history.
+func (s *Server) getTaskCancelReason(ctx context.Context, taskStatusPatch *api.TaskStatusPatch) (string, error) {
+	principal, err := s.store.GetPrincipalByID(ctx, taskStatusPatch.UpdaterID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find principal ID %d", taskStatusPatch.UpdaterID)
+	}
+	reason := "canceled"
+	if principal != nil {
+		reason = fmt.Sprintf("canceled by %s", principal.Name)
+	}
+	if taskStatusPatch.Comment != nil && *taskStatusPatch.Comment != "" {
+		reason = fmt.Sprintf("%s: %s", reason, *taskStatusPatch.Comment)
+	}
+	return reason, nil
+}
+
 func isTaskStatusTransitionAllowed(fromStatus, toStatus api.TaskStatus) bool {
 	for _, allowedStatus := range applicableTaskStatusTransition[fromStatus] {
 		if allowedStatus == toStatus {
@@ -79,6 +93,16 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusForbidden, api.FeatureTaskScheduleTime.AccessErrorMessage())
 		}
 
+		if taskPatch.MaintenanceWindowOverride != nil {
+			ok, err := s.canPrincipalOverrideMaintenanceWindow(ctx, taskPatch.UpdaterID, pipelineID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can override the maintenance window").SetInternal(err)
+			}
+			if !ok {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the workspace owners and the project owners can override the maintenance window")
+			}
+		}
+
 		issue, err := s.store.GetIssueByPipelineID(ctx, pipelineID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID: %d", pipelineID)).SetInternal(err)
@@ -144,6 +168,16 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Task ID not found: %d", taskID))
 		}
 
+		if taskPatch.MaintenanceWindowOverride != nil {
+			ok, err := s.canPrincipalOverrideMaintenanceWindow(ctx, taskPatch.UpdaterID, task.PipelineID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can override the maintenance window").SetInternal(err)
+			}
+			if !ok {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the workspace owners and the project owners can override the maintenance window")
+			}
+		}
+
 		issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID: %d", task.PipelineID)).SetInternal(err)
@@ -199,19 +233,37 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
 		}
 
-		ok, err := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, task, taskStatusPatch.Status)
+		approvalRuleList, riskLevel, err := s.getTaskApprovalRuleList(ctx, task, taskStatusPatch.Status)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
-		}
-		if !ok {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to change task status")
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, common.ErrorMessage(err))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the approval rules of the task").SetInternal(err)
+		}
+		var taskPatched *api.Task
+		if len(approvalRuleList) > 0 {
+			// The task requires multiple approvals, and the task status changes only if all the approval rules are satisfied.
+			taskPatched, err = s.approveTask(ctx, task, currentPrincipalID, approvalRuleList, riskLevel, taskStatusPatch.Comment)
+		} else {
+			ok, checkErr := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, task, taskStatusPatch.Status)
+			if checkErr != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(checkErr)
+			}
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to change task status")
+			}
+			taskPatched, err = s.patchTaskStatus(ctx, task, taskStatusPatch)
 		}
-
-		taskPatched, err := s.patchTaskStatus(ctx, task, taskStatusPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.Invalid {
 				return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessage(err))
 			}
+			if common.ErrorCode(err) == common.NotAuthorized {
+				return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessage(err))
+			}
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, common.ErrorMessage(err))
+			}
 			if common.ErrorCode(err) == common.NotImplemented {
 				return echo.NewHTTPError(http.StatusNotImplemented, common.ErrorMessage(err))
 			}
@@ -251,6 +303,129 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	// The requester reveals the password of the temporary database user granted by the database grant task.
+	// The password is cleared after the reveal, so it can only be revealed once.
+	g.POST("/pipeline/:pipelineID/task/:taskID/grant/credential", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get task with ID %d", taskID)).SetInternal(err)
+		}
+		if task == nil || task.Type != api.TaskDatabaseGrant {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database grant task not found with ID %d", taskID))
+		}
+		issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get issue of task %d", taskID)).SetInternal(err)
+		}
+		if issue == nil || issue.CreatorID != c.Get(getPrincipalIDContextKey()).(int) {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the requester can reveal the database grant credential")
+		}
+		if task.Status != api.TaskDone {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The database grant task %q is not done", task.Name))
+		}
+
+		userName := getDatabaseGrantUserName(task.ID)
+		instanceUserName := getDatabaseGrantInstanceUserName(task.Instance.Engine, userName)
+		instanceUser, err := s.store.GetInstanceUser(ctx, &api.InstanceUserFind{
+			InstanceID: &task.InstanceID,
+			Name:       &instanceUserName,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the granted user %q", userName)).SetInternal(err)
+		}
+		if instanceUser == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("The granted user %q has been revoked", userName))
+		}
+		password, err := s.store.RevealInstanceUserPassword(ctx, instanceUser.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to reveal the password of the granted user %q", userName)).SetInternal(err)
+		}
+		if password == "" {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("The password of the granted user %q has already been revealed", userName))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.DatabaseGrantCredential{
+			ID:       task.ID,
+			Username: userName,
+			Password: password,
+			ExpireTs: instanceUser.ExpireTs,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal the database grant credential response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// The logs of a running task run can be tailed by polling with afterId set to the ID of the last log received.
+	g.GET("/pipeline/:pipelineID/task/:taskID/run/:taskRunID/log", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		taskRunID, err := strconv.Atoi(c.Param("taskRunID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task run ID is not a number: %s", c.Param("taskRunID"))).SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get task with ID %d", taskID)).SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+		found := false
+		for _, taskRun := range task.TaskRunList {
+			if taskRun.ID == taskRunID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task run not found with ID %d in task %d", taskRunID, taskID))
+		}
+
+		limit := api.DefaultTaskRunLogLimit
+		taskRunLogFind := &api.TaskRunLogFind{
+			TaskRunID: &taskRunID,
+			Limit:     &limit,
+		}
+		if afterIDStr := c.QueryParam("afterId"); afterIDStr != "" {
+			afterID, err := strconv.Atoi(afterIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter afterId is not a number: %s", afterIDStr)).SetInternal(err)
+			}
+			taskRunLogFind.AfterID = &afterID
+		}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a number: %s", limitStr)).SetInternal(err)
+			}
+			if limit <= 0 || limit > api.DefaultTaskRunLogLimit {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit should be between 1 and %d", api.DefaultTaskRunLogLimit))
+			}
+			taskRunLogFind.Limit = &limit
+		}
+		taskRunLogList, err := s.store.FindTaskRunLog(ctx, taskRunLogFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the logs of task run %d", taskRunID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, taskRunLogList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal the logs of task run %d response", taskRunID)).SetInternal(err)
+		}
+		return nil
+	})
 }
 
 func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.TaskPatch, issue *api.Issue) (*api.Task, *echo.HTTPError) {
@@ -486,6 +661,39 @@ func (s *Server) patchTask(ctx context.Context, task *api.Task, taskPatch *api.T
 		}
 	}
 
+	// maintenance window override update, create an activity recording who overrides the maintenance window.
+	if taskPatched.MaintenanceWindowOverride != task.MaintenanceWindowOverride {
+		if issue == nil {
+			err := errors.Errorf("issue not found with pipeline ID %v", task.PipelineID)
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+
+		payload, err := json.Marshal(api.ActivityPipelineTaskMaintenanceWindowOverrideUpdatePayload{
+			TaskID:    taskPatched.ID,
+			Override:  taskPatched.MaintenanceWindowOverride,
+			TaskName:  task.Name,
+			IssueName: issue.Name,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrapf(err, "failed to marshal maintenance window override activity payload: %v", task.Name))
+		}
+		level := api.ActivityInfo
+		if taskPatched.MaintenanceWindowOverride {
+			level = api.ActivityWarn
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+			CreatorID:   taskPatch.UpdaterID,
+			ContainerID: taskPatched.PipelineID,
+			Type:        api.ActivityPipelineTaskMaintenanceWindowOverrideUpdate,
+			Payload:     string(payload),
+			Level:       level,
+		}, &ActivityMeta{
+			issue: issue,
+		}); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after overriding task maintenance window: %v", taskPatched.Name)).SetInternal(err)
+		}
+	}
+
 	// earliest allowed time update.
 	// - create an activity.
 	// - dismiss stale approval.
@@ -631,6 +839,285 @@ func (s *Server) canPrincipalChangeTaskStatus(ctx context.Context, principalID i
 	return false, nil
 }
 
+// canPrincipalOverrideMaintenanceWindow returns true if the principal is the workspace owner or the owner of the pipeline project.
+func (s *Server) canPrincipalOverrideMaintenanceWindow(ctx context.Context, principalID int, pipelineID int) (bool, error) {
+	principal, err := s.store.GetPrincipalByID(ctx, principalID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get principal by ID %d", principalID)
+	}
+	if principal == nil {
+		return false, nil
+	}
+	if principal.Role == api.Owner {
+		return true, nil
+	}
+	issue, err := s.store.GetIssueByPipelineID(ctx, pipelineID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to find issue by pipeline ID %d", pipelineID)
+	}
+	if issue == nil {
+		return false, nil
+	}
+	member, err := s.store.GetProjectMember(ctx, &api.ProjectMemberFind{
+		ProjectID:   &issue.ProjectID,
+		PrincipalID: &principalID,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get project member by projectID %d, principalID %d", issue.ProjectID, principalID)
+	}
+	return member != nil && member.Role == string(api.Owner), nil
+}
+
+// getTaskApprovalRuleList returns the approval rules for the task risk level if approving the task requires multiple approvals, or nil otherwise.
+func (s *Server) getTaskApprovalRuleList(ctx context.Context, task *api.Task, toStatus api.TaskStatus) ([]api.ApprovalRule, api.RiskLevel, error) {
+	if task.Status != api.TaskPendingApproval || toStatus != api.TaskPending {
+		return nil, "", nil
+	}
+	policy, err := s.store.GetPipelineApprovalPolicy(ctx, task.Instance.EnvironmentID)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get approval policy for environment ID %d", task.Instance.EnvironmentID)
+	}
+	// Skip computing the risk level if the policy doesn't depend on it.
+	riskLevel := api.RiskLevelLow
+	if len(policy.RiskLevelApprovalRuleMap) > 0 {
+		riskLevel, err = s.getTaskRiskLevel(ctx, task)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return policy.GetApprovalRuleList(riskLevel), riskLevel, nil
+}
+
+// getTaskRiskLevel returns the risk level of the task computed from the latest statement type and advise check runs.
+// It returns the Conflict error if any of the check runs is still running, and the high risk level if any of the check runs is not done or missing.
+func (s *Server) getTaskRiskLevel(ctx context.Context, task *api.Task) (api.RiskLevel, error) {
+	riskLevel := api.RiskLevelLow
+	for i := range api.RiskTaskCheckTypeList {
+		taskCheckRunList, err := s.store.FindTaskCheckRun(ctx, &api.TaskCheckRunFind{
+			TaskID: &task.ID,
+			Type:   &api.RiskTaskCheckTypeList[i],
+			Latest: true,
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to find %s check runs of task %d", api.RiskTaskCheckTypeList[i], task.ID)
+		}
+		if len(taskCheckRunList) == 0 {
+			// The risk is unknown if the check run is expected but missing, so we consider it high.
+			if isRiskTaskCheckExpected(task, api.RiskTaskCheckTypeList[i], s.profile.Mode) {
+				return api.RiskLevelHigh, nil
+			}
+			continue
+		}
+		if taskCheckRunList[0].Status == api.TaskCheckRunRunning {
+			return "", common.Errorf(common.Conflict, "the risk level of the task %q is unknown until the task checks finish", task.Name)
+		}
+		checkRiskLevel, err := api.GetTaskCheckRunRiskLevel(taskCheckRunList[0])
+		if err != nil {
+			return "", err
+		}
+		riskLevel = api.HigherRiskLevel(riskLevel, checkRiskLevel)
+	}
+	return riskLevel, nil
+}
+
+// isRiskTaskCheckExpected returns true if the task check scheduler schedules the check run of the type for the task.
+func isRiskTaskCheckExpected(task *api.Task, checkType api.TaskCheckType, mode common.ReleaseMode) bool {
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate, api.TaskDatabaseSchemaUpdateSDL, api.TaskDatabaseDataUpdate, api.TaskDatabaseSchemaUpdateGhostSync:
+	default:
+		return false
+	}
+	if task.Instance == nil {
+		return false
+	}
+	switch checkType {
+	case api.TaskCheckDatabaseStatementType:
+		return api.IsStatementTypeCheckSupported(task.Instance.Engine)
+	case api.TaskCheckDatabaseStatementAdvise:
+		return api.IsSQLReviewSupported(task.Instance.Engine, mode)
+	}
+	return false
+}
+
+// taskApprovalMuCount is the number of the locks serializing the task approvals.
+const taskApprovalMuCount = 64
+
+// approveTask records the approval of the principal as an activity, and changes the task status to PENDING if the approvals satisfy all the rules.
+// The issue creator can't approve the task, and each approver is counted for at most one rule.
+func (s *Server) approveTask(ctx context.Context, task *api.Task, principalID int, ruleList []api.ApprovalRule, riskLevel api.RiskLevel, comment *string) (*api.Task, error) {
+	// Serialize the approvals of the task, so that the concurrent approvals see each other's activity
+	// when deciding whether the rules are satisfied, and only one of them approves the task.
+	mu := &s.taskApprovalMuList[task.ID%taskApprovalMuCount]
+	mu.Lock()
+	defer mu.Unlock()
+	// Re-fetch the task in case it was approved by another approval while waiting for the lock.
+	task, err := s.store.GetTaskByID(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get task by ID")
+	}
+	if task == nil {
+		return nil, common.Errorf(common.NotFound, "task not found")
+	}
+	if task.Status != api.TaskPendingApproval {
+		return nil, common.Errorf(common.Conflict, "the task %q is no longer pending approval", task.Name)
+	}
+
+	issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find issue by pipeline ID %d", task.PipelineID)
+	}
+	if issue == nil {
+		return nil, common.Errorf(common.NotFound, "issue not found by pipeline ID %d", task.PipelineID)
+	}
+	if principalID == issue.CreatorID || principalID == task.CreatorID {
+		return nil, common.Errorf(common.NotAuthorized, "the creator cannot approve the task %q", task.Name)
+	}
+
+	groupList, err := s.getPrincipalApprovalGroupList(ctx, principalID, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	var approverGroupList []api.ApprovalGroupValue
+	for _, rule := range ruleList {
+		for _, group := range groupList {
+			if group == rule.Group {
+				approverGroupList = append(approverGroupList, group)
+			}
+		}
+	}
+	if len(approverGroupList) == 0 {
+		return nil, common.Errorf(common.NotAuthorized, "the principal is not in any approval group of the task %q", task.Name)
+	}
+
+	approverGroupMap, err := s.getTaskApproverGroupMap(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := approverGroupMap[principalID]; ok {
+		return nil, common.Errorf(common.Conflict, "the principal has already approved the task %q", task.Name)
+	}
+	var approvalList [][]api.ApprovalGroupValue
+	for _, groupList := range approverGroupMap {
+		approvalList = append(approvalList, groupList)
+	}
+	approvalList = append(approvalList, approverGroupList)
+	approved := api.IsApprovalRuleListSatisfied(ruleList, approvalList)
+
+	payload, err := json.Marshal(api.ActivityPipelineTaskApprovalCreatePayload{
+		TaskID:            task.ID,
+		ApproverGroupList: approverGroupList,
+		RiskLevel:         riskLevel,
+		Approved:          approved,
+		IssueName:         issue.Name,
+		TaskName:          task.Name,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal activity after approving the task: %v", task.Name)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   principalID,
+		ContainerID: task.PipelineID,
+		Type:        api.ActivityPipelineTaskApprovalCreate,
+		Level:       api.ActivityInfo,
+		Payload:     string(payload),
+	}
+	if comment != nil {
+		activityCreate.Comment = *comment
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{issue: issue}); err != nil {
+		return nil, errors.Wrapf(err, "failed to create activity after approving the task: %v", task.Name)
+	}
+
+	if !approved {
+		return task, nil
+	}
+	return s.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+		IDList:    []int{task.ID},
+		UpdaterID: principalID,
+		Status:    api.TaskPending,
+	})
+}
+
+// getPrincipalApprovalGroupList returns the approval groups which the principal belongs to in the project.
+func (s *Server) getPrincipalApprovalGroupList(ctx context.Context, principalID int, projectID int) ([]api.ApprovalGroupValue, error) {
+	principal, err := s.store.GetPrincipalByID(ctx, principalID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get principal by ID %d", principalID)
+	}
+	if principal == nil {
+		return nil, common.Errorf(common.NotFound, "principal not found by ID %d", principalID)
+	}
+	var groupList []api.ApprovalGroupValue
+	switch principal.Role {
+	case api.Owner:
+		groupList = append(groupList, api.ApprovalGroupValueWorkspaceOwner, api.ApprovalGroupValueWorkspaceOwnerOrDBA)
+	case api.DBA:
+		groupList = append(groupList, api.ApprovalGroupValueWorkspaceDBA, api.ApprovalGroupValueWorkspaceOwnerOrDBA)
+	}
+	member, err := s.store.GetProjectMember(ctx, &api.ProjectMemberFind{
+		ProjectID:   &projectID,
+		PrincipalID: &principalID,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project member by projectID %d, principalID %d", projectID, principalID)
+	}
+	if member != nil && member.Role == string(api.Owner) {
+		groupList = append(groupList, api.ApprovalGroupValueProjectOwner)
+	}
+	return groupList, nil
+}
+
+// getTaskApproverGroupMap returns the approval groups of each approver in the current approval round of the task.
+// The round restarts when the task statement is updated or the task goes back to PENDING_APPROVAL.
+func (s *Server) getTaskApproverGroupMap(ctx context.Context, task *api.Task) (map[int][]api.ApprovalGroupValue, error) {
+	typePrefix := "bb.pipeline.task."
+	activityList, err := s.store.FindActivity(ctx, &api.ActivityFind{
+		ContainerID: &task.PipelineID,
+		TypePrefix:  &typePrefix,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find activities of pipeline %d", task.PipelineID)
+	}
+	return getApproverGroupMap(task.ID, activityList)
+}
+
+func getApproverGroupMap(taskID int, activityList []*api.Activity) (map[int][]api.ApprovalGroupValue, error) {
+	// The created_ts is in seconds, so we sort by ID for the exact order.
+	sort.Slice(activityList, func(i, j int) bool {
+		return activityList[i].ID < activityList[j].ID
+	})
+	approverGroupMap := make(map[int][]api.ApprovalGroupValue)
+	for _, activity := range activityList {
+		switch activity.Type {
+		case api.ActivityPipelineTaskStatementUpdate:
+			payload := &api.ActivityPipelineTaskStatementUpdatePayload{}
+			if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal activity payload %q", activity.Payload)
+			}
+			if payload.TaskID == taskID {
+				approverGroupMap = make(map[int][]api.ApprovalGroupValue)
+			}
+		case api.ActivityPipelineTaskStatusUpdate:
+			payload := &api.ActivityPipelineTaskStatusUpdatePayload{}
+			if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal activity payload %q", activity.Payload)
+			}
+			if payload.TaskID == taskID && payload.NewStatus == api.TaskPendingApproval {
+				approverGroupMap = make(map[int][]api.ApprovalGroupValue)
+			}
+		case api.ActivityPipelineTaskApprovalCreate:
+			payload := &api.ActivityPipelineTaskApprovalCreatePayload{}
+			if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal activity payload %q", activity.Payload)
+			}
+			if payload.TaskID == taskID {
+				approverGroupMap[activity.CreatorID] = payload.ApproverGroupList
+			}
+		}
+	}
+	return approverGroupMap, nil
+}
+
 func (s *Server) getGroupValueForTask(ctx context.Context, issue *api.Issue, task *api.Task) (*api.AssigneeGroupValue, error) {
 	environmentID := api.UnknownID
 	for _, stage := range issue.Pipeline.StageList {
@@ -680,19 +1167,20 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 		return nil, errors.Errorf("expect to patch 1 task, get %d", len(taskStatusPatch.IDList))
 	}
 
+	var cancelReason string
 	if taskStatusPatch.Status == api.TaskCanceled {
-		if !taskCancellationImplemented[task.Type] {
-			return nil, common.Errorf(common.NotImplemented, "Canceling task type %s is not supported", task.Type)
+		reason, err := s.getTaskCancelReason(ctx, taskStatusPatch)
+		if err != nil {
+			return nil, err
 		}
-		s.TaskScheduler.runningExecutorsMutex.Lock()
-		cancel, ok := s.TaskScheduler.runningExecutorsCancel[task.ID]
-		s.TaskScheduler.runningExecutorsMutex.Unlock()
-		if !ok {
-			return nil, errors.New("failed to cancel task")
+		cancelReason = reason
+		// A pending task hasn't started running, so there is nothing to cancel in the scheduler.
+		// A running task may have no executor yet, e.g. it's queued by the concurrency limits, and we only need to change its status.
+		if task.Status == api.TaskRunning {
+			s.TaskScheduler.cancelRunningExecutor(task.ID, reason)
 		}
-		cancel()
 		result, err := json.Marshal(api.TaskRunResultPayload{
-			Detail: "Task cancellation requested.",
+			Detail: fmt.Sprintf("Task cancellation requested, %s.", reason),
 		})
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to marshal TaskRunResultPayload")
@@ -706,6 +1194,11 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 		return nil, errors.Wrapf(err, "failed to change task %v(%v) status", task.ID, task.Name)
 	}
 	taskPatched := taskPatchedList[0]
+	if cancelReason != "" && task.Status == api.TaskRunning {
+		// The executor may start before the status is changed, so we cancel it again after the status is changed.
+		// The executor starting after the status is changed skips the canceled task by itself.
+		s.TaskScheduler.cancelRunningExecutor(task.ID, cancelReason)
+	}
 
 	// Most tasks belong to a pipeline which in turns belongs to an issue. The followup code
 	// behaves differently depending on whether the task is wrapped in an issue.
@@ -775,6 +1268,13 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 		}
 	}
 
+	// Halt the stage rollout if the failed task makes the failed tasks exceed the tolerated percentage.
+	if taskPatched.Status == api.TaskFailed {
+		if err := s.createStageRolloutHaltActivity(ctx, taskPatched, issue); err != nil {
+			return nil, errors.Wrapf(err, "failed to check the stage rollout for task %d", taskPatched.ID)
+		}
+	}
+
 	// Cancel every task depending on the canceled task.
 	if taskPatched.Status == api.TaskCanceled {
 		if err := s.cancelDependingTasks(ctx, taskPatched); err != nil {
@@ -785,7 +1285,8 @@ func (s *Server) patchTaskStatus(ctx context.Context, task *api.Task, taskStatus
 	// If every task in the pipeline completes, and the assignee is system bot:
 	// Case 1: If the task is associated with an issue, then we mark the issue (including the pipeline) as DONE.
 	// Case 2: If the task is NOT associated with an issue, then we mark the pipeline as DONE.
-	if taskPatched.Status == "DONE" && (issue == nil || issue.AssigneeID == api.SystemBotID) {
+	// A failed task may complete the pipeline as well if the rollout tolerates it.
+	if (taskPatched.Status == api.TaskDone || taskPatched.Status == api.TaskFailed) && (issue == nil || issue.AssigneeID == api.SystemBotID) {
 		pipeline, err := s.store.GetPipelineByID(ctx, taskPatched.PipelineID)
 		if err != nil {
 			return nil, errors.Errorf("failed to fetch pipeline/issue as DONE after completing task %v", taskPatched.Name)
@@ -864,7 +1365,8 @@ func (s *Server) getDefaultAssigneeID(ctx context.Context, environmentID int, pr
 	if err != nil {
 		return api.UnknownID, errors.Wrapf(err, "failed to GetPipelineApprovalPolicy for environmentID %d", environmentID)
 	}
-	if policy.Value == api.PipelineApprovalValueManualNever {
+	// The database grant and data source request issues are always approved manually, so they need a real assignee.
+	if policy.Value == api.PipelineApprovalValueManualNever && issueType != api.IssueDatabaseGrant && issueType != api.IssueDataSourceRequest {
 		// use SystemBot for auto approval tasks.
 		return api.SystemBotID, nil
 	}
@@ -893,12 +1395,12 @@ func (s *Server) getDefaultAssigneeID(ctx context.Context, environmentID int, pr
 	return api.UnknownID, errors.New("invalid assigneeGroupValue")
 }
 
+// areAllTasksDone returns true if every stage of the pipeline is completed,
+// including the stages whose failed tasks are tolerated by the rollout.
 func areAllTasksDone(pipeline *api.Pipeline) bool {
 	for _, stage := range pipeline.StageList {
-		for _, task := range stage.TaskList {
-			if task.Status != api.TaskDone {
-				return false
-			}
+		if !isStageCompleted(stage) {
+			return false
 		}
 	}
 	return true
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
@@ -67,11 +69,46 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 		return nil, err
 	}
 
+	var connection *sql.DB
+	var skippedResult *api.TaskCheckResult
+	if isDatabaseAccessRequired(dbType, policy.RuleList) {
+		database, err := server.store.GetDatabase(ctx, &api.DatabaseFind{ID: task.DatabaseID})
+		if err != nil {
+			return nil, common.Wrapf(err, common.Internal, "failed to get database by id")
+		}
+		if database == nil {
+			return nil, common.Errorf(common.NotFound, "database ID not found %v", task.DatabaseID)
+		}
+		// The advisors only run EXPLAIN without ANALYZE, but we never run them with the admin data source for safety.
+		// Without a read-only data source, the connection stays nil and the advisors skip the check.
+		if dataSource := api.DataSourceFromInstanceWithType(database.Instance, api.RO); dataSource != nil {
+			driver, err := getReadOnlyDatabaseDriverWithDataSource(ctx, database.Instance, database.Name, dataSource)
+			if err != nil {
+				return nil, err
+			}
+			defer driver.Close(ctx)
+			connection, err = driver.GetDBConnection(ctx, database.Name)
+			if err != nil {
+				return nil, common.Wrapf(err, common.Internal, "failed to get connection for database %q", database.Name)
+			}
+		} else {
+			skippedResult = &api.TaskCheckResult{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "Skipped the rules requiring database access",
+				Content:   fmt.Sprintf("The affected row limit and DML dry run rules are skipped because instance %q has no read-only data source.", database.Instance.Name),
+			}
+		}
+	}
+
 	adviceList, err := advisor.SQLReviewCheck(payload.Statement, policy.RuleList, advisor.SQLReviewCheckContext{
 		Charset:   payload.Charset,
 		Collation: payload.Collation,
 		DbType:    dbType,
 		Catalog:   catalog,
+		Driver:    connection,
+		Context:   ctx,
 	})
 	if err != nil {
 		return nil, err
@@ -98,6 +135,10 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 		})
 	}
 
+	if skippedResult != nil {
+		result = append(result, *skippedResult)
+	}
+
 	if len(result) == 0 {
 		result = append(result, api.TaskCheckResult{
 			Status:    api.TaskCheckStatusSuccess,
@@ -110,3 +151,24 @@ func (*TaskCheckStatementAdvisorCompositeExecutor) Run(ctx context.Context, serv
 
 	return result, nil
 }
+
+// isDatabaseAccessRequired returns true if any enabled rule needs to access the database, e.g. running EXPLAIN.
+// MySQL and TiDB only estimate the affected rows, and don't support the DML dry run yet.
+func isDatabaseAccessRequired(dbType advisorDB.Type, ruleList []*advisor.SQLReviewRule) bool {
+	for _, rule := range ruleList {
+		if rule.Level == advisor.SchemaRuleLevelDisabled {
+			continue
+		}
+		switch dbType {
+		case advisorDB.Postgres:
+			if rule.Type == advisor.SchemaRuleStatementAffectedRowLimit || rule.Type == advisor.SchemaRuleStatementDMLDryRun {
+				return true
+			}
+		case advisorDB.MySQL, advisorDB.TiDB:
+			if rule.Type == advisor.SchemaRuleStatementAffectedRowLimit {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -37,6 +37,10 @@ func (*TaskCheckStatementAdvisorSimpleExecutor) Run(_ context.Context, _ *Server
 			advisorType = advisor.MySQLSyntax
 		case db.Postgres:
 			advisorType = advisor.PostgreSQLSyntax
+		case db.ClickHouse:
+			advisorType = advisor.ClickHouseSyntax
+		case db.Snowflake:
+			advisorType = advisor.SnowflakeSyntax
 		default:
 			return nil, common.Errorf(common.Invalid, "invalid database type: %s for syntax statement advisor", payload.DbType)
 		}
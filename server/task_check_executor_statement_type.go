@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	tidbparser "github.com/pingcap/tidb/parser"
 	tidbast "github.com/pingcap/tidb/parser/ast"
@@ -16,6 +17,9 @@ import (
 	"github.com/bytebase/bytebase/plugin/parser/ast"
 )
 
+// truncateRegexp matches the TRUNCATE statement, which the Postgres parser doesn't convert.
+var truncateRegexp = regexp.MustCompile(`(?is)^\s*TRUNCATE\s`)
+
 // NewTaskCheckStatementTypeExecutor creates a task check DML executor.
 func NewTaskCheckStatementTypeExecutor() TaskCheckExecutor {
 	return &TaskCheckStatementTypeExecutor{}
@@ -63,6 +67,14 @@ func (*TaskCheckStatementTypeExecutor) Run(ctx context.Context, server *Server,
 		return nil, common.Errorf(common.Invalid, "invalid check statement type database type: %s", payload.DbType)
 	}
 
+	if task.DatabaseID != nil {
+		sensitiveColumnList, err := getSensitiveColumnList(ctx, server, *task.DatabaseID)
+		if err != nil {
+			return nil, common.Wrap(err, common.Internal)
+		}
+		result = append(result, sensitiveColumnCheck(payload.Statement, sensitiveColumnList)...)
+	}
+
 	if len(result) == 0 {
 		result = append(result, api.TaskCheckResult{
 			Status:    api.TaskCheckStatusSuccess,
@@ -128,6 +140,12 @@ func mysqlStatementTypeCheck(statement string, charset string, collation string,
 		}, nil
 	}
 
+	for _, node := range stmts {
+		if isMySQLDestructiveDDL(node) {
+			result = append(result, newDestructiveDDLCheckResult(node.Text()))
+		}
+	}
+
 	switch taskType {
 	case api.TaskDatabaseDataUpdate:
 		for _, node := range stmts {
@@ -180,6 +198,12 @@ func postgresqlStatementTypeCheck(statement string, taskType api.TaskType) (resu
 		}, nil
 	}
 
+	for _, node := range stmts {
+		if isPostgresDestructiveDDL(node) {
+			result = append(result, newDestructiveDDLCheckResult(node.Text()))
+		}
+	}
+
 	switch taskType {
 	case api.TaskDatabaseDataUpdate:
 		for _, node := range stmts {
@@ -217,3 +241,119 @@ func postgresqlStatementTypeCheck(statement string, taskType api.TaskType) (resu
 
 	return result, nil
 }
+
+// isMySQLDestructiveDDL returns true if the statement drops or rewrites the existing tables, columns or data.
+func isMySQLDestructiveDDL(node tidbast.StmtNode) bool {
+	switch node := node.(type) {
+	case *tidbast.DropDatabaseStmt, *tidbast.DropTableStmt, *tidbast.TruncateTableStmt, *tidbast.RenameTableStmt:
+		return true
+	case *tidbast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case tidbast.AlterTableDropColumn,
+				tidbast.AlterTableModifyColumn,
+				tidbast.AlterTableChangeColumn,
+				tidbast.AlterTableRenameColumn,
+				tidbast.AlterTableRenameTable,
+				tidbast.AlterTableDropPartition,
+				tidbast.AlterTableTruncatePartition:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPostgresDestructiveDDL returns true if the statement drops or rewrites the existing tables, columns or data.
+func isPostgresDestructiveDDL(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.DropDatabaseStmt, *ast.DropTableStmt, *ast.RenameTableStmt:
+		return true
+	case *ast.AlterTableStmt:
+		for _, item := range node.AlterItemList {
+			switch item.(type) {
+			case *ast.DropColumnStmt, *ast.AlterColumnTypeStmt, *ast.RenameColumnStmt, *ast.RenameTableStmt:
+				return true
+			}
+		}
+	case *ast.UnconvertedStmt:
+		return truncateRegexp.MatchString(node.Text())
+	}
+	return false
+}
+
+func newDestructiveDDLCheckResult(text string) api.TaskCheckResult {
+	return api.TaskCheckResult{
+		Status:    api.TaskCheckStatusWarn,
+		Namespace: api.BBNamespace,
+		Code:      common.TaskRiskDestructiveDDL.Int(),
+		Title:     "Destructive schema change",
+		Content:   fmt.Sprintf("\"%s\" drops or rewrites the existing data", text),
+	}
+}
+
+// sensitiveColumn is a column classified as sensitive.
+type sensitiveColumn struct {
+	table       string
+	column      string
+	sensitivity api.ColumnSensitivity
+}
+
+func getSensitiveColumnList(ctx context.Context, server *Server, databaseID int) ([]sensitiveColumn, error) {
+	columnList, err := server.store.FindColumn(ctx, &api.ColumnFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, err
+	}
+	var tableNameMap map[int]string
+	var sensitiveColumnList []sensitiveColumn
+	for _, column := range columnList {
+		if column.Sensitivity == "" || column.Sensitivity == api.ColumnSensitivityNone {
+			continue
+		}
+		// Lazily find the tables, because most databases have no sensitive column.
+		if tableNameMap == nil {
+			tableList, err := server.store.FindTable(ctx, &api.TableFind{DatabaseID: &databaseID})
+			if err != nil {
+				return nil, err
+			}
+			tableNameMap = make(map[int]string)
+			for _, table := range tableList {
+				tableNameMap[table.ID] = table.Name
+			}
+		}
+		sensitiveColumnList = append(sensitiveColumnList, sensitiveColumn{
+			table:       tableNameMap[column.TableID],
+			column:      column.Name,
+			sensitivity: column.Sensitivity,
+		})
+	}
+	return sensitiveColumnList, nil
+}
+
+// sensitiveColumnCheck returns the results for the sensitive columns which the statement may touch.
+// A column is regarded as touched if the statement mentions both its table and its name, which may be false positive but never misses the column.
+func sensitiveColumnCheck(statement string, sensitiveColumnList []sensitiveColumn) []api.TaskCheckResult {
+	var result []api.TaskCheckResult
+	for _, column := range sensitiveColumnList {
+		if !containsIdentifier(statement, column.table) || !containsIdentifier(statement, column.column) {
+			continue
+		}
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskRiskSensitiveColumn.Int(),
+			Title:     "Change touches sensitive column",
+			Content:   fmt.Sprintf("The statement may change the %s sensitivity column %q in table %q", column.sensitivity, column.column, column.table),
+		})
+	}
+	return result
+}
+
+// containsIdentifier returns true if the statement contains the identifier as a whole word case-insensitively.
+func containsIdentifier(statement string, identifier string) bool {
+	if identifier == "" {
+		return false
+	}
+	re := regexp.MustCompile(`(?i)(^|[^\w$])` + regexp.QuoteMeta(identifier) + `($|[^\w$])`)
+	return re.MatchString(statement)
+}
@@ -102,3 +102,62 @@ func TestStatementTypeCheck(t *testing.T) {
 		require.Equal(t, test.want, res)
 	}
 }
+
+func TestDestructiveDDLCheck(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want bool
+	}{
+		{stmt: "CREATE TABLE t(a int)", want: false},
+		{stmt: "ALTER TABLE t ADD COLUMN a int", want: false},
+		{stmt: "DROP TABLE t", want: true},
+		{stmt: "TRUNCATE TABLE t", want: true},
+		{stmt: "ALTER TABLE t DROP COLUMN a", want: true},
+		{stmt: "ALTER TABLE t RENAME COLUMN a TO b", want: true},
+		{stmt: "ALTER TABLE t RENAME TO t2", want: true},
+	}
+	for _, test := range tests {
+		var want []api.TaskCheckResult
+		if test.want {
+			want = append(want, newDestructiveDDLCheckResult(test.stmt))
+		}
+		res, err := mysqlStatementTypeCheck(test.stmt, "", "", api.TaskDatabaseSchemaUpdate)
+		require.NoError(t, err)
+		require.Equal(t, want, res, test.stmt)
+		res, err = postgresqlStatementTypeCheck(test.stmt, api.TaskDatabaseSchemaUpdate)
+		require.NoError(t, err)
+		require.Equal(t, want, res, test.stmt)
+	}
+
+	// Modifying the column type rewrites the table.
+	res, err := mysqlStatementTypeCheck("ALTER TABLE t MODIFY COLUMN a bigint", "", "", api.TaskDatabaseSchemaUpdate)
+	require.NoError(t, err)
+	require.Equal(t, []api.TaskCheckResult{newDestructiveDDLCheckResult("ALTER TABLE t MODIFY COLUMN a bigint")}, res)
+	res, err = postgresqlStatementTypeCheck("ALTER TABLE t ALTER COLUMN a TYPE bigint", api.TaskDatabaseSchemaUpdate)
+	require.NoError(t, err)
+	require.Equal(t, []api.TaskCheckResult{newDestructiveDDLCheckResult("ALTER TABLE t ALTER COLUMN a TYPE bigint")}, res)
+}
+
+func TestSensitiveColumnCheck(t *testing.T) {
+	sensitiveColumnList := []sensitiveColumn{
+		{table: "user", column: "phone", sensitivity: api.ColumnSensitivityMedium},
+		{table: "card", column: "number", sensitivity: api.ColumnSensitivityHigh},
+	}
+	tests := []struct {
+		stmt string
+		want int
+	}{
+		{stmt: "UPDATE user SET phone = '' WHERE id = 1", want: 1},
+		{stmt: "ALTER TABLE `User` MODIFY COLUMN `Phone` varchar(64)", want: 1},
+		{stmt: "UPDATE user SET name = '' WHERE id = 1", want: 0},
+		{stmt: "UPDATE user_phone SET phone_number = ''", want: 0},
+		{stmt: "UPDATE user SET phone = ''; UPDATE card SET number = ''", want: 2},
+	}
+	for _, test := range tests {
+		res := sensitiveColumnCheck(test.stmt, sensitiveColumnList)
+		require.Len(t, res, test.want, test.stmt)
+		for _, r := range res {
+			require.Equal(t, common.TaskRiskSensitiveColumn.Int(), r.Code)
+		}
+	}
+}
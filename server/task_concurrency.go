@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// taskConcurrencyLimit is the limits of the tasks running at the same time.
+type taskConcurrencyLimit struct {
+	// globalLimit is the max number of running tasks across all instances, 0 means no limit.
+	globalLimit int
+	// environmentPolicyMap is the task concurrency policy of each environment.
+	environmentPolicyMap map[int]*api.TaskConcurrencyPolicy
+}
+
+// getTaskConcurrencyLimit gets the task concurrency limits applied to the tasks.
+func (s *TaskScheduler) getTaskConcurrencyLimit(ctx context.Context, taskList []*api.Task) (*taskConcurrencyLimit, error) {
+	limit := &taskConcurrencyLimit{
+		globalLimit:          s.server.profile.MaxConcurrentTasks,
+		environmentPolicyMap: make(map[int]*api.TaskConcurrencyPolicy),
+	}
+	for _, task := range taskList {
+		if task.Instance == nil {
+			continue
+		}
+		environmentID := task.Instance.EnvironmentID
+		if _, ok := limit.environmentPolicyMap[environmentID]; ok {
+			continue
+		}
+		policy, err := s.server.store.GetTaskConcurrencyPolicyByEnvID(ctx, environmentID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the task concurrency policy for environment %d", environmentID)
+		}
+		limit.environmentPolicyMap[environmentID] = policy
+	}
+	return limit, nil
+}
+
+// pipelineTurn records when each pipeline was last served, so that the pipelines keep taking turns across the scheduling ticks.
+// It's only accessed by the task scheduler loop.
+type pipelineTurn struct {
+	seq int64
+	// servedSeqMap is the sequence number of the latest task started for each pipeline.
+	servedSeqMap map[int]int64
+}
+
+func newPipelineTurn() *pipelineTurn {
+	return &pipelineTurn{
+		servedSeqMap: make(map[int]int64),
+	}
+}
+
+// serve records that a task of the pipeline is started, moving the pipeline to the end of the turns.
+func (t *pipelineTurn) serve(pipelineID int) {
+	t.seq++
+	t.servedSeqMap[pipelineID] = t.seq
+}
+
+// getServedSeq returns the sequence number of the latest task started for the pipeline, 0 if never.
+func (t *pipelineTurn) getServedSeq(pipelineID int) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.servedSeqMap[pipelineID]
+}
+
+// prune forgets the pipelines without any executing or waiting task.
+func (t *pipelineTurn) prune(executingTaskList, waitingTaskList []*api.Task) {
+	pipelineIDMap := make(map[int]bool)
+	for _, task := range executingTaskList {
+		pipelineIDMap[task.PipelineID] = true
+	}
+	for _, task := range waitingTaskList {
+		pipelineIDMap[task.PipelineID] = true
+	}
+	for pipelineID := range t.servedSeqMap {
+		if !pipelineIDMap[pipelineID] {
+			delete(t.servedSeqMap, pipelineID)
+		}
+	}
+}
+
+// scheduleTaskQueue returns the waiting tasks to start within the concurrency limits given the executing tasks,
+// and the queue position of each task left waiting, starting from 1.
+// The waiting tasks are queued fairly across the pipelines, so that a pipeline with many tasks such as a tenant rollout doesn't starve the others.
+// The started tasks are recorded in the turn, so that the pipelines served in this tick go after the others in the next tick.
+func scheduleTaskQueue(executingTaskList, waitingTaskList []*api.Task, limit *taskConcurrencyLimit, turn *pipelineTurn) ([]*api.Task, map[int]int) {
+	globalCount := 0
+	instanceCount := make(map[int]int)
+	environmentCount := make(map[int]int)
+	for _, task := range executingTaskList {
+		globalCount++
+		instanceCount[task.InstanceID]++
+		if task.Instance != nil {
+			environmentCount[task.Instance.EnvironmentID]++
+		}
+	}
+
+	var startTaskList []*api.Task
+	queuePositionMap := make(map[int]int)
+	for _, task := range getFairTaskQueue(waitingTaskList, turn) {
+		if limit.isExceeded(task, globalCount, instanceCount, environmentCount) {
+			queuePositionMap[task.ID] = len(queuePositionMap) + 1
+			continue
+		}
+		startTaskList = append(startTaskList, task)
+		globalCount++
+		instanceCount[task.InstanceID]++
+		if task.Instance != nil {
+			environmentCount[task.Instance.EnvironmentID]++
+		}
+	}
+	for _, task := range startTaskList {
+		turn.serve(task.PipelineID)
+	}
+	turn.prune(executingTaskList, waitingTaskList)
+	return startTaskList, queuePositionMap
+}
+
+// isExceeded returns true if starting the task would exceed the concurrency limits given the running task counts.
+func (l *taskConcurrencyLimit) isExceeded(task *api.Task, globalCount int, instanceCount, environmentCount map[int]int) bool {
+	if l.globalLimit > 0 && globalCount >= l.globalLimit {
+		return true
+	}
+	if task.Instance == nil {
+		return false
+	}
+	policy, ok := l.environmentPolicyMap[task.Instance.EnvironmentID]
+	if !ok {
+		return false
+	}
+	if policy.InstanceLimit > 0 && instanceCount[task.InstanceID] >= policy.InstanceLimit {
+		return true
+	}
+	if policy.EnvironmentLimit > 0 && environmentCount[task.Instance.EnvironmentID] >= policy.EnvironmentLimit {
+		return true
+	}
+	return false
+}
+
+// getFairTaskQueue orders the tasks by taking turns among the pipelines.
+// The pipelines take turns in the order of when they were last served, then their earliest task,
+// and the tasks of a pipeline keep the order of the task ID.
+func getFairTaskQueue(taskList []*api.Task, turn *pipelineTurn) []*api.Task {
+	sortedTaskList := make([]*api.Task, len(taskList))
+	copy(sortedTaskList, taskList)
+	sort.Slice(sortedTaskList, func(i, j int) bool {
+		return sortedTaskList[i].ID < sortedTaskList[j].ID
+	})
+
+	var pipelineIDList []int
+	pipelineTaskMap := make(map[int][]*api.Task)
+	for _, task := range sortedTaskList {
+		if _, ok := pipelineTaskMap[task.PipelineID]; !ok {
+			pipelineIDList = append(pipelineIDList, task.PipelineID)
+		}
+		pipelineTaskMap[task.PipelineID] = append(pipelineTaskMap[task.PipelineID], task)
+	}
+	// The stable sort keeps the order of the earliest task among the pipelines served at the same time.
+	sort.SliceStable(pipelineIDList, func(i, j int) bool {
+		return turn.getServedSeq(pipelineIDList[i]) < turn.getServedSeq(pipelineIDList[j])
+	})
+
+	var queue []*api.Task
+	for round := 0; len(queue) < len(sortedTaskList); round++ {
+		for _, pipelineID := range pipelineIDList {
+			if round < len(pipelineTaskMap[pipelineID]) {
+				queue = append(queue, pipelineTaskMap[pipelineID][round])
+			}
+		}
+	}
+	return queue
+}
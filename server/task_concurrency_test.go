@@ -0,0 +1,143 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func newTestConcurrencyTask(id, pipelineID, instanceID, environmentID int) *api.Task {
+	return &api.Task{
+		ID:         id,
+		PipelineID: pipelineID,
+		InstanceID: instanceID,
+		Instance:   &api.Instance{ID: instanceID, EnvironmentID: environmentID},
+	}
+}
+
+func getTestTaskIDList(taskList []*api.Task) []int {
+	var idList []int
+	for _, task := range taskList {
+		idList = append(idList, task.ID)
+	}
+	return idList
+}
+
+func TestGetFairTaskQueue(t *testing.T) {
+	taskList := []*api.Task{
+		newTestConcurrencyTask(5, 2, 1, 1),
+		newTestConcurrencyTask(1, 1, 1, 1),
+		newTestConcurrencyTask(2, 1, 1, 1),
+		newTestConcurrencyTask(3, 1, 1, 1),
+		newTestConcurrencyTask(6, 3, 1, 1),
+		newTestConcurrencyTask(4, 2, 1, 1),
+	}
+	// Pipeline 1, 2 and 3 take turns in the order of their earliest task.
+	assert.Equal(t, []int{1, 4, 6, 2, 5, 3}, getTestTaskIDList(getFairTaskQueue(taskList, nil)))
+}
+
+func TestScheduleTaskQueue(t *testing.T) {
+	// Pipeline 1 is a tenant rollout on instance 1 and 2 in environment 1, and pipeline 2 has a task on instance 3 in environment 2.
+	waitingTaskList := []*api.Task{
+		newTestConcurrencyTask(1, 1, 1, 1),
+		newTestConcurrencyTask(2, 1, 1, 1),
+		newTestConcurrencyTask(3, 1, 2, 1),
+		newTestConcurrencyTask(4, 1, 2, 1),
+		newTestConcurrencyTask(5, 2, 3, 2),
+	}
+	tests := []struct {
+		name              string
+		executingTaskList []*api.Task
+		limit             *taskConcurrencyLimit
+		wantStartIDList   []int
+		wantQueuePosition map[int]int
+	}{
+		{
+			name:              "noLimit",
+			limit:             &taskConcurrencyLimit{},
+			wantStartIDList:   []int{1, 5, 2, 3, 4},
+			wantQueuePosition: map[int]int{},
+		},
+		{
+			name:              "globalLimit",
+			executingTaskList: []*api.Task{newTestConcurrencyTask(6, 3, 3, 2)},
+			limit:             &taskConcurrencyLimit{globalLimit: 3},
+			wantStartIDList:   []int{1, 5},
+			wantQueuePosition: map[int]int{2: 1, 3: 2, 4: 3},
+		},
+		{
+			name: "instanceLimit",
+			limit: &taskConcurrencyLimit{
+				environmentPolicyMap: map[int]*api.TaskConcurrencyPolicy{
+					1: {InstanceLimit: 1},
+				},
+			},
+			wantStartIDList:   []int{1, 5, 3},
+			wantQueuePosition: map[int]int{2: 1, 4: 2},
+		},
+		{
+			name:              "instanceLimitWithExecutingTask",
+			executingTaskList: []*api.Task{newTestConcurrencyTask(6, 3, 1, 1)},
+			limit: &taskConcurrencyLimit{
+				environmentPolicyMap: map[int]*api.TaskConcurrencyPolicy{
+					1: {InstanceLimit: 1},
+				},
+			},
+			wantStartIDList:   []int{5, 3},
+			wantQueuePosition: map[int]int{1: 1, 2: 2, 4: 3},
+		},
+		{
+			name: "environmentLimit",
+			limit: &taskConcurrencyLimit{
+				environmentPolicyMap: map[int]*api.TaskConcurrencyPolicy{
+					1: {EnvironmentLimit: 2},
+					2: {EnvironmentLimit: 2},
+				},
+			},
+			wantStartIDList:   []int{1, 5, 2},
+			wantQueuePosition: map[int]int{3: 1, 4: 2},
+		},
+	}
+
+	for _, test := range tests {
+		startTaskList, queuePositionMap := scheduleTaskQueue(test.executingTaskList, waitingTaskList, test.limit, newPipelineTurn())
+		assert.Equal(t, test.wantStartIDList, getTestTaskIDList(startTaskList), test.name)
+		assert.Equal(t, test.wantQueuePosition, queuePositionMap, test.name)
+	}
+}
+
+func TestScheduleTaskQueueAcrossTicks(t *testing.T) {
+	// Pipeline 1 is a tenant rollout with many tasks, and pipeline 2 and 3 are created later with a task each.
+	// Only one task runs at a time, and each task finishes before the next tick.
+	waitingTaskList := []*api.Task{
+		newTestConcurrencyTask(1, 1, 1, 1),
+		newTestConcurrencyTask(2, 1, 1, 1),
+		newTestConcurrencyTask(3, 1, 1, 1),
+		newTestConcurrencyTask(4, 1, 1, 1),
+		newTestConcurrencyTask(5, 2, 1, 1),
+		newTestConcurrencyTask(6, 3, 1, 1),
+	}
+	limit := &taskConcurrencyLimit{globalLimit: 1}
+	turn := newPipelineTurn()
+
+	var startIDList []int
+	for len(waitingTaskList) > 0 {
+		startTaskList, _ := scheduleTaskQueue(nil, waitingTaskList, limit, turn)
+		assert.Len(t, startTaskList, 1)
+		startIDList = append(startIDList, startTaskList[0].ID)
+		var remainingTaskList []*api.Task
+		for _, task := range waitingTaskList {
+			if task.ID != startTaskList[0].ID {
+				remainingTaskList = append(remainingTaskList, task)
+			}
+		}
+		waitingTaskList = remainingTaskList
+	}
+	// The pipelines take turns across the ticks instead of pipeline 1 going first on every tick.
+	assert.Equal(t, []int{1, 5, 6, 2, 3, 4}, startIDList)
+	// The pipelines are forgotten once they have no executing or waiting task.
+	scheduleTaskQueue(nil, nil, limit, turn)
+	assert.Empty(t, turn.servedSeqMap)
+}
@@ -322,11 +322,42 @@ func runMigration(ctx context.Context, server *Server, task *api.Task, migration
 	}
 	migrationID, schema, err := executeMigration(ctx, server, task, statement, mi)
 	if err != nil {
-		return true, nil, err
+		return true, nil, wrapExecuteProgressError(err, db.GetExecuteProgressTracker(ctx).GetProgress())
 	}
 	return postMigration(ctx, server, task, vcsPushEvent, mi, migrationID, schema)
 }
 
+// getExecuteProgress returns the task progress of executing the statements, in which the completed unit is the completed statement count.
+// The payload is the JSON of db.ExecuteProgress including the current statement.
+func getExecuteProgress(tracker *db.ExecuteProgressTracker) api.Progress {
+	progress := tracker.GetProgress()
+	if progress.TotalStatementCount == 0 {
+		return api.Progress{}
+	}
+	payload, err := json.Marshal(progress)
+	if err != nil {
+		log.Error("Failed to marshal execute progress", zap.Error(err))
+	}
+	return api.Progress{
+		TotalUnit:     int64(progress.TotalStatementCount),
+		CompletedUnit: int64(progress.CompletedStatementCount),
+		CreatedTs:     progress.StartTs / 1000,
+		UpdatedTs:     progress.UpdatedTs / 1000,
+		Payload:       string(payload),
+	}
+}
+
+// wrapExecuteProgressError wraps the error of executing the statements with the failed statement and the committed statement count,
+// so that the failed task run shows where to continue.
+func wrapExecuteProgressError(err error, progress db.ExecuteProgress) error {
+	// The error happens before or after executing the statements.
+	if progress.TotalStatementCount == 0 || progress.CompletedStatementCount == progress.TotalStatementCount {
+		return err
+	}
+	return errors.Wrapf(err, "failed to execute statement %d of %d at line %d, %d statement(s) completed and %d statement(s) committed",
+		progress.CompletedStatementCount+1, progress.TotalStatementCount, progress.CurrentStatementLine, progress.CompletedStatementCount, progress.CommittedStatementCount)
+}
+
 func findIssueByTask(ctx context.Context, server *Server, task *api.Task) (*api.Issue, error) {
 	issue, err := server.store.GetIssueByPipelineID(ctx, task.PipelineID)
 	if err != nil {
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// dataSourceRequestUserPrefix is the prefix of the read-only database users created by the data source request task.
+// The user name is suffixed with the database ID and the requester ID, so that the revoker can find the requested data source.
+const dataSourceRequestUserPrefix = "bb_ro_"
+
+// NewDataSourceRequestTaskExecutor creates a data source request task executor.
+func NewDataSourceRequestTaskExecutor() TaskExecutor {
+	return &DataSourceRequestTaskExecutor{}
+}
+
+// DataSourceRequestTaskExecutor is the data source request task executor.
+// It creates a read-only database user for the requester, and creates or updates the requester's read-only data source with it.
+// The database grant revoker drops the user and deletes the data source when the request expires.
+type DataSourceRequestTaskExecutor struct {
+	completed int32
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *DataSourceRequestTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (*DataSourceRequestTaskExecutor) GetProgress() api.Progress {
+	return api.Progress{}
+}
+
+// RunOnce will run the data source request task executor once.
+func (exec *DataSourceRequestTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDataSourceRequestPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, errors.Wrap(err, "invalid data source request payload")
+	}
+	if task.Database == nil {
+		return true, nil, errors.Errorf("missing database for data source request task %d", task.ID)
+	}
+	if payload.ExpireTs <= time.Now().Unix() {
+		return true, nil, errors.Errorf("the data source request has already expired at %s", time.Unix(payload.ExpireTs, 0).UTC().Format(time.RFC3339))
+	}
+	requester, err := server.store.GetPrincipalByID(ctx, task.CreatorID)
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "failed to find the requester %d", task.CreatorID)
+	}
+	if requester == nil {
+		return true, nil, errors.Errorf("requester %d not found", task.CreatorID)
+	}
+
+	instance := task.Instance
+	database := task.Database
+	userName := getDataSourceRequestUserName(database.ID, requester.ID)
+	password, err := common.RandomString(databaseGrantPasswordLength)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "failed to generate password")
+	}
+	grantStatementList, err := getDatabaseGrantStatementList(instance.Engine, database.Name, userName, password, api.DatabaseGrantRead, payload.ExpireTs)
+	if err != nil {
+		return true, nil, err
+	}
+	revokeStatementList, err := getDatabaseRevokeStatementList(instance.Engine, userName)
+	if err != nil {
+		return true, nil, err
+	}
+
+	log.Debug("Start creating read-only data source...",
+		zap.String("instance", instance.Name),
+		zap.String("database", database.Name),
+		zap.String("requester", requester.Email),
+	)
+	// The user is recreated for the repeated requests, so that the old password stops working.
+	if err := server.executeDatabaseGrantStatementList(ctx, instance, database.Name, append(revokeStatementList, grantStatementList...)); err != nil {
+		// The error is recorded in the task run result and logs, which must not contain the password.
+		return true, nil, redactDatabaseGrantPassword(err, password)
+	}
+	if _, err := server.store.UpsertInstanceUser(ctx, &api.InstanceUserUpsert{
+		CreatorID:  task.CreatorID,
+		InstanceID: instance.ID,
+		Name:       getDatabaseGrantInstanceUserName(instance.Engine, userName),
+		Grant:      strings.Join(grantStatementList[1:], "\n"),
+		ExpireTs:   &payload.ExpireTs,
+	}); err != nil {
+		return true, nil, errors.Wrapf(err, "failed to record the read-only user %q", userName)
+	}
+
+	dataSource, err := server.store.GetDataSource(ctx, &api.DataSourceFind{
+		DatabaseID:  &database.ID,
+		PrincipalID: &requester.ID,
+	})
+	if err != nil {
+		return true, nil, errors.Wrap(err, "failed to find the requested data source")
+	}
+	if dataSource == nil {
+		dataSourceCreate := &api.DataSourceCreate{
+			CreatorID:    task.CreatorID,
+			InstanceID:   instance.ID,
+			DatabaseID:   database.ID,
+			Name:         fmt.Sprintf("%s of %s", api.ReadOnlyDataSourceName, requester.Email),
+			Type:         api.RO,
+			Username:     userName,
+			Password:     password,
+			HostOverride: payload.HostOverride,
+			PortOverride: payload.PortOverride,
+			PrincipalID:  &requester.ID,
+		}
+		// The data source connects to the same instance, so it uses the same TLS config as the admin data source.
+		if adminDataSource := api.DataSourceFromInstanceWithType(instance, api.Admin); adminDataSource != nil {
+			dataSourceCreate.SslCa = adminDataSource.SslCa
+			dataSourceCreate.SslCert = adminDataSource.SslCert
+			dataSourceCreate.SslKey = adminDataSource.SslKey
+		}
+		if dataSource, err = server.store.CreateDataSource(ctx, dataSourceCreate); err != nil {
+			return true, nil, errors.Wrap(err, "failed to create the requested data source")
+		}
+	} else {
+		if dataSource, err = server.store.PatchDataSource(ctx, &api.DataSourcePatch{
+			ID:           dataSource.ID,
+			UpdaterID:    task.CreatorID,
+			Username:     &userName,
+			Password:     &password,
+			HostOverride: &payload.HostOverride,
+			PortOverride: &payload.PortOverride,
+		}); err != nil {
+			return true, nil, errors.Wrap(err, "failed to update the requested data source")
+		}
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Created %q of database %q until %s, the SQL editor queries of %s on the database go through it", dataSource.Name, database.Name, time.Unix(payload.ExpireTs, 0).UTC().Format(time.RFC3339), requester.Email),
+	}, nil
+}
+
+// getDataSourceRequestUserName returns the read-only user name of the requested data source.
+func getDataSourceRequestUserName(databaseID int, principalID int) string {
+	return fmt.Sprintf("%s%d_%d", dataSourceRequestUserPrefix, databaseID, principalID)
+}
+
+// getDataSourceRequestID returns the database ID and the requester ID from the instance user name, or false if the user isn't created by the data source request task.
+func getDataSourceRequestID(instanceUserName string) (int, int, bool) {
+	name := getDatabaseUserName(instanceUserName)
+	if !strings.HasPrefix(name, dataSourceRequestUserPrefix) {
+		return 0, 0, false
+	}
+	idList := strings.Split(strings.TrimPrefix(name, dataSourceRequestUserPrefix), "_")
+	if len(idList) != 2 {
+		return 0, 0, false
+	}
+	databaseID, err := strconv.Atoi(idList[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	principalID, err := strconv.Atoi(idList[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return databaseID, principalID, true
+}
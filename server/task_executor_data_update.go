@@ -18,16 +18,19 @@ func NewDataUpdateTaskExecutor() TaskExecutor {
 
 // DataUpdateTaskExecutor is the data update (DML) task executor.
 type DataUpdateTaskExecutor struct {
-	completed int32
+	completed       int32
+	progressTracker db.ExecuteProgressTracker
 }
 
 // RunOnce will run the data update (DML) task executor once.
-func (*DataUpdateTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+func (exec *DataUpdateTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
 	payload := &api.TaskDatabaseDataUpdatePayload{}
 	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
 		return true, nil, errors.Wrap(err, "invalid database data update payload")
 	}
 
+	ctx = db.WithExecuteProgressTracker(ctx, &exec.progressTracker)
 	return runMigration(ctx, server, task, db.Data, payload.Statement, payload.SchemaVersion, payload.VCSPushEvent)
 }
 
@@ -37,6 +40,6 @@ func (exec *DataUpdateTaskExecutor) IsCompleted() bool {
 }
 
 // GetProgress returns the task progress.
-func (*DataUpdateTaskExecutor) GetProgress() api.Progress {
-	return api.Progress{}
+func (exec *DataUpdateTaskExecutor) GetProgress() api.Progress {
+	return getExecuteProgress(&exec.progressTracker)
 }
@@ -73,9 +73,15 @@ func (exec *DatabaseBackupTaskExecutor) RunOnce(ctx context.Context, server *Ser
 	backupPayload, backupErr := exec.backupDatabase(ctx, server, task.Instance, task.Database.Name, backup)
 	backupStatus := string(api.BackupStatusDone)
 	comment := ""
+	// The backup status must leave PENDING_CREATE even if the task is canceled, so we use a new context to update it.
+	patchCtx := ctx
 	if backupErr != nil {
 		backupStatus = string(api.BackupStatusFailed)
 		comment = backupErr.Error()
+		if reason := db.CancelReason(ctx); reason != "" {
+			comment = fmt.Sprintf("backup is canceled: %s", reason)
+			patchCtx = context.Background()
+		}
 		if err := removeLocalBackupFile(server.profile.DataDir, backup); err != nil {
 			log.Warn(err.Error())
 		}
@@ -88,12 +94,12 @@ func (exec *DatabaseBackupTaskExecutor) RunOnce(ctx context.Context, server *Ser
 		Payload:   &backupPayload,
 	}
 
-	if _, err := server.store.PatchBackup(ctx, &backupPatch); err != nil {
+	if _, err := server.store.PatchBackup(patchCtx, &backupPatch); err != nil {
 		return true, nil, errors.Wrap(err, "failed to patch backup")
 	}
 
 	if backupErr != nil {
-		return true, nil, backupErr
+		return true, nil, errors.New(comment)
 	}
 
 	return true, &api.TaskRunResultPayload{
@@ -148,17 +154,23 @@ func (*DatabaseBackupTaskExecutor) backupDatabase(ctx context.Context, server *S
 	}
 	defer driver.Close(ctx)
 
+	logger := db.GetExecuteLogger(ctx)
 	backupFilePathLocal := filepath.Join(server.profile.DataDir, backup.Path)
+	logger.Logf(db.ExecuteLogLevelInfo, "Dumping database %q to backup %q", databaseName, backup.Name)
 	payload, err := dumpBackupFile(ctx, driver, databaseName, backupFilePathLocal)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to dump backup file %q", backupFilePathLocal)
 	}
+	if info, err := os.Stat(backupFilePathLocal); err == nil {
+		logger.Logf(db.ExecuteLogLevelInfo, "Dumped database %q, backup file size %d bytes", databaseName, info.Size())
+	}
 
 	switch backup.StorageBackend {
 	case api.BackupStorageBackendLocal:
 		return payload, nil
 	case api.BackupStorageBackendS3:
 		log.Debug("Uploading backup to s3 bucket.", zap.String("bucket", server.s3Client.GetBucket()), zap.String("path", backupFilePathLocal))
+		logger.Logf(db.ExecuteLogLevelInfo, "Uploading backup to s3 bucket %q", server.s3Client.GetBucket())
 		bucketFileToUpload, err := os.Open(backupFilePathLocal)
 		if err != nil {
 			return "", errors.Wrapf(err, "failed to open backup file %q for uploading to s3 bucket", backupFilePathLocal)
@@ -169,9 +181,11 @@ func (*DatabaseBackupTaskExecutor) backupDatabase(ctx context.Context, server *S
 			return "", errors.Wrapf(err, "failed to upload backup to AWS S3")
 		}
 		log.Debug("Successfully uploaded backup to s3 bucket.")
+		logger.Logf(db.ExecuteLogLevelInfo, "Uploaded backup to s3 bucket %q", server.s3Client.GetBucket())
 
 		if err := os.Remove(backupFilePathLocal); err != nil {
 			log.Warn("Failed to remove the local backup file after uploading to s3 bucket.", zap.String("path", backupFilePathLocal), zap.Error(err))
+			logger.Logf(db.ExecuteLogLevelWarn, "Failed to remove the local backup file after uploading to s3 bucket: %v", err)
 		} else {
 			log.Debug("Successfully removed the local backup file after uploading to s3 bucket.", zap.String("path", backupFilePathLocal))
 		}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// databaseGrantUserPrefix is the prefix of the temporary database users created by the database grant task.
+	// The user name is suffixed with the task ID, so that the revoker can find the database of the grant.
+	databaseGrantUserPrefix     = "bb_grant_"
+	databaseGrantPasswordLength = 20
+)
+
+// NewDatabaseGrantTaskExecutor creates a database grant task executor.
+func NewDatabaseGrantTaskExecutor() TaskExecutor {
+	return &DatabaseGrantTaskExecutor{}
+}
+
+// DatabaseGrantTaskExecutor is the database grant task executor.
+type DatabaseGrantTaskExecutor struct {
+	completed int32
+}
+
+// IsCompleted tells the scheduler if the task execution has completed.
+func (exec *DatabaseGrantTaskExecutor) IsCompleted() bool {
+	return atomic.LoadInt32(&exec.completed) == 1
+}
+
+// GetProgress returns the task progress.
+func (*DatabaseGrantTaskExecutor) GetProgress() api.Progress {
+	return api.Progress{}
+}
+
+// RunOnce will run the database grant task executor once.
+func (exec *DatabaseGrantTaskExecutor) RunOnce(ctx context.Context, server *Server, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	defer atomic.StoreInt32(&exec.completed, 1)
+	payload := &api.TaskDatabaseGrantPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, errors.Wrap(err, "invalid database grant payload")
+	}
+	if task.Database == nil {
+		return true, nil, errors.Errorf("missing database for database grant task %d", task.ID)
+	}
+	if payload.ExpireTs <= time.Now().Unix() {
+		return true, nil, errors.Errorf("the database grant has already expired at %s", time.Unix(payload.ExpireTs, 0).UTC().Format(time.RFC3339))
+	}
+
+	instance := task.Instance
+	databaseName := task.Database.Name
+	userName := getDatabaseGrantUserName(task.ID)
+	password, err := common.RandomString(databaseGrantPasswordLength)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "failed to generate password")
+	}
+	grantStatementList, err := getDatabaseGrantStatementList(instance.Engine, databaseName, userName, password, payload.Access, payload.ExpireTs)
+	if err != nil {
+		return true, nil, err
+	}
+	revokeStatementList, err := getDatabaseRevokeStatementList(instance.Engine, userName)
+	if err != nil {
+		return true, nil, err
+	}
+
+	log.Debug("Start granting database...",
+		zap.String("instance", instance.Name),
+		zap.String("database", databaseName),
+		zap.String("user", userName),
+		zap.String("access", string(payload.Access)),
+	)
+	// Revoke the user created by the previous run first, so that the task can be retried.
+	if err := server.executeDatabaseGrantStatementList(ctx, instance, databaseName, append(revokeStatementList, grantStatementList...)); err != nil {
+		// The error is recorded in the task run result and logs, which must not contain the password.
+		return true, nil, redactDatabaseGrantPassword(err, password)
+	}
+
+	expireTs := payload.ExpireTs
+	if _, err := server.store.UpsertInstanceUser(ctx, &api.InstanceUserUpsert{
+		CreatorID:  task.CreatorID,
+		InstanceID: instance.ID,
+		Name:       getDatabaseGrantInstanceUserName(instance.Engine, userName),
+		Grant:      strings.Join(grantStatementList[1:], "\n"),
+		ExpireTs:   &expireTs,
+		Password:   &password,
+	}); err != nil {
+		return true, nil, errors.Wrapf(err, "failed to record the granted user %q", userName)
+	}
+
+	// The password is only revealed to the requester once, so it's not in the task run result visible to anyone viewing the issue.
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Granted %s access to database %q until %s. Username: %s. The requester can reveal the password once.",
+			payload.Access, databaseName, time.Unix(payload.ExpireTs, 0).UTC().Format(time.RFC3339), userName),
+	}, nil
+}
+
+// redactDatabaseGrantPassword removes the password from the error, e.g. the failed CREATE USER statement.
+func redactDatabaseGrantPassword(err error, password string) error {
+	return errors.New(strings.ReplaceAll(err.Error(), password, "******"))
+}
+
+// executeDatabaseGrantStatementList executes the statements one by one with the admin connection of the database.
+// We don't use the driver Execute because Postgres executes the statements as the database owner, who can't create roles in general.
+func (s *Server) executeDatabaseGrantStatementList(ctx context.Context, instance *api.Instance, databaseName string, statementList []string) error {
+	driver, err := s.getAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	sqlDB, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statementList {
+		if _, err := sqlDB.ExecContext(ctx, statement); err != nil {
+			return errors.Wrapf(err, "failed to execute statement %q", statement)
+		}
+	}
+	return nil
+}
+
+func getDatabaseGrantUserName(taskID int) string {
+	return fmt.Sprintf("%s%d", databaseGrantUserPrefix, taskID)
+}
+
+// getDatabaseGrantTaskID returns the database grant task ID from the instance user name, or false if the user isn't created by the database grant task.
+func getDatabaseGrantTaskID(instanceUserName string) (int, bool) {
+	name := getDatabaseUserName(instanceUserName)
+	if !strings.HasPrefix(name, databaseGrantUserPrefix) {
+		return 0, false
+	}
+	taskID, err := strconv.Atoi(strings.TrimPrefix(name, databaseGrantUserPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return taskID, true
+}
+
+// getDatabaseUserName returns the database user name from the instance user name.
+func getDatabaseUserName(instanceUserName string) string {
+	// The MySQL instance user name is in the format of 'user'@'host'.
+	name := instanceUserName
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.Trim(name, "'")
+}
+
+// getDatabaseGrantInstanceUserName returns the user name in the same format as the instance user synced from the instance.
+func getDatabaseGrantInstanceUserName(engine db.Type, userName string) string {
+	switch engine {
+	case db.MySQL, db.TiDB:
+		return fmt.Sprintf("'%s'@'%%'", userName)
+	default:
+		return userName
+	}
+}
+
+// getDatabaseGrantStatementList returns the statements creating the user with the access to the database.
+// The first statement creates the user, and the rest grant the privileges. The user never expires if expireTs is 0.
+func getDatabaseGrantStatementList(engine db.Type, databaseName, userName, password string, access api.DatabaseGrantAccess, expireTs int64) ([]string, error) {
+	var privileges string
+	switch access {
+	case api.DatabaseGrantRead:
+		privileges = "SELECT"
+	case api.DatabaseGrantWrite:
+		privileges = "SELECT, INSERT, UPDATE, DELETE"
+	default:
+		return nil, errors.Errorf("invalid database grant access %q", access)
+	}
+
+	switch engine {
+	case db.MySQL, db.TiDB:
+		return []string{
+			fmt.Sprintf("CREATE USER '%s'@'%%' IDENTIFIED BY '%s'", userName, password),
+			fmt.Sprintf("GRANT %s ON `%s`.* TO '%s'@'%%'", privileges, strings.ReplaceAll(databaseName, "`", "``"), userName),
+		}, nil
+	case db.Postgres:
+		createRole := fmt.Sprintf(`CREATE ROLE "%s" WITH LOGIN PASSWORD '%s'`, userName, password)
+		// The role expires by itself even if the revoker fails to drop it in time.
+		if expireTs > 0 {
+			createRole += fmt.Sprintf(" VALID UNTIL '%s'", time.Unix(expireTs, 0).UTC().Format(time.RFC3339))
+		}
+		return []string{
+			createRole,
+			fmt.Sprintf(`GRANT CONNECT ON DATABASE "%s" TO "%s"`, strings.ReplaceAll(databaseName, `"`, `""`), userName),
+			// Postgres has no database level table privileges, so we grant the privileges on the tables in each user schema.
+			fmt.Sprintf(`DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN SELECT nspname FROM pg_namespace WHERE nspname NOT LIKE 'pg\_%%' AND nspname <> 'information_schema' LOOP
+        EXECUTE format('GRANT USAGE ON SCHEMA %%I TO "%s"', schema_name);
+        EXECUTE format('GRANT %s ON ALL TABLES IN SCHEMA %%I TO "%s"', schema_name);
+    END LOOP;
+END $$`, userName, privileges, userName),
+		}, nil
+	default:
+		return nil, errors.Errorf("database grant is not supported for engine %s", engine)
+	}
+}
+
+// getDatabaseRevokeStatementList returns the statements dropping the user created by getDatabaseGrantStatementList if it exists.
+func getDatabaseRevokeStatementList(engine db.Type, userName string) ([]string, error) {
+	switch engine {
+	case db.MySQL, db.TiDB:
+		return []string{
+			fmt.Sprintf("DROP USER IF EXISTS '%s'@'%%'", userName),
+		}, nil
+	case db.Postgres:
+		// The privileges must be dropped before dropping the role, and DROP OWNED only applies to the current database.
+		return []string{
+			fmt.Sprintf(`DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '%s') THEN
+        DROP OWNED BY "%s";
+        DROP ROLE "%s";
+    END IF;
+END $$`, userName, userName, userName),
+		}, nil
+	default:
+		return nil, errors.Errorf("database grant is not supported for engine %s", engine)
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetDatabaseGrantTaskID(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantID int
+		wantOK bool
+	}{
+		{name: getDatabaseGrantInstanceUserName(db.MySQL, getDatabaseGrantUserName(101)), wantID: 101, wantOK: true},
+		{name: getDatabaseGrantInstanceUserName(db.Postgres, getDatabaseGrantUserName(102)), wantID: 102, wantOK: true},
+		{name: "'root'@'%'", wantOK: false},
+		{name: "bb_grant_abc", wantOK: false},
+		{name: "postgres", wantOK: false},
+	}
+
+	for _, test := range tests {
+		id, ok := getDatabaseGrantTaskID(test.name)
+		require.Equal(t, test.wantOK, ok, test.name)
+		require.Equal(t, test.wantID, id, test.name)
+	}
+}
+
+func TestGetDataSourceRequestID(t *testing.T) {
+	tests := []struct {
+		name            string
+		wantDatabaseID  int
+		wantPrincipalID int
+		wantOK          bool
+	}{
+		{name: getDatabaseGrantInstanceUserName(db.MySQL, getDataSourceRequestUserName(101, 201)), wantDatabaseID: 101, wantPrincipalID: 201, wantOK: true},
+		{name: getDatabaseGrantInstanceUserName(db.Postgres, getDataSourceRequestUserName(102, 202)), wantDatabaseID: 102, wantPrincipalID: 202, wantOK: true},
+		{name: getDatabaseGrantInstanceUserName(db.MySQL, getDatabaseGrantUserName(101)), wantOK: false},
+		{name: "bb_ro_101", wantOK: false},
+		{name: "bb_ro_101_abc", wantOK: false},
+		{name: "postgres", wantOK: false},
+	}
+
+	for _, test := range tests {
+		databaseID, principalID, ok := getDataSourceRequestID(test.name)
+		require.Equal(t, test.wantOK, ok, test.name)
+		require.Equal(t, test.wantDatabaseID, databaseID, test.name)
+		require.Equal(t, test.wantPrincipalID, principalID, test.name)
+	}
+}
+
+func TestGetDatabaseGrantStatementList(t *testing.T) {
+	statementList, err := getDatabaseGrantStatementList(db.MySQL, "db`1", "bb_grant_101", "secret", api.DatabaseGrantRead, 1666915200)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CREATE USER 'bb_grant_101'@'%' IDENTIFIED BY 'secret'",
+		"GRANT SELECT ON `db``1`.* TO 'bb_grant_101'@'%'",
+	}, statementList)
+
+	statementList, err = getDatabaseGrantStatementList(db.Postgres, "db", "bb_grant_101", "secret", api.DatabaseGrantWrite, 1666915200)
+	require.NoError(t, err)
+	require.Len(t, statementList, 3)
+	require.Equal(t, `CREATE ROLE "bb_grant_101" WITH LOGIN PASSWORD 'secret' VALID UNTIL '2022-10-28T00:00:00Z'`, statementList[0])
+	require.Equal(t, `GRANT CONNECT ON DATABASE "db" TO "bb_grant_101"`, statementList[1])
+	require.Contains(t, statementList[2], `EXECUTE format('GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA %I TO "bb_grant_101"', schema_name);`)
+
+	// The role never expires without the expiration time.
+	statementList, err = getDatabaseGrantStatementList(db.Postgres, "db", "bb_ro_101_1", "secret", api.DatabaseGrantRead, 0)
+	require.NoError(t, err)
+	require.Equal(t, `CREATE ROLE "bb_ro_101_1" WITH LOGIN PASSWORD 'secret'`, statementList[0])
+
+	_, err = getDatabaseGrantStatementList(db.MySQL, "db", "bb_grant_101", "secret", "ADMIN", 1666915200)
+	require.Error(t, err)
+	_, err = getDatabaseGrantStatementList(db.ClickHouse, "db", "bb_grant_101", "secret", api.DatabaseGrantRead, 1666915200)
+	require.Error(t, err)
+}
+
+func TestGetDatabaseRevokeStatementList(t *testing.T) {
+	statementList, err := getDatabaseRevokeStatementList(db.TiDB, "bb_grant_101")
+	require.NoError(t, err)
+	require.Equal(t, []string{"DROP USER IF EXISTS 'bb_grant_101'@'%'"}, statementList)
+
+	statementList, err = getDatabaseRevokeStatementList(db.Postgres, "bb_grant_101")
+	require.NoError(t, err)
+	require.Len(t, statementList, 1)
+	require.Contains(t, statementList[0], `DROP OWNED BY "bb_grant_101";`)
+	require.Contains(t, statementList[0], `DROP ROLE "bb_grant_101";`)
+}
+
+func TestRedactDatabaseGrantPassword(t *testing.T) {
+	statementList, err := getDatabaseGrantStatementList(db.MySQL, "db", "bb_grant_101", "s3cr3tPassw0rd", api.DatabaseGrantRead, 0)
+	require.NoError(t, err)
+	err = errors.Wrapf(errors.New("Error 1396: Operation CREATE USER failed"), "failed to execute statement %q", statementList[0])
+	redacted := redactDatabaseGrantPassword(err, "s3cr3tPassw0rd")
+	require.NotContains(t, redacted.Error(), "s3cr3tPassw0rd")
+	require.Contains(t, redacted.Error(), "IDENTIFIED BY '******'")
+}
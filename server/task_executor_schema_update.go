@@ -18,7 +18,8 @@ func NewSchemaUpdateTaskExecutor() TaskExecutor {
 
 // SchemaUpdateTaskExecutor is the schema update (DDL) task executor.
 type SchemaUpdateTaskExecutor struct {
-	completed int32
+	completed       int32
+	progressTracker db.ExecuteProgressTracker
 }
 
 // RunOnce will run the schema update (DDL) task executor once.
@@ -29,6 +30,7 @@ func (exec *SchemaUpdateTaskExecutor) RunOnce(ctx context.Context, server *Serve
 		return true, nil, errors.Wrap(err, "invalid database schema update payload")
 	}
 
+	ctx = db.WithExecuteProgressTracker(ctx, &exec.progressTracker)
 	return runMigration(ctx, server, task, db.Migrate, payload.Statement, payload.SchemaVersion, payload.VCSPushEvent)
 }
 
@@ -38,6 +40,6 @@ func (exec *SchemaUpdateTaskExecutor) IsCompleted() bool {
 }
 
 // GetProgress returns the task progress.
-func (*SchemaUpdateTaskExecutor) GetProgress() api.Progress {
-	return api.Progress{}
+func (exec *SchemaUpdateTaskExecutor) GetProgress() api.Progress {
+	return getExecuteProgress(&exec.progressTracker)
 }
@@ -11,11 +11,9 @@ import (
 
 	"github.com/github/gh-ost/go/base"
 	"github.com/pkg/errors"
-	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
-	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
@@ -113,12 +111,7 @@ func cutover(ctx context.Context, server *Server, task *api.Task, statement, sch
 		startedNs := time.Now().UnixNano()
 
 		defer func() {
-			if err := util.EndMigration(ctx, executor, startedNs, insertedID, updatedSchema, db.BytebaseDatabase, resErr == nil /*isDone*/); err != nil {
-				log.Error("failed to update migration history record",
-					zap.Error(err),
-					zap.Int64("migration_id", migrationHistoryID),
-				)
-			}
+			resErr = util.FinishMigration(ctx, executor, mi, startedNs, insertedID, updatedSchema, db.BytebaseDatabase, resErr)
 		}()
 
 		if err := os.Remove(postponeFilename); err != nil {
@@ -18,8 +18,12 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
+// ghostStatusLogIntervalSeconds is the interval of recording the gh-ost status in the task run logs.
+const ghostStatusLogIntervalSeconds = 30
+
 // NewSchemaUpdateGhostSyncTaskExecutor creates a schema update (gh-ost) sync task executor.
 func NewSchemaUpdateGhostSyncTaskExecutor() TaskExecutor {
 	return &SchemaUpdateGhostSyncTaskExecutor{}
@@ -240,6 +244,8 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(ctx context.Con
 	}
 
 	migrator := logic.NewMigrator(migrationContext, "bb")
+	logger := db.GetExecuteLogger(ctx)
+	logger.Logf(db.ExecuteLogLevelInfo, "Starting gh-ost migration on table %q", tableName)
 
 	childCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -247,6 +253,7 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(ctx context.Con
 		ticker := time.NewTicker(1 * time.Second)
 		defer ticker.Stop()
 		createdTs := time.Now().Unix()
+		var statusLoggedTs int64
 		for {
 			select {
 			case <-ticker.C:
@@ -261,8 +268,14 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(ctx context.Con
 					CreatedTs:     createdTs,
 					UpdatedTs:     updatedTs,
 				})
+				if updatedTs-statusLoggedTs >= ghostStatusLogIntervalSeconds {
+					statusLoggedTs = updatedTs
+					logger.Logf(db.ExecuteLogLevelInfo, "gh-ost copied %d of %d rows, applied %d events, lag %s",
+						completedUnit, totalUnit, atomic.LoadInt64(&migrationContext.TotalDMLEventsApplied), migrationContext.GetCurrentLagDuration())
+				}
 				// Since we are using postpone flag file to postpone cutover, it's gh-ost mechanism to set migrationContext.IsPostponingCutOver to 1 after synced and before postpone flag file is removed. We utilize this mechanism here to check if synced.
 				if atomic.LoadInt64(&migrationContext.IsPostponingCutOver) > 0 {
+					logger.Logf(db.ExecuteLogLevelInfo, "gh-ost synced the ghost table, waiting for cutover")
 					close(syncDone)
 					return
 				}
@@ -275,6 +288,7 @@ func (exec *SchemaUpdateGhostSyncTaskExecutor) runGhostMigration(ctx context.Con
 	go func() {
 		if err := migrator.Migrate(); err != nil {
 			log.Error("failed to run gh-ost migration", zap.Error(err))
+			logger.Logf(db.ExecuteLogLevelError, "gh-ost migration failed: %v", err)
 			migrationError <- err
 			return
 		}
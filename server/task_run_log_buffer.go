@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// The interval bounds how long a live log of the running task run is delayed.
+	taskRunLogFlushInterval = time.Duration(1) * time.Second
+	// taskRunLogFlushSize is the number of the buffered logs that triggers a flush before the interval.
+	taskRunLogFlushSize = 100
+)
+
+// taskRunLogBuffer buffers the live logs of a task run, and creates them in batches in the background,
+// so that recording the logs doesn't slow down executing the statements.
+// All the methods are no-op on the nil buffer.
+type taskRunLogBuffer struct {
+	taskRunID int
+	create    func(ctx context.Context, createList []*api.TaskRunLogCreate) error
+
+	mu         sync.Mutex
+	createList []*api.TaskRunLogCreate
+	// flushMu serializes the flushes, so that the logs are created in order.
+	flushMu sync.Mutex
+
+	flushC    chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// newTaskRunLogBuffer creates a task run log buffer creating the logs with create, and starts flushing it in the background.
+func newTaskRunLogBuffer(taskRunID int, create func(ctx context.Context, createList []*api.TaskRunLogCreate) error) *taskRunLogBuffer {
+	b := &taskRunLogBuffer{
+		taskRunID: taskRunID,
+		create:    create,
+		flushC:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// logger returns the execute logger writing to the buffer.
+func (b *taskRunLogBuffer) logger() *db.ExecuteLogger {
+	if b == nil {
+		return nil
+	}
+	return db.NewExecuteLogger(b.write)
+}
+
+func (b *taskRunLogBuffer) write(level db.ExecuteLogLevel, message string) {
+	b.mu.Lock()
+	b.createList = append(b.createList, &api.TaskRunLogCreate{
+		CreatedTs: time.Now().Unix(),
+		TaskRunID: b.taskRunID,
+		Level:     api.TaskRunLogLevel(level),
+		Message:   message,
+	})
+	full := len(b.createList) >= taskRunLogFlushSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushC <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *taskRunLogBuffer) run() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(taskRunLogFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.flushC:
+			b.flush()
+		case <-b.done:
+			b.flush()
+			return
+		}
+	}
+}
+
+// flush creates the buffered logs.
+// It's called before updating the task status, so that the logs are complete when the task run finishes.
+func (b *taskRunLogBuffer) flush() {
+	if b == nil {
+		return
+	}
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	createList := b.createList
+	b.createList = nil
+	b.mu.Unlock()
+
+	if len(createList) == 0 {
+		return
+	}
+	// Use a background context so that the logs are still persisted after the task is canceled.
+	if err := b.create(context.Background(), createList); err != nil {
+		log.Error("Failed to create task run logs",
+			zap.Int("task_run_id", b.taskRunID),
+			zap.Int("count", len(createList)),
+			zap.Error(err),
+		)
+	}
+}
+
+// close stops the background flushing and flushes the remaining logs.
+// The logs written after closing are dropped.
+func (b *taskRunLogBuffer) close() {
+	if b == nil {
+		return
+	}
+	b.closeOnce.Do(func() {
+		close(b.done)
+		<-b.stopped
+	})
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestTaskRunLogBuffer(t *testing.T) {
+	// The nil buffer is no-op.
+	var nilBuffer *taskRunLogBuffer
+	nilBuffer.logger().Logf(db.ExecuteLogLevelInfo, "Started task %q", "t1")
+	nilBuffer.flush()
+	nilBuffer.close()
+
+	var mu sync.Mutex
+	var batchList [][]*api.TaskRunLogCreate
+	buffer := newTaskRunLogBuffer(101, func(_ context.Context, createList []*api.TaskRunLogCreate) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batchList = append(batchList, createList)
+		return nil
+	})
+	getMessageList := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		var messageList []string
+		for _, batch := range batchList {
+			for _, create := range batch {
+				require.Equal(t, 101, create.TaskRunID)
+				messageList = append(messageList, create.Message)
+			}
+		}
+		return messageList
+	}
+
+	// The logs are buffered until the flush.
+	logger := buffer.logger()
+	logger.Logf(db.ExecuteLogLevelInfo, "Started task %q", "t1")
+	logger.Logf(db.ExecuteLogLevelWarn, "Warning %d", 1)
+	buffer.flush()
+	require.Equal(t, []string{`Started task "t1"`, "Warning 1"}, getMessageList())
+	mu.Lock()
+	lastBatch := batchList[len(batchList)-1]
+	require.Equal(t, api.TaskRunLogWarn, lastBatch[len(lastBatch)-1].Level)
+	mu.Unlock()
+
+	// A full buffer is flushed in the background before the interval.
+	var wantMessageList []string
+	for i := 0; i < taskRunLogFlushSize; i++ {
+		message := fmt.Sprintf("Executing statement at line %d", i)
+		logger.Logf(db.ExecuteLogLevelInfo, "%s", message)
+		wantMessageList = append(wantMessageList, message)
+	}
+	require.Eventually(t, func() bool {
+		return len(getMessageList()) == 2+taskRunLogFlushSize
+	}, taskRunLogFlushInterval/2, 10*time.Millisecond)
+
+	// The remaining logs are flushed on close, and the logs are created in order.
+	logger.Logf(db.ExecuteLogLevelInfo, "Completed task")
+	buffer.close()
+	buffer.close()
+	wantMessageList = append([]string{`Started task "t1"`, "Warning 1"}, append(wantMessageList, "Completed task")...)
+	require.Equal(t, wantMessageList, getMessageList())
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	taskRunLogCleanInterval = time.Duration(1) * time.Hour
+)
+
+// NewTaskRunLogCleaner creates a task run log cleaner.
+func NewTaskRunLogCleaner(server *Server) *TaskRunLogCleaner {
+	return &TaskRunLogCleaner{
+		server: server,
+	}
+}
+
+// TaskRunLogCleaner is the runner deleting the task run logs expired by the task run log retention policy.
+type TaskRunLogCleaner struct {
+	server *Server
+}
+
+// Run will run the task run log cleaner.
+func (s *TaskRunLogCleaner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(taskRunLogCleanInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Task run log cleaner started and will run every %v", taskRunLogCleanInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = errors.Errorf("%v", r)
+						}
+						log.Error("Task run log cleaner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+					}
+				}()
+				s.deleteExpiredLog(ctx)
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (s *TaskRunLogCleaner) deleteExpiredLog(ctx context.Context) {
+	envList, err := s.server.store.FindEnvironment(ctx, &api.EnvironmentFind{})
+	if err != nil {
+		log.Error("Failed to find the environments", zap.Error(err))
+		return
+	}
+	now := time.Now()
+	for _, env := range envList {
+		policy, err := s.server.store.GetTaskRunLogRetentionPolicyByEnvID(ctx, env.ID)
+		if err != nil {
+			log.Error("Failed to get the task run log retention policy", zap.Int("environment_id", env.ID), zap.Error(err))
+			continue
+		}
+		// Keep the logs forever.
+		if policy.RetentionDays == 0 {
+			continue
+		}
+		count, err := s.server.store.DeleteTaskRunLog(ctx, &api.TaskRunLogDelete{
+			EnvironmentID:   env.ID,
+			CreatedTsBefore: getTaskRunLogExpireTs(policy, now),
+		})
+		if err != nil {
+			// We will retry in the next round.
+			log.Error("Failed to delete the expired task run logs", zap.Int("environment_id", env.ID), zap.Error(err))
+			continue
+		}
+		if count > 0 {
+			log.Debug("Deleted the expired task run logs", zap.String("environment", env.Name), zap.Int64("count", count))
+		}
+	}
+}
+
+// getTaskRunLogExpireTs returns the timestamp before which the task run logs are expired by the policy.
+func getTaskRunLogExpireTs(policy *api.TaskRunLogRetentionPolicy, now time.Time) int64 {
+	return now.AddDate(0, 0, -policy.RetentionDays).Unix()
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
 
 	"go.uber.org/zap"
 )
@@ -28,7 +29,8 @@ func NewTaskScheduler(server *Server) *TaskScheduler {
 	return &TaskScheduler{
 		executorGetters:        make(map[api.TaskType]func() TaskExecutor),
 		runningExecutors:       make(map[int]TaskExecutor),
-		runningExecutorsCancel: make(map[int]context.CancelFunc),
+		runningExecutorsCancel: make(map[int]func(reason string)),
+		pipelineTurn:           newPipelineTurn(),
 		server:                 server,
 	}
 }
@@ -37,11 +39,17 @@ func NewTaskScheduler(server *Server) *TaskScheduler {
 type TaskScheduler struct {
 	executorGetters        map[api.TaskType]func() TaskExecutor
 	runningExecutors       map[int]TaskExecutor
-	runningExecutorsCancel map[int]context.CancelFunc
+	runningExecutorsCancel map[int]func(reason string)
 	runningExecutorsMutex  sync.Mutex
 	taskProgress           sync.Map // map[taskID]api.Progress
-	sharedTaskState        sync.Map // map[taskID]interface{}
-	server                 *Server
+	// maintenanceWindowAllowedTs is the next time allowed by the maintenance window policy of the tasks held by the policy.
+	maintenanceWindowAllowedTs sync.Map // map[taskID]int64
+	// taskQueuePosition is the position of the running tasks waiting in the queue for the task concurrency limits.
+	taskQueuePosition sync.Map // map[taskID]int
+	sharedTaskState   sync.Map // map[taskID]interface{}
+	// pipelineTurn keeps the pipelines taking turns to start the tasks across the ticks.
+	pipelineTurn *pipelineTurn
+	server       *Server
 }
 
 // Run will run the task scheduler.
@@ -122,6 +130,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					databaseRunningTasks[*task.DatabaseID] = task.ID
 				}
 
+				var executingTaskList, waitingTaskList []*api.Task
 				for _, task := range taskList {
 					// Skip task belongs to archived instances
 					if i := task.Instance; i == nil || i.RowStatus == api.Archived {
@@ -132,6 +141,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					_, ok := s.runningExecutors[task.ID]
 					s.runningExecutorsMutex.Unlock()
 					if ok {
+						executingTaskList = append(executingTaskList, task)
 						continue
 					}
 					// Skip the task that is not the earliest task of the database.
@@ -142,8 +152,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						}
 					}
 
-					executorGetter, ok := s.executorGetters[task.Type]
-					if !ok {
+					if _, ok := s.executorGetters[task.Type]; !ok {
 						log.Error("Skip running task with unknown type",
 							zap.Int("id", task.ID),
 							zap.String("name", task.Name),
@@ -151,8 +160,28 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						)
 						continue
 					}
+					waitingTaskList = append(waitingTaskList, task)
+				}
+
+				// Start the waiting tasks within the task concurrency limits, and queue the rest.
+				limit, err := s.getTaskConcurrencyLimit(ctx, taskList)
+				if err != nil {
+					log.Error("Failed to get the task concurrency limits", zap.Error(err))
+					return
+				}
+				startTaskList, queuePositionMap := scheduleTaskQueue(executingTaskList, waitingTaskList, limit, s.pipelineTurn)
+				s.taskQueuePosition.Range(func(key, _ interface{}) bool {
+					if _, ok := queuePositionMap[key.(int)]; !ok {
+						s.taskQueuePosition.Delete(key)
+					}
+					return true
+				})
+				for taskID, position := range queuePositionMap {
+					s.taskQueuePosition.Store(taskID, position)
+				}
 
-					executor := executorGetter()
+				for _, task := range startTaskList {
+					executor := s.executorGetters[task.Type]()
 					s.runningExecutorsMutex.Lock()
 					s.runningExecutors[task.ID] = executor
 					s.runningExecutorsMutex.Unlock()
@@ -166,20 +195,40 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							s.taskProgress.Delete(task.ID)
 						}()
 
-						executorCtx, cancel := context.WithCancel(ctx)
+						executorCtx, cancel := db.WithCancelReason(ctx)
 						s.runningExecutorsMutex.Lock()
 						s.runningExecutorsCancel[task.ID] = cancel
 						s.runningExecutorsMutex.Unlock()
 
+						// The task may be canceled after listed and before the cancel function is registered, so we check it again.
+						latestTask, err := s.server.store.GetTaskByID(ctx, task.ID)
+						if err != nil {
+							log.Error("Failed to get the task before running it", zap.Int("id", task.ID), zap.Error(err))
+							return
+						}
+						if latestTask == nil || latestTask.Status != api.TaskRunning {
+							return
+						}
+
+						// The executor and the drivers record the live logs of the task run with the logger carried by the context.
+						logBuffer := s.newTaskRunLogBuffer(task)
+						defer logBuffer.close()
+						logger := logBuffer.logger()
+						executorCtx = db.WithExecuteLogger(executorCtx, logger)
+						logger.Logf(db.ExecuteLogLevelInfo, "Started task %q", task.Name)
+
 						done, result, err := RunTaskExecutorOnce(executorCtx, executor, s.server, task)
 
 						select {
 						case <-executorCtx.Done():
 							// task cancelled
+							logger.Logf(db.ExecuteLogLevelWarn, "Canceled task: %s", db.CancelReason(executorCtx))
 							log.Debug("Task canceled",
 								zap.Int("id", task.ID),
 								zap.String("name", task.Name),
 								zap.String("type", string(task.Type)),
+								zap.String("reason", db.CancelReason(executorCtx)),
+								zap.Error(err),
 							)
 							return
 						default:
@@ -194,6 +243,12 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							)
 							return
 						}
+						if done && err == nil {
+							// The task fails if the post-deploy validation of the stage rollout doesn't pass.
+							if validateErr := s.server.validateTaskRollout(ctx, task); validateErr != nil {
+								err = validateErr
+							}
+						}
 						if done && err != nil {
 							log.Warn("Failed to run task",
 								zap.Int("id", task.ID),
@@ -201,9 +256,18 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								zap.String("type", string(task.Type)),
 								zap.Error(err),
 							)
-							bytes, marshalErr := json.Marshal(api.TaskRunResultPayload{
-								Detail: err.Error(),
-							})
+							resultPayload := api.TaskRunResultPayload{
+								Detail:   err.Error(),
+								Progress: getLastTaskProgress(executor),
+							}
+							logger.Logf(db.ExecuteLogLevelError, "Failed task: %v", err)
+							retryBackoff, retry := s.getTaskRetryBackoff(ctx, task, err)
+							if retry {
+								logger.Logf(db.ExecuteLogLevelInfo, "Retrying task in %s", retryBackoff)
+								resultPayload.Detail = fmt.Sprintf("%s. Retrying in %s.", resultPayload.Detail, retryBackoff)
+								resultPayload.RetryTs = time.Now().Add(retryBackoff).Unix()
+							}
+							bytes, marshalErr := json.Marshal(resultPayload)
 							if marshalErr != nil {
 								log.Error("Failed to marshal task run result",
 									zap.Int("task_id", task.ID),
@@ -221,6 +285,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								Code:      &code,
 								Result:    &result,
 							}
+							logBuffer.flush()
 							_, err = s.server.patchTaskStatus(ctx, task, taskStatusPatch)
 							if err != nil {
 								log.Error("Failed to mark task as FAILED",
@@ -228,10 +293,18 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 									zap.String("name", task.Name),
 									zap.Error(err),
 								)
+								return
+							}
+							if retry {
+								go s.retryTask(ctx, task.ID, resultPayload.RetryTs, resultPayload.RetryTs)
 							}
 							return
 						}
 						if done && err == nil {
+							if result.Progress == nil {
+								result.Progress = getLastTaskProgress(executor)
+							}
+							logger.Logf(db.ExecuteLogLevelInfo, "Completed task: %s", result.Detail)
 							bytes, err := json.Marshal(*result)
 							if err != nil {
 								log.Error("Failed to marshal task run result",
@@ -250,6 +323,7 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								Code:      &code,
 								Result:    &result,
 							}
+							logBuffer.flush()
 							taskPatched, err := s.server.patchTaskStatus(ctx, task, taskStatusPatch)
 							if err != nil {
 								log.Error("Failed to mark task as DONE",
@@ -305,6 +379,184 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// getLastTaskProgress returns the last progress of the executor to persist in the task run result, or nil if the executor doesn't report progress.
+func getLastTaskProgress(executor TaskExecutor) *api.Progress {
+	progress := executor.GetProgress()
+	if progress.TotalUnit == 0 {
+		return nil
+	}
+	return &progress
+}
+
+// getTaskRetryBackoff returns the backoff before retrying the task failed with the error, or false if the task shouldn't be retried.
+// The task is retried if the error is retryable and the task hasn't used up the max attempts in the task retry policy of its environment.
+func (s *TaskScheduler) getTaskRetryBackoff(ctx context.Context, task *api.Task, err error) (time.Duration, bool) {
+	// The statements have been applied if the rollout validation fails, and retrying the task would apply them again.
+	if common.ErrorCode(err) == common.TaskRolloutValidationFailed {
+		return 0, false
+	}
+	if !util.IsRetryableError(err) {
+		return 0, false
+	}
+	policy, err := s.server.store.GetTaskRetryPolicyByEnvID(ctx, task.Instance.EnvironmentID)
+	if err != nil {
+		log.Error("Failed to get the task retry policy",
+			zap.Int("id", task.ID),
+			zap.Int("environment_id", task.Instance.EnvironmentID),
+			zap.Error(err),
+		)
+		return 0, false
+	}
+	rule := policy.GetRule(task.Type)
+	attempt := getTaskAttempt(task.TaskRunList)
+	if attempt >= rule.MaxAttempts {
+		return 0, false
+	}
+	return rule.GetBackoff(attempt), true
+}
+
+// getTaskAttempt returns the attempt of the latest task run, starting from 1.
+// The task runs retried automatically are in the same attempt series, and the task runs started by other means start a new series.
+func getTaskAttempt(taskRunList []*api.TaskRun) int {
+	sortedTaskRunList := make([]*api.TaskRun, len(taskRunList))
+	copy(sortedTaskRunList, taskRunList)
+	sort.Slice(sortedTaskRunList, func(i, j int) bool {
+		return sortedTaskRunList[i].ID < sortedTaskRunList[j].ID
+	})
+	attempt := 1
+	// Skip the latest task run, and count the previous task runs scheduled to retry.
+	for i := len(sortedTaskRunList) - 2; i >= 0; i-- {
+		if getTaskRunRetryTs(sortedTaskRunList[i]) == 0 {
+			break
+		}
+		attempt++
+	}
+	return attempt
+}
+
+func getLatestTaskRun(taskRunList []*api.TaskRun) *api.TaskRun {
+	var latestTaskRun *api.TaskRun
+	for _, taskRun := range taskRunList {
+		if latestTaskRun == nil || taskRun.ID > latestTaskRun.ID {
+			latestTaskRun = taskRun
+		}
+	}
+	return latestTaskRun
+}
+
+// getRunningTaskRun returns the latest RUNNING task run, or nil if there is none.
+func getRunningTaskRun(taskRunList []*api.TaskRun) *api.TaskRun {
+	latestTaskRun := getLatestTaskRun(taskRunList)
+	if latestTaskRun == nil || latestTaskRun.Status != api.TaskRunRunning {
+		return nil
+	}
+	return latestTaskRun
+}
+
+// newTaskRunLogBuffer creates the buffer persisting the live logs of the running task run, or returns nil if the task is not running.
+func (s *TaskScheduler) newTaskRunLogBuffer(task *api.Task) *taskRunLogBuffer {
+	taskRun := getRunningTaskRun(task.TaskRunList)
+	if taskRun == nil {
+		return nil
+	}
+	return newTaskRunLogBuffer(taskRun.ID, s.server.store.CreateTaskRunLogList)
+}
+
+// getTaskRunRetryTs returns the time the failed task run is scheduled to retry, or 0 if it's not scheduled to retry.
+func getTaskRunRetryTs(taskRun *api.TaskRun) int64 {
+	if taskRun.Status != api.TaskRunFailed || taskRun.Result == "" {
+		return 0
+	}
+	var result api.TaskRunResultPayload
+	if err := json.Unmarshal([]byte(taskRun.Result), &result); err != nil {
+		return 0
+	}
+	return result.RetryTs
+}
+
+// retryTask runs the failed task scheduled to retry at retryTs again at startTs.
+// The retry is skipped if the task has been changed in the meantime, e.g. the task is retried or canceled manually.
+func (s *TaskScheduler) retryTask(ctx context.Context, taskID int, retryTs int64, startTs int64) {
+	timer := time.NewTimer(time.Until(time.Unix(startTs, 0)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		// The retry will be resumed by ResumeTaskRetry after restarting.
+		return
+	}
+
+	task, err := s.server.store.GetTaskByID(ctx, taskID)
+	if err != nil {
+		log.Error("Failed to get the task to retry", zap.Int("id", taskID), zap.Error(err))
+		return
+	}
+	if task == nil || task.Status != api.TaskFailed {
+		return
+	}
+	latestTaskRun := getLatestTaskRun(task.TaskRunList)
+	if latestTaskRun == nil || getTaskRunRetryTs(latestTaskRun) != retryTs {
+		return
+	}
+	// Hold the retry until the maintenance window opens.
+	allowedTs, err := s.getMaintenanceWindowAllowedTs(ctx, task)
+	if err != nil {
+		log.Error("Failed to check the maintenance window of the task to retry", zap.Int("id", taskID), zap.Error(err))
+		return
+	}
+	if allowedTs > time.Now().Unix() {
+		s.retryTask(ctx, taskID, retryTs, allowedTs)
+		return
+	}
+
+	if _, err := s.server.patchTaskStatus(ctx, task, &api.TaskStatusPatch{
+		IDList:    []int{task.ID},
+		UpdaterID: api.SystemBotID,
+		Status:    api.TaskRunning,
+	}); err != nil {
+		log.Error("Failed to retry task",
+			zap.Int("id", task.ID),
+			zap.String("name", task.Name),
+			zap.Error(err),
+		)
+		return
+	}
+	log.Debug("Retried task",
+		zap.Int("id", task.ID),
+		zap.String("name", task.Name),
+		zap.Int("attempt", getTaskAttempt(task.TaskRunList)+1),
+	)
+}
+
+// ResumeTaskRetry resumes the retries of the FAILED tasks scheduled to retry before the Bytebase server is restarted.
+func (s *TaskScheduler) ResumeTaskRetry(ctx context.Context) error {
+	taskFind := &api.TaskFind{StatusList: &[]api.TaskStatus{api.TaskFailed}}
+	failedTasks, err := s.server.store.FindTask(ctx, taskFind, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to get failed tasks")
+	}
+	for _, task := range failedTasks {
+		latestTaskRun := getLatestTaskRun(task.TaskRunList)
+		if latestTaskRun == nil {
+			continue
+		}
+		if retryTs := getTaskRunRetryTs(latestTaskRun); retryTs > 0 {
+			go s.retryTask(ctx, task.ID, retryTs, retryTs)
+		}
+	}
+	return nil
+}
+
+// cancelRunningExecutor cancels the executor running the task, if there is one.
+func (s *TaskScheduler) cancelRunningExecutor(taskID int, reason string) {
+	s.runningExecutorsMutex.Lock()
+	cancel, ok := s.runningExecutorsCancel[taskID]
+	s.runningExecutorsMutex.Unlock()
+	if ok {
+		cancel(reason)
+	}
+}
+
 // Register will register a task executor factory.
 func (s *TaskScheduler) Register(taskType api.TaskType, executorGetter func() TaskExecutor) {
 	if executorGetter == nil {
@@ -432,6 +684,10 @@ func (s *TaskScheduler) passAllCheck(ctx context.Context, task *api.Task, allowe
 
 // auto transit PendingApproval to Pending if all required task checks pass.
 func (s *TaskScheduler) canAutoApprove(ctx context.Context, task *api.Task) (bool, error) {
+	// The database grant and data source request are always approved manually regardless of the pipeline approval policy, because they hand out the database access.
+	if task.Type == api.TaskDatabaseGrant || task.Type == api.TaskDataSourceRequest {
+		return false, nil
+	}
 	return s.passAllCheck(ctx, task, api.TaskCheckStatusSuccess)
 }
 
@@ -448,13 +704,42 @@ func (s *TaskScheduler) canSchedule(ctx context.Context, task *api.Task) (bool,
 		return false, nil
 	}
 
+	allowedTs, err := s.getMaintenanceWindowAllowedTs(ctx, task)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check the maintenance window")
+	}
+	if allowedTs > time.Now().Unix() {
+		s.maintenanceWindowAllowedTs.Store(task.ID, allowedTs)
+		return false, nil
+	}
+	s.maintenanceWindowAllowedTs.Delete(task.ID)
+
 	return s.passAllCheck(ctx, task, api.TaskCheckStatusWarn)
 }
 
+// getMaintenanceWindowAllowedTs returns the next time allowed by the maintenance window policy of the task environment.
+// The task overriding the maintenance window is allowed at any time.
+func (s *TaskScheduler) getMaintenanceWindowAllowedTs(ctx context.Context, task *api.Task) (int64, error) {
+	now := time.Now()
+	if task.MaintenanceWindowOverride {
+		return now.Unix(), nil
+	}
+	policy, err := s.server.store.GetMaintenanceWindowPolicyByEnvID(ctx, task.Instance.EnvironmentID)
+	if err != nil {
+		return 0, err
+	}
+	allowedTime, err := policy.GetNextAllowedTime(now)
+	if err != nil {
+		return 0, err
+	}
+	return allowedTime.Unix(), nil
+}
+
 // ScheduleIfNeeded schedules the task if
 //  1. its required check does not contain error in the latest run.
 //  2. it has no blocking tasks.
 //  3. it has passed the earliest allowed time.
+//  4. it's in the maintenance window of the environment.
 func (s *TaskScheduler) ScheduleIfNeeded(ctx context.Context, task *api.Task) (*api.Task, error) {
 	schedule, err := s.canSchedule(ctx, task)
 	if err != nil {
@@ -495,10 +780,8 @@ func (s *TaskScheduler) isTaskBlocked(ctx context.Context, task *api.Task) (bool
 
 func getActiveStage(stageList []*api.Stage) *api.Stage {
 	for _, stage := range stageList {
-		for _, task := range stage.TaskList {
-			if task.Status != api.TaskDone {
-				return stage
-			}
+		if !isStageCompleted(stage) {
+			return stage
 		}
 	}
 	return nil
@@ -6,9 +6,12 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestAreAllTasksDone(t *testing.T) {
+	maxFailurePercent := 50
 	tests := []struct {
 		pipeline *api.Pipeline
 		want     bool
@@ -78,6 +81,62 @@ func TestAreAllTasksDone(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			// The failed task is tolerated by the rollout.
+			pipeline: &api.Pipeline{
+				StageList: []*api.Stage{
+					{
+						Rollout: api.DeploymentRollout{MaxFailurePercent: &maxFailurePercent},
+						TaskList: []*api.Task{
+							{
+								Status: api.TaskDone,
+							},
+							{
+								Status: api.TaskFailed,
+							},
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			// The failed tasks exceed the tolerated percentage.
+			pipeline: &api.Pipeline{
+				StageList: []*api.Stage{
+					{
+						Rollout: api.DeploymentRollout{MaxFailurePercent: &maxFailurePercent},
+						TaskList: []*api.Task{
+							{
+								Status: api.TaskFailed,
+							},
+							{
+								Status: api.TaskFailed,
+							},
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			// The failed task is not tolerated without the max failure percent.
+			pipeline: &api.Pipeline{
+				StageList: []*api.Stage{
+					{
+						TaskList: []*api.Task{
+							{
+								Status: api.TaskDone,
+							},
+							{
+								Status: api.TaskFailed,
+							},
+						},
+					},
+				},
+			},
+			want: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -85,3 +144,158 @@ func TestAreAllTasksDone(t *testing.T) {
 		assert.Equal(t, test.want, res)
 	}
 }
+
+func TestGetTaskAttempt(t *testing.T) {
+	retried := `{"detail":"deadlock","retryTs":1666000000}`
+	tests := []struct {
+		taskRunList []*api.TaskRun
+		want        int
+	}{
+		{
+			taskRunList: nil,
+			want:        1,
+		},
+		{
+			taskRunList: []*api.TaskRun{
+				{ID: 1, Status: api.TaskRunRunning},
+			},
+			want: 1,
+		},
+		{
+			taskRunList: []*api.TaskRun{
+				{ID: 3, Status: api.TaskRunRunning},
+				{ID: 1, Status: api.TaskRunFailed, Result: retried},
+				{ID: 2, Status: api.TaskRunFailed, Result: retried},
+			},
+			want: 3,
+		},
+		{
+			// The manual retry after the task run not scheduled to retry starts a new series.
+			taskRunList: []*api.TaskRun{
+				{ID: 1, Status: api.TaskRunFailed, Result: retried},
+				{ID: 2, Status: api.TaskRunFailed, Result: `{"detail":"syntax error"}`},
+				{ID: 3, Status: api.TaskRunFailed, Result: retried},
+				{ID: 4, Status: api.TaskRunRunning},
+			},
+			want: 2,
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, getTaskAttempt(test.taskRunList))
+	}
+}
+
+func TestGetRunningTaskRun(t *testing.T) {
+	tests := []struct {
+		taskRunList []*api.TaskRun
+		wantID      int
+	}{
+		{
+			taskRunList: nil,
+			wantID:      0,
+		},
+		{
+			taskRunList: []*api.TaskRun{
+				{ID: 2, Status: api.TaskRunRunning},
+				{ID: 1, Status: api.TaskRunFailed},
+			},
+			wantID: 2,
+		},
+		{
+			// The task run has finished.
+			taskRunList: []*api.TaskRun{
+				{ID: 1, Status: api.TaskRunFailed},
+				{ID: 2, Status: api.TaskRunDone},
+			},
+			wantID: 0,
+		},
+	}
+	for _, test := range tests {
+		taskRun := getRunningTaskRun(test.taskRunList)
+		if test.wantID == 0 {
+			assert.Nil(t, taskRun)
+			continue
+		}
+		assert.Equal(t, test.wantID, taskRun.ID)
+	}
+}
+
+func TestIsRiskTaskCheckExpected(t *testing.T) {
+	mysqlInstance := &api.Instance{Engine: db.MySQL}
+	tests := []struct {
+		task      *api.Task
+		checkType api.TaskCheckType
+		want      bool
+	}{
+		{
+			task:      &api.Task{Type: api.TaskDatabaseDataUpdate, Instance: mysqlInstance},
+			checkType: api.TaskCheckDatabaseStatementAdvise,
+			want:      true,
+		},
+		{
+			task:      &api.Task{Type: api.TaskDatabaseSchemaUpdate, Instance: mysqlInstance},
+			checkType: api.TaskCheckDatabaseStatementType,
+			want:      true,
+		},
+		{
+			// The statement type check doesn't support ClickHouse.
+			task:      &api.Task{Type: api.TaskDatabaseSchemaUpdate, Instance: &api.Instance{Engine: db.ClickHouse}},
+			checkType: api.TaskCheckDatabaseStatementType,
+			want:      false,
+		},
+		{
+			// The task without statements has no statement checks.
+			task:      &api.Task{Type: api.TaskDatabaseCreate, Instance: mysqlInstance},
+			checkType: api.TaskCheckDatabaseStatementAdvise,
+			want:      false,
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, isRiskTaskCheckExpected(test.task, test.checkType, common.ReleaseModeDev), test.task.Type)
+	}
+}
+
+func TestGetApproverGroupMap(t *testing.T) {
+	dba := []api.ApprovalGroupValue{api.ApprovalGroupValueWorkspaceDBA}
+	projectOwner := []api.ApprovalGroupValue{api.ApprovalGroupValueProjectOwner}
+	activityList := []*api.Activity{
+		{ID: 1, CreatorID: 101, Type: api.ActivityPipelineTaskApprovalCreate, Payload: `{"taskId":1,"approverGroupList":["WORKSPACE_DBA"]}`},
+		{ID: 2, CreatorID: 1, Type: api.ActivityPipelineTaskStatementUpdate, Payload: `{"taskId":1}`},
+		{ID: 3, CreatorID: 102, Type: api.ActivityPipelineTaskApprovalCreate, Payload: `{"taskId":1,"approverGroupList":["PROJECT_OWNER"]}`},
+		{ID: 4, CreatorID: 101, Type: api.ActivityPipelineTaskApprovalCreate, Payload: `{"taskId":2,"approverGroupList":["WORKSPACE_DBA"]}`},
+		{ID: 5, CreatorID: 1, Type: api.ActivityPipelineTaskStatusUpdate, Payload: `{"taskId":2,"oldStatus":"FAILED","newStatus":"PENDING_APPROVAL"}`},
+		{ID: 6, CreatorID: 103, Type: api.ActivityPipelineTaskApprovalCreate, Payload: `{"taskId":2,"approverGroupList":["PROJECT_OWNER"]}`},
+		{ID: 7, CreatorID: 101, Type: api.ActivityPipelineTaskApprovalCreate, Payload: `{"taskId":3,"approverGroupList":["WORKSPACE_DBA"]}`},
+	}
+	// The activities are ordered by ID regardless of the input order.
+	activityList[0], activityList[6] = activityList[6], activityList[0]
+
+	tests := []struct {
+		taskID int
+		want   map[int][]api.ApprovalGroupValue
+	}{
+		{
+			// The approval before the statement update is dismissed.
+			taskID: 1,
+			want:   map[int][]api.ApprovalGroupValue{102: projectOwner},
+		},
+		{
+			// The approval before going back to PENDING_APPROVAL is dismissed.
+			taskID: 2,
+			want:   map[int][]api.ApprovalGroupValue{103: projectOwner},
+		},
+		{
+			taskID: 3,
+			want:   map[int][]api.ApprovalGroupValue{101: dba},
+		},
+		{
+			taskID: 4,
+			want:   map[int][]api.ApprovalGroupValue{},
+		},
+	}
+	for _, test := range tests {
+		got, err := getApproverGroupMap(test.taskID, activityList)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, got, test.taskID)
+	}
+}
@@ -32,7 +32,7 @@ import (
 
 const (
 	// sqlReviewDocs is the URL for SQL review doc.
-	sqlReviewDocs = "https://www.bytebase.com/docs/reference/error-code/advisor"
+	sqlReviewDocs = advisor.ErrorCodeDocURL
 
 	// issueNameTemplate should be consistent with UI issue names generated from the frontend except for the timestamp.
 	// Because we cannot get the correct timezone of the client here.
@@ -39,11 +39,21 @@ func (c *catalogService) GetFinder() *catalog.Finder {
 
 type sqlCheckRequestBody struct {
 	Statement    string                      `json:"statement"`
+	FileList     []*sqlCheckFile             `json:"fileList"`
 	DatabaseType string                      `json:"databaseType"`
 	TemplateID   advisor.SQLReviewTemplateID `json:"templateId"`
 	Override     string                      `json:"override"`
 }
 
+// sqlCheckFile is the SQL file for batch check.
+type sqlCheckFile struct {
+	FilePath  string `json:"filePath"`
+	Statement string `json:"statement"`
+}
+
+// defaultSQLCheckFilePath is the file path used in SARIF and JUnit reports for the single statement request.
+const defaultSQLCheckFilePath = "statement.sql"
+
 func (s *Server) registerAdvisorRoutes(g *echo.Group) {
 	g.POST("/advise", s.sqlCheckController)
 }
@@ -51,10 +61,15 @@ func (s *Server) registerAdvisorRoutes(g *echo.Group) {
 // sqlCheckController godoc
 // @Summary  Check the SQL statement.
 // @Description  Parse and check the SQL statement according to the SQL review rules.
+// @Description  The response format is negotiated by the Accept header: application/json (default), application/sarif+json (SARIF 2.1.0) or application/xml (JUnit XML).
+// @Description  For the JSON format, the response is the advice list for the statement, or the advice list per file if the file list is specified.
 // @Accept  application/json
 // @Tags  SQL review
 // @Produce  json
-// @Param  statement     body  string  true   "The SQL statement."
+// @Produce  application/sarif+json
+// @Produce  application/xml
+// @Param  statement     body  string  false  "The SQL statement. Required if the file list is not specified."
+// @Param  fileList      body  array   false  "The SQL files for batch check, each with the filePath and statement. Required if the statement is not specified."
 // @Param  databaseType  body  string  true   "The database type."  Enums(MYSQL, POSTGRES, TIDB)
 // @Param  templateId    body  string  false  "The SQL check template id. Required if the config is not specified." Enums(bb.sql-review.prod, bb.sql-review.dev)
 // @Param  override      body  string  false  "The SQL check config override string in YAML format. Check https://github.com/bytebase/bytebase/tree/main/plugin/advisor/config/sql-review.override.yaml for example. Required if the template is not specified."
@@ -72,8 +87,16 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Cannot format request body").SetInternal(err)
 	}
 
-	if request.Statement == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Missing required SQL statement")
+	if request.Statement == "" && len(request.FileList) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required SQL statement or file list")
+	}
+	if request.Statement != "" && len(request.FileList) != 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "The SQL statement and file list cannot be specified at the same time")
+	}
+	for _, file := range request.FileList {
+		if file.FilePath == "" || file.Statement == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing required file path or SQL statement in the file list")
+		}
 	}
 
 	if request.Override == "" && request.TemplateID == "" {
@@ -102,16 +125,33 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot merge the config for template: %s", ruleOverride.Template)).SetInternal(err)
 	}
 
-	adviceList, err := sqlCheck(
-		advisorDBType,
-		"utf8mb4",
-		"utf8mb4_general_ci",
-		request.Statement,
-		ruleList,
-		newCatalogService(advisorDBType),
-	)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to run sql check").SetInternal(err)
+	fileList := request.FileList
+	if request.Statement != "" {
+		fileList = []*sqlCheckFile{
+			{
+				FilePath:  defaultSQLCheckFilePath,
+				Statement: request.Statement,
+			},
+		}
+	}
+
+	var fileAdviceList []*advisor.FileAdvice
+	for _, file := range fileList {
+		adviceList, err := sqlCheck(
+			advisorDBType,
+			"utf8mb4",
+			"utf8mb4_general_ci",
+			file.Statement,
+			ruleList,
+			newCatalogService(advisorDBType),
+		)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to run sql check for %s", file.FilePath)).SetInternal(err)
+		}
+		fileAdviceList = append(fileAdviceList, &advisor.FileAdvice{
+			FilePath:   file.FilePath,
+			AdviceList: adviceList,
+		})
 	}
 
 	s.metricReporter.Report(&metric.Metric{
@@ -127,7 +167,25 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		},
 	})
 
-	return c.JSON(http.StatusOK, adviceList)
+	switch advisor.NegotiateReportFormat(c.Request().Header.Get(echo.HeaderAccept)) {
+	case advisor.ReportFormatSARIF:
+		report, err := advisor.ConvertToSARIF(s.profile.Version, fileAdviceList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate SARIF report").SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, string(advisor.ReportFormatSARIF), report)
+	case advisor.ReportFormatJUnit:
+		report, err := advisor.ConvertToJUnit(fileAdviceList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate JUnit report").SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, report)
+	}
+
+	if request.Statement != "" {
+		return c.JSON(http.StatusOK, fileAdviceList[0].AdviceList)
+	}
+	return c.JSON(http.StatusOK, fileAdviceList)
 }
 
 func sqlCheck(
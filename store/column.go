@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
@@ -27,6 +29,26 @@ func (s *Store) FindColumn(ctx context.Context, find *api.ColumnFind) ([]*api.Co
 	return list, nil
 }
 
+// PatchColumn patches a column.
+func (s *Store) PatchColumn(ctx context.Context, patch *api.ColumnPatch) (*api.Column, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	column, err := s.patchColumnImpl(ctx, tx, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return column, nil
+}
+
 func generateColumnActions(oldColumnList []*api.Column, columnList []db.Column, databaseID, tableID int) ([]*api.ColumnDelete, []*api.ColumnCreate) {
 	var columnCreateList []*api.ColumnCreate
 	for _, column := range columnList {
@@ -62,6 +84,8 @@ func generateColumnActions(oldColumnList []*api.Column, columnList []db.Column,
 			deletes = append(deletes, &api.ColumnDelete{ID: oldValue.ID})
 		} else if ok && (oldValue.Position != newValue.Position || oldValue.Default != newValue.Default || oldValue.Nullable != newValue.Nullable || oldValue.Type != newValue.Type || oldValue.CharacterSet != newValue.CharacterSet || oldValue.Collation != newValue.Collation || oldValue.Comment != newValue.Comment) {
 			deletes = append(deletes, &api.ColumnDelete{ID: oldValue.ID})
+			// The sensitivity is classified by the user instead of synced from the database, so we keep it for the recreated column.
+			newValue.Sensitivity = oldValue.Sensitivity
 			creates = append(creates, newValue)
 		}
 	}
@@ -84,6 +108,11 @@ func (*Store) createColumnImpl(ctx context.Context, tx *Tx, create *api.ColumnCr
 		}
 	}
 
+	sensitivity := create.Sensitivity
+	if sensitivity == "" {
+		sensitivity = api.ColumnSensitivityNone
+	}
+
 	// Insert row into column.
 	query := `
 		INSERT INTO col (
@@ -98,10 +127,11 @@ func (*Store) createColumnImpl(ctx context.Context, tx *Tx, create *api.ColumnCr
 			type,
 			character_set,
 			"collation",
-			comment
+			comment,
+			sensitivity
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, "default", nullable, type, character_set, "collation", comment
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, "default", nullable, type, character_set, "collation", comment, sensitivity
 	`
 	var column api.Column
 	if err := tx.QueryRowContext(ctx, query,
@@ -117,6 +147,7 @@ func (*Store) createColumnImpl(ctx context.Context, tx *Tx, create *api.ColumnCr
 		create.CharacterSet,
 		create.Collation,
 		create.Comment,
+		sensitivity,
 	).Scan(
 		&column.ID,
 		&column.CreatorID,
@@ -133,6 +164,7 @@ func (*Store) createColumnImpl(ctx context.Context, tx *Tx, create *api.ColumnCr
 		&column.CharacterSet,
 		&column.Collation,
 		&column.Comment,
+		&column.Sensitivity,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -177,7 +209,8 @@ func (*Store) findColumnImpl(ctx context.Context, tx *Tx, find *api.ColumnFind)
 			type,
 			character_set,
 			"collation",
-			comment
+			comment,
+			sensitivity
 		FROM col
 		WHERE `+strings.Join(where, " AND ")+`
 		ORDER BY database_id, table_id, position ASC`,
@@ -209,6 +242,7 @@ func (*Store) findColumnImpl(ctx context.Context, tx *Tx, find *api.ColumnFind)
 			&column.CharacterSet,
 			&column.Collation,
 			&column.Comment,
+			&column.Sensitivity,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -226,6 +260,54 @@ func (*Store) findColumnImpl(ctx context.Context, tx *Tx, find *api.ColumnFind)
 	return columnList, nil
 }
 
+// patchColumnImpl patches a column.
+func (*Store) patchColumnImpl(ctx context.Context, tx *Tx, patch *api.ColumnPatch) (*api.Column, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Sensitivity; v != nil {
+		set, args = append(set, fmt.Sprintf("sensitivity = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	var column api.Column
+	defaultStr := sql.NullString{}
+	// Execute update query with RETURNING.
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE col
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, "default", nullable, type, character_set, "collation", comment, sensitivity
+	`, len(args)),
+		args...,
+	).Scan(
+		&column.ID,
+		&column.CreatorID,
+		&column.CreatedTs,
+		&column.UpdaterID,
+		&column.UpdatedTs,
+		&column.DatabaseID,
+		&column.TableID,
+		&column.Name,
+		&column.Position,
+		&defaultStr,
+		&column.Nullable,
+		&column.Type,
+		&column.CharacterSet,
+		&column.Collation,
+		&column.Comment,
+		&column.Sensitivity,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("column ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	if defaultStr.Valid {
+		column.Default = &defaultStr.String
+	}
+	return &column, nil
+}
+
 // deleteColumnImpl deletes columns.
 func (*Store) deleteColumnImpl(ctx context.Context, tx *Tx, delete *api.ColumnDelete) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM col WHERE id = $1`, delete.ID); err != nil {
@@ -70,6 +70,20 @@ func TestGenerateColumnActions(t *testing.T) {
 			wantDeletes: nil,
 			wantCreates: nil,
 		},
+		{
+			oldColumnList: []*api.Column{
+				{ID: 123, Name: "column1", Type: "int", Sensitivity: api.ColumnSensitivityHigh},
+			},
+			columnList: []db.Column{
+				{Name: "column1", Type: "bigint"},
+			},
+			wantDeletes: []*api.ColumnDelete{
+				{ID: 123},
+			},
+			wantCreates: []*api.ColumnCreate{
+				{Name: "column1", Type: "bigint", Sensitivity: api.ColumnSensitivityHigh, CreatorID: api.SystemBotID, DatabaseID: databaseID, TableID: tableID},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -37,6 +37,7 @@ type dataSourceRaw struct {
 	SslKey       string
 	HostOverride string
 	PortOverride string
+	PrincipalID  *int
 }
 
 // toDataSource creates an instance of DataSource based on the dataSourceRaw.
@@ -65,6 +66,7 @@ func (raw *dataSourceRaw) toDataSource() *api.DataSource {
 		SslKey:       raw.SslKey,
 		HostOverride: raw.HostOverride,
 		PortOverride: raw.PortOverride,
+		PrincipalID:  raw.PrincipalID,
 	}
 }
 
@@ -274,10 +276,11 @@ func (*Store) createDataSourceImpl(ctx context.Context, tx *Tx, create *api.Data
 			ssl_cert,
 			ssl_ca,
 			host_override,
-			port_override
+			port_override,
+			principal_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host_override, port_override
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host_override, port_override, principal_id
 	`
 	var dataSourceRaw dataSourceRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -294,6 +297,7 @@ func (*Store) createDataSourceImpl(ctx context.Context, tx *Tx, create *api.Data
 		create.SslCa,
 		create.HostOverride,
 		create.PortOverride,
+		create.PrincipalID,
 	).Scan(
 		&dataSourceRaw.ID,
 		&dataSourceRaw.CreatorID,
@@ -311,6 +315,7 @@ func (*Store) createDataSourceImpl(ctx context.Context, tx *Tx, create *api.Data
 		&dataSourceRaw.SslCa,
 		&dataSourceRaw.HostOverride,
 		&dataSourceRaw.PortOverride,
+		&dataSourceRaw.PrincipalID,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -335,6 +340,9 @@ func (*Store) findDataSourceImpl(ctx context.Context, tx *Tx, find *api.DataSour
 	if v := find.Type; v != nil {
 		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, api.DataSourceType(*v))
 	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -353,7 +361,8 @@ func (*Store) findDataSourceImpl(ctx context.Context, tx *Tx, find *api.DataSour
 			ssl_cert,
 			ssl_ca,
 			host_override,
-			port_override
+			port_override,
+			principal_id
 		FROM data_source
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -384,6 +393,7 @@ func (*Store) findDataSourceImpl(ctx context.Context, tx *Tx, find *api.DataSour
 			&dataSourceRaw.SslCa,
 			&dataSourceRaw.HostOverride,
 			&dataSourceRaw.PortOverride,
+			&dataSourceRaw.PrincipalID,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -430,7 +440,7 @@ func (*Store) patchDataSourceImpl(ctx context.Context, tx *Tx, patch *api.DataSo
 			UPDATE data_source
 			SET `+strings.Join(set, ", ")+`
 			WHERE id = $%d
-			RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host_override, port_override
+			RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host_override, port_override, principal_id
 		`, len(args)),
 		args...,
 	).Scan(
@@ -450,6 +460,7 @@ func (*Store) patchDataSourceImpl(ctx context.Context, tx *Tx, patch *api.DataSo
 		&dataSourceRaw.SslCa,
 		&dataSourceRaw.HostOverride,
 		&dataSourceRaw.PortOverride,
+		&dataSourceRaw.PrincipalID,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("DataSource not found with ID %d", patch.ID)}
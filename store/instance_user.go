@@ -53,6 +53,22 @@ func (s *Store) GetInstanceUser(ctx context.Context, find *api.InstanceUserFind)
 	return list[0], nil
 }
 
+// FindInstanceUser retrieves a list of instance users based on find.
+func (s *Store) FindInstanceUser(ctx context.Context, find *api.InstanceUserFind) ([]*api.InstanceUser, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findInstanceUserImpl(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
 // FindInstanceUserByInstanceID retrieves a list of instanceUsers based on find.
 func (s *Store) FindInstanceUserByInstanceID(ctx context.Context, id int) ([]*api.InstanceUser, error) {
 	find := &api.InstanceUserFind{
@@ -73,6 +89,39 @@ func (s *Store) FindInstanceUserByInstanceID(ctx context.Context, id int) ([]*ap
 	return list, nil
 }
 
+// RevealInstanceUserPassword returns the one-time password of the instance user and clears it.
+// It returns an empty string if the password has been revealed.
+func (s *Store) RevealInstanceUserPassword(ctx context.Context, id int) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", FormatError(err)
+	}
+	defer tx.Rollback()
+
+	var password string
+	if err := tx.QueryRowContext(ctx, `
+		WITH old AS (
+			SELECT id, password FROM instance_user WHERE id = $1 FOR UPDATE
+		)
+		UPDATE instance_user
+		SET password = ''
+		FROM old
+		WHERE instance_user.id = old.id
+		RETURNING old.password
+	`, id).Scan(&password); err != nil {
+		if err == sql.ErrNoRows {
+			return "", &common.Error{Code: common.NotFound, Err: errors.Errorf("instance user not found with ID %d", id)}
+		}
+		return "", FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", FormatError(err)
+	}
+
+	return password, nil
+}
+
 // DeleteInstanceUser deletes an existing instance user by ID.
 func (s *Store) DeleteInstanceUser(ctx context.Context, delete *api.InstanceUserDelete) error {
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -101,13 +150,17 @@ func upsertInstanceUserImpl(ctx context.Context, tx *Tx, upsert *api.InstanceUse
 			updater_id,
 			instance_id,
 			name,
-			"grant"
+			"grant",
+			expire_ts,
+			password
 		)
-		VALUES ($1, $2, $3, $4, $5)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, 0), COALESCE($7, ''))
 		ON CONFLICT (instance_id, name) DO UPDATE SET
 			updater_id = excluded.updater_id,
-			"grant" = excluded.grant
-		RETURNING id, instance_id, name, "grant"
+			"grant" = excluded.grant,
+			expire_ts = COALESCE($6, instance_user.expire_ts),
+			password = COALESCE($7, instance_user.password)
+		RETURNING id, instance_id, name, "grant", expire_ts
 	`
 	var instanceUser api.InstanceUser
 	if err := tx.QueryRowContext(ctx, query,
@@ -116,11 +169,14 @@ func upsertInstanceUserImpl(ctx context.Context, tx *Tx, upsert *api.InstanceUse
 		upsert.InstanceID,
 		upsert.Name,
 		upsert.Grant,
+		upsert.ExpireTs,
+		upsert.Password,
 	).Scan(
 		&instanceUser.ID,
 		&instanceUser.InstanceID,
 		&instanceUser.Name,
 		&instanceUser.Grant,
+		&instanceUser.ExpireTs,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -140,13 +196,20 @@ func findInstanceUserImpl(ctx context.Context, tx *Tx, find *api.InstanceUserFin
 	if v := find.InstanceID; v != nil {
 		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ExpireTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("expire_ts > 0 AND expire_ts <= $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
 			id,
 			instance_id,
 			name,
-			"grant"
+			"grant",
+			expire_ts
 		FROM instance_user
 		WHERE `+strings.Join(where, " AND ")+`
 		ORDER BY name ASC
@@ -167,6 +230,7 @@ func findInstanceUserImpl(ctx context.Context, tx *Tx, find *api.InstanceUserFin
 			&instanceUser.InstanceID,
 			&instanceUser.Name,
 			&instanceUser.Grant,
+			&instanceUser.ExpireTs,
 		); err != nil {
 			return nil, FormatError(err)
 		}
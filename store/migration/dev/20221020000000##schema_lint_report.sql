@@ -0,0 +1,24 @@
+-- schema_lint_report stores the latest result of linting the whole database schema against the SQL review policy.
+CREATE TABLE schema_lint_report (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    status TEXT NOT NULL CHECK (status IN ('SUCCESS', 'WARN', 'ERROR')),
+    error_count INTEGER NOT NULL DEFAULT 0,
+    warning_count INTEGER NOT NULL DEFAULT 0,
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_schema_lint_report_unique_database_id ON schema_lint_report(database_id);
+
+ALTER SEQUENCE schema_lint_report_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_lint_report_updated_ts
+BEFORE
+UPDATE
+    ON schema_lint_report FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
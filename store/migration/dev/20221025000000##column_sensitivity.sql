@@ -0,0 +1 @@
+ALTER TABLE col ADD COLUMN IF NOT EXISTS sensitivity TEXT NOT NULL CHECK (sensitivity IN ('NONE', 'LOW', 'MEDIUM', 'HIGH')) DEFAULT 'NONE';
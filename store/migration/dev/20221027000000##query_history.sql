@@ -0,0 +1,37 @@
+-- query_history stores the SQL editor queries for searching the query history.
+-- Unlike other tables, it doesn't have row_status/updater_id/updated_ts because the query history is immutable.
+CREATE TABLE query_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('SUCCESS', 'WARN', 'ERROR')),
+    duration_ns BIGINT NOT NULL,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_query_history_creator_id_created_ts ON query_history(creator_id, created_ts);
+
+CREATE INDEX idx_query_history_instance_id_database_name_created_ts ON query_history(instance_id, database_name, created_ts);
+
+CREATE INDEX idx_query_history_created_ts ON query_history(created_ts);
+
+ALTER SEQUENCE query_history_id_seq RESTART WITH 101;
+
+-- Backfill the query history from the SQL editor query activities.
+INSERT INTO query_history (creator_id, created_ts, instance_id, database_name, statement, status, duration_ns, error)
+SELECT
+    activity.creator_id,
+    activity.created_ts,
+    activity.container_id,
+    COALESCE(activity.payload->>'databaseName', ''),
+    COALESCE(activity.payload->>'statement', ''),
+    CASE activity.level WHEN 'INFO' THEN 'SUCCESS' ELSE activity.level END,
+    COALESCE((activity.payload->>'durationNs')::BIGINT, 0),
+    COALESCE(activity.payload->>'error', '')
+FROM activity
+JOIN instance ON instance.id = activity.container_id
+WHERE activity.type = 'bb.sql-editor.query'
+ORDER BY activity.id;
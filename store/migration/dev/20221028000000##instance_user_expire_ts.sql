@@ -0,0 +1,3 @@
+-- expire_ts is the time when the temporary database user granted by the database grant issue expires.
+-- 0 means the user never expires, which is the case for the users synced from the instance.
+ALTER TABLE instance_user ADD COLUMN IF NOT EXISTS expire_ts BIGINT NOT NULL DEFAULT 0;
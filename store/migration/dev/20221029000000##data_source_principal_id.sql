@@ -0,0 +1,3 @@
+-- principal_id is set for the read-only data source requested by the data source request issue.
+-- The SQL editor queries of the principal are routed through it, and it's not used by other principals.
+ALTER TABLE data_source ADD COLUMN IF NOT EXISTS principal_id INTEGER REFERENCES principal (id);
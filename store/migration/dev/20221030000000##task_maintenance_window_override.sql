@@ -0,0 +1,2 @@
+-- maintenance_window_override is set by the owners to run the task outside the maintenance window of the environment in an emergency.
+ALTER TABLE task ADD COLUMN IF NOT EXISTS maintenance_window_override BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- rollout stores the rollout controls of the tenant deployment that the stage is created from.
+ALTER TABLE stage ADD COLUMN IF NOT EXISTS rollout JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,14 @@
+-- task_run_log stores the log lines of the task runs, e.g. the statements executed and the warnings from the database.
+CREATE TABLE task_run_log (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    task_run_id INTEGER NOT NULL REFERENCES task_run (id),
+    level TEXT NOT NULL CHECK (level IN ('INFO', 'WARN', 'ERROR')),
+    message TEXT NOT NULL
+);
+
+CREATE INDEX idx_task_run_log_task_run_id ON task_run_log(task_run_id);
+
+CREATE INDEX idx_task_run_log_created_ts ON task_run_log(created_ts);
+
+ALTER SEQUENCE task_run_log_id_seq RESTART WITH 101;
@@ -0,0 +1,3 @@
+-- password is the password of the temporary database user granted by the database grant issue.
+-- It's only revealed to the requester once, and cleared after that.
+ALTER TABLE instance_user ADD COLUMN IF NOT EXISTS password TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- pg_trgm makes the GIN index support searching the query history statements by ILIKE.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_query_history_statement ON query_history USING gin (statement gin_trgm_ops);
@@ -297,7 +297,12 @@ CREATE TABLE instance_user (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     instance_id INTEGER NOT NULL REFERENCES instance (id),
     name TEXT NOT NULL,
-    "grant" TEXT NOT NULL
+    "grant" TEXT NOT NULL,
+    -- expire_ts is the time when the temporary database user granted by the database grant issue expires, 0 means never.
+    expire_ts BIGINT NOT NULL DEFAULT 0,
+    -- password is the password of the temporary database user granted by the database grant issue.
+    -- It's only revealed to the requester once, and cleared after that.
+    password TEXT NOT NULL DEFAULT ''
 );
 
 ALTER SEQUENCE instance_user_id_seq RESTART WITH 101;
@@ -395,7 +400,8 @@ CREATE TABLE col (
     type TEXT NOT NULL,
     character_set TEXT NOT NULL,
     "collation" TEXT NOT NULL,
-    comment TEXT NOT NULL
+    comment TEXT NOT NULL,
+    sensitivity TEXT NOT NULL CHECK (sensitivity IN ('NONE', 'LOW', 'MEDIUM', 'HIGH')) DEFAULT 'NONE'
 );
 
 CREATE INDEX idx_col_database_id_table_id ON col(database_id, table_id);
@@ -518,7 +524,9 @@ CREATE TABLE data_source (
     ssl_ca TEXT NOT NULL DEFAULT '',
     -- host_override and port_override are used for read-replicas that have different connection addresses.
     host_override TEXT NOT NULL DEFAULT '',
-    port_override TEXT NOT NULL DEFAULT ''
+    port_override TEXT NOT NULL DEFAULT '',
+    -- principal_id is set for the read-only data source requested by the principal, which is only used by the principal's SQL editor queries.
+    principal_id INTEGER REFERENCES principal (id)
 );
 
 CREATE INDEX idx_data_source_instance_id ON data_source(instance_id);
@@ -629,7 +637,8 @@ CREATE TABLE stage (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     pipeline_id INTEGER NOT NULL REFERENCES pipeline (id),
     environment_id INTEGER NOT NULL REFERENCES environment (id),
-    name TEXT NOT NULL
+    name TEXT NOT NULL,
+    rollout JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_stage_pipeline_id ON stage(pipeline_id);
@@ -659,7 +668,8 @@ CREATE TABLE task (
     status TEXT NOT NULL CHECK (status IN ('PENDING', 'PENDING_APPROVAL', 'RUNNING', 'DONE', 'FAILED', 'CANCELED')),
     type TEXT NOT NULL CHECK (type LIKE 'bb.task.%'),
     payload JSONB NOT NULL DEFAULT '{}',
-    earliest_allowed_ts BIGINT NOT NULL DEFAULT 0
+    earliest_allowed_ts BIGINT NOT NULL DEFAULT 0,
+    maintenance_window_override BOOLEAN NOT NULL DEFAULT FALSE
 );
 
 CREATE INDEX idx_task_pipeline_id_stage_id ON task(pipeline_id, stage_id);
@@ -727,6 +737,21 @@ UPDATE
     ON task_run FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- task_run_log stores the log lines of the task runs, e.g. the statements executed and the warnings from the database.
+CREATE TABLE task_run_log (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    task_run_id INTEGER NOT NULL REFERENCES task_run (id),
+    level TEXT NOT NULL CHECK (level IN ('INFO', 'WARN', 'ERROR')),
+    message TEXT NOT NULL
+);
+
+CREATE INDEX idx_task_run_log_task_run_id ON task_run_log(task_run_id);
+
+CREATE INDEX idx_task_run_log_created_ts ON task_run_log(created_ts);
+
+ALTER SEQUENCE task_run_log_id_seq RESTART WITH 101;
+
 -- task check run table stores the task check run
 CREATE TABLE task_check_run (
     id SERIAL PRIMARY KEY,
@@ -1130,3 +1155,55 @@ CREATE TABLE sheet_organizer (
 CREATE UNIQUE INDEX idx_sheet_organizer_unique_sheet_id_principal_id ON sheet_organizer(sheet_id, principal_id);
 
 CREATE INDEX idx_sheet_organizer_principal_id ON sheet_organizer(principal_id);
+
+-- schema_lint_report stores the latest result of linting the whole database schema against the SQL review policy.
+CREATE TABLE schema_lint_report (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    status TEXT NOT NULL CHECK (status IN ('SUCCESS', 'WARN', 'ERROR')),
+    error_count INTEGER NOT NULL DEFAULT 0,
+    warning_count INTEGER NOT NULL DEFAULT 0,
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_schema_lint_report_unique_database_id ON schema_lint_report(database_id);
+
+ALTER SEQUENCE schema_lint_report_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_lint_report_updated_ts
+BEFORE
+UPDATE
+    ON schema_lint_report FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- query_history stores the SQL editor queries for searching the query history.
+-- Unlike other tables, it doesn't have row_status/updater_id/updated_ts because the query history is immutable.
+CREATE TABLE query_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('SUCCESS', 'WARN', 'ERROR')),
+    duration_ns BIGINT NOT NULL,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_query_history_creator_id_created_ts ON query_history(creator_id, created_ts);
+
+CREATE INDEX idx_query_history_instance_id_database_name_created_ts ON query_history(instance_id, database_name, created_ts);
+
+CREATE INDEX idx_query_history_created_ts ON query_history(created_ts);
+
+-- pg_trgm makes the GIN index support searching the query history statements by ILIKE.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_query_history_statement ON query_history USING gin (statement gin_trgm_ops);
+
+ALTER SEQUENCE query_history_id_seq RESTART WITH 101;
@@ -226,6 +226,84 @@ func (s *Store) GetEnvironmentTierPolicyByEnvID(ctx context.Context, environment
 	return api.UnmarshalEnvironmentTierPolicy(policy.Payload)
 }
 
+// GetSQLExportPolicyByEnvID will get the SQL export policy for an environment.
+func (s *Store) GetSQLExportPolicyByEnvID(ctx context.Context, environmentID int) (*api.SQLExportPolicy, error) {
+	pType := api.PolicyTypeSQLExport
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalSQLExportPolicy(policy.Payload)
+}
+
+// GetQueryTimeoutPolicyByEnvID will get the query timeout policy for an environment.
+func (s *Store) GetQueryTimeoutPolicyByEnvID(ctx context.Context, environmentID int) (*api.QueryTimeoutPolicy, error) {
+	pType := api.PolicyTypeQueryTimeout
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalQueryTimeoutPolicy(policy.Payload)
+}
+
+// GetTaskRetryPolicyByEnvID will get the task retry policy for an environment.
+func (s *Store) GetTaskRetryPolicyByEnvID(ctx context.Context, environmentID int) (*api.TaskRetryPolicy, error) {
+	pType := api.PolicyTypeTaskRetry
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalTaskRetryPolicy(policy.Payload)
+}
+
+// GetTaskConcurrencyPolicyByEnvID will get the task concurrency policy for an environment.
+func (s *Store) GetTaskConcurrencyPolicyByEnvID(ctx context.Context, environmentID int) (*api.TaskConcurrencyPolicy, error) {
+	pType := api.PolicyTypeTaskConcurrency
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalTaskConcurrencyPolicy(policy.Payload)
+}
+
+// GetTaskRunLogRetentionPolicyByEnvID will get the task run log retention policy for an environment.
+func (s *Store) GetTaskRunLogRetentionPolicyByEnvID(ctx context.Context, environmentID int) (*api.TaskRunLogRetentionPolicy, error) {
+	pType := api.PolicyTypeTaskRunLogRetention
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalTaskRunLogRetentionPolicy(policy.Payload)
+}
+
+// GetMaintenanceWindowPolicyByEnvID will get the maintenance window policy for an environment.
+func (s *Store) GetMaintenanceWindowPolicyByEnvID(ctx context.Context, environmentID int) (*api.MaintenanceWindowPolicy, error) {
+	pType := api.PolicyTypeMaintenanceWindow
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalMaintenanceWindowPolicy(policy.Payload)
+}
+
 //
 // private functions
 //
@@ -0,0 +1,251 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// queryHistoryRaw is the store model for a QueryHistory.
+// Fields have exactly the same meanings as QueryHistory.
+type queryHistoryRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+
+	// Related fields
+	InstanceID   int
+	DatabaseName string
+
+	// Domain specific fields
+	Statement  string
+	Status     api.QueryHistoryStatus
+	DurationNs int64
+	Error      string
+}
+
+// toQueryHistory creates an instance of QueryHistory based on the queryHistoryRaw.
+// This is intended to be called when we need to compose a QueryHistory relationship.
+func (raw *queryHistoryRaw) toQueryHistory() *api.QueryHistory {
+	return &api.QueryHistory{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+
+		// Related fields
+		InstanceID:   raw.InstanceID,
+		DatabaseName: raw.DatabaseName,
+
+		// Domain specific fields
+		Statement:  raw.Statement,
+		Status:     raw.Status,
+		DurationNs: raw.DurationNs,
+		Error:      raw.Error,
+	}
+}
+
+// CreateQueryHistory creates an instance of QueryHistory.
+func (s *Store) CreateQueryHistory(ctx context.Context, create *api.QueryHistoryCreate) (*api.QueryHistory, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	raw, err := createQueryHistoryImpl(ctx, tx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create query history with QueryHistoryCreate[%+v]", create)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	queryHistory, err := s.composeQueryHistory(ctx, raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose query history with queryHistoryRaw[%+v]", raw)
+	}
+	return queryHistory, nil
+}
+
+// FindQueryHistory finds a list of QueryHistory instances in the descending order of ID.
+func (s *Store) FindQueryHistory(ctx context.Context, find *api.QueryHistoryFind) ([]*api.QueryHistory, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	rawList, err := findQueryHistoryImpl(ctx, tx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find query history with QueryHistoryFind[%+v]", find)
+	}
+
+	var queryHistoryList []*api.QueryHistory
+	for _, raw := range rawList {
+		queryHistory, err := s.composeQueryHistory(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose query history with queryHistoryRaw[%+v]", raw)
+		}
+		queryHistoryList = append(queryHistoryList, queryHistory)
+	}
+	return queryHistoryList, nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeQueryHistory(ctx context.Context, raw *queryHistoryRaw) (*api.QueryHistory, error) {
+	queryHistory := raw.toQueryHistory()
+
+	creator, err := s.GetPrincipalByID(ctx, queryHistory.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	queryHistory.Creator = creator
+
+	return queryHistory, nil
+}
+
+// createQueryHistoryImpl creates a new query history.
+func createQueryHistoryImpl(ctx context.Context, tx *Tx, create *api.QueryHistoryCreate) (*queryHistoryRaw, error) {
+	query := `
+		INSERT INTO query_history (
+			creator_id,
+			instance_id,
+			database_name,
+			statement,
+			status,
+			duration_ns,
+			error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, instance_id, database_name, statement, status, duration_ns, error
+	`
+	var raw queryHistoryRaw
+	if err := tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.InstanceID,
+		create.DatabaseName,
+		create.Statement,
+		create.Status,
+		create.DurationNs,
+		create.Error,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.InstanceID,
+		&raw.DatabaseName,
+		&raw.Statement,
+		&raw.Status,
+		&raw.DurationNs,
+		&raw.Error,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+func findQueryHistoryImpl(ctx context.Context, tx *Tx, find *api.QueryHistoryFind) ([]*queryHistoryRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseName; v != nil {
+		where, args = append(where, fmt.Sprintf("database_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MinDurationNs; v != nil {
+		where, args = append(where, fmt.Sprintf("duration_ns >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MaxDurationNs; v != nil {
+		where, args = append(where, fmt.Sprintf("duration_ns <= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Statement; v != nil {
+		where, args = append(where, fmt.Sprintf("statement ILIKE $%d ESCAPE '\\'", len(args)+1)), append(args, "%"+escapeLikePattern(*v)+"%")
+	}
+	if v := find.SinceID; v != nil {
+		where, args = append(where, fmt.Sprintf("id <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			instance_id,
+			database_name,
+			statement,
+			status,
+			duration_ns,
+			error
+		FROM query_history
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*queryHistoryRaw
+	for rows.Next() {
+		var raw queryHistoryRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.InstanceID,
+			&raw.DatabaseName,
+			&raw.Statement,
+			&raw.Status,
+			&raw.DurationNs,
+			&raw.Error,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
+
+// escapeLikePattern escapes the wildcard characters in the LIKE pattern with backslash.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "SELECT * FROM t", want: "SELECT * FROM t"},
+		{pattern: "user_id", want: `user\_id`},
+		{pattern: "100%", want: `100\%`},
+		{pattern: `a\b`, want: `a\\b`},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, escapeLikePattern(test.pattern))
+	}
+}
@@ -0,0 +1,356 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// schemaLintReportRaw is the store model for a SchemaLintReport.
+// Fields have exactly the same meanings as SchemaLintReport.
+type schemaLintReportRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Status       api.SchemaLintReportStatus
+	ErrorCount   int
+	WarningCount int
+	Payload      string
+}
+
+// toSchemaLintReport creates an instance of SchemaLintReport based on the schemaLintReportRaw.
+// This is intended to be called when we need to compose a SchemaLintReport relationship.
+func (raw *schemaLintReportRaw) toSchemaLintReport() *api.SchemaLintReport {
+	return &api.SchemaLintReport{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Status:       raw.Status,
+		ErrorCount:   raw.ErrorCount,
+		WarningCount: raw.WarningCount,
+		Payload:      raw.Payload,
+	}
+}
+
+// UpsertSchemaLintReport upserts the schema lint report of a database.
+func (s *Store) UpsertSchemaLintReport(ctx context.Context, upsert *api.SchemaLintReportUpsert) (*api.SchemaLintReport, error) {
+	raw, err := s.upsertSchemaLintReportRaw(ctx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert schema lint report with SchemaLintReportUpsert[%+v]", upsert)
+	}
+	report, err := s.composeSchemaLintReport(ctx, raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose schema lint report with schemaLintReportRaw[%+v]", raw)
+	}
+	return report, nil
+}
+
+// FindSchemaLintReport finds a list of SchemaLintReport instances.
+func (s *Store) FindSchemaLintReport(ctx context.Context, find *api.SchemaLintReportFind) ([]*api.SchemaLintReport, error) {
+	rawList, err := s.findSchemaLintReportRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find schema lint report with SchemaLintReportFind[%+v]", find)
+	}
+	var reportList []*api.SchemaLintReport
+	for _, raw := range rawList {
+		report, err := s.composeSchemaLintReport(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose schema lint report with schemaLintReportRaw[%+v]", raw)
+		}
+		reportList = append(reportList, report)
+	}
+	return reportList, nil
+}
+
+// GetSchemaLintReportByDatabaseID gets the schema lint report of a database.
+// Returns nil if the database has not been linted yet.
+func (s *Store) GetSchemaLintReportByDatabaseID(ctx context.Context, databaseID int) (*api.SchemaLintReport, error) {
+	reportList, err := s.FindSchemaLintReport(ctx, &api.SchemaLintReportFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, err
+	}
+	if len(reportList) == 0 {
+		return nil, nil
+	} else if len(reportList) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d schema lint reports for database %d, expect 1", len(reportList), databaseID)}
+	}
+	return reportList[0], nil
+}
+
+// DeleteSchemaLintReport deletes the schema lint report of a database if it exists.
+func (s *Store) DeleteSchemaLintReport(ctx context.Context, delete *api.SchemaLintReportDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteSchemaLintReportImpl(ctx, tx, delete); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeSchemaLintReport(ctx context.Context, raw *schemaLintReportRaw) (*api.SchemaLintReport, error) {
+	report := raw.toSchemaLintReport()
+
+	creator, err := s.GetPrincipalByID(ctx, report.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	report.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, report.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	report.Updater = updater
+
+	database, err := s.GetDatabase(ctx, &api.DatabaseFind{ID: &report.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	report.Database = database
+
+	return report, nil
+}
+
+// upsertSchemaLintReportRaw updates the existing report of the database, otherwise creates a new one.
+// Do not use ON CONFLICT (upsert syntax) as it will consume auto-increment id.
+func (s *Store) upsertSchemaLintReportRaw(ctx context.Context, upsert *api.SchemaLintReportUpsert) (*schemaLintReportRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findSchemaLintReportListImpl(ctx, tx, &api.SchemaLintReportFind{DatabaseID: &upsert.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw *schemaLintReportRaw
+	if len(list) == 0 {
+		raw, err = createSchemaLintReportImpl(ctx, tx, upsert)
+	} else {
+		raw, err = patchSchemaLintReportImpl(ctx, tx, list[0].ID, upsert)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return raw, nil
+}
+
+// findSchemaLintReportRaw retrieves a list of schema lint reports based on the find condition.
+func (s *Store) findSchemaLintReportRaw(ctx context.Context, find *api.SchemaLintReportFind) ([]*schemaLintReportRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findSchemaLintReportListImpl(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// createSchemaLintReportImpl creates a new schema lint report.
+func createSchemaLintReportImpl(ctx context.Context, tx *Tx, upsert *api.SchemaLintReportUpsert) (*schemaLintReportRaw, error) {
+	if upsert.Payload == "" {
+		upsert.Payload = "{}"
+	}
+	query := `
+		INSERT INTO schema_lint_report (
+			creator_id,
+			updater_id,
+			database_id,
+			status,
+			error_count,
+			warning_count,
+			payload
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, status, error_count, warning_count, payload
+	`
+	var raw schemaLintReportRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.DatabaseID,
+		upsert.Status,
+		upsert.ErrorCount,
+		upsert.WarningCount,
+		upsert.Payload,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DatabaseID,
+		&raw.Status,
+		&raw.ErrorCount,
+		&raw.WarningCount,
+		&raw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+// patchSchemaLintReportImpl overwrites the schema lint report with the latest result.
+func patchSchemaLintReportImpl(ctx context.Context, tx *Tx, id int, upsert *api.SchemaLintReportUpsert) (*schemaLintReportRaw, error) {
+	if upsert.Payload == "" {
+		upsert.Payload = "{}"
+	}
+	query := `
+		UPDATE schema_lint_report
+		SET updater_id = $1, status = $2, error_count = $3, warning_count = $4, payload = $5
+		WHERE id = $6
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, status, error_count, warning_count, payload
+	`
+	var raw schemaLintReportRaw
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.Status,
+		upsert.ErrorCount,
+		upsert.WarningCount,
+		upsert.Payload,
+		id,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DatabaseID,
+		&raw.Status,
+		&raw.ErrorCount,
+		&raw.WarningCount,
+		&raw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return &raw, nil
+}
+
+// deleteSchemaLintReportImpl deletes the schema lint report of the database.
+func deleteSchemaLintReportImpl(ctx context.Context, tx *Tx, delete *api.SchemaLintReportDelete) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_lint_report WHERE database_id = $1`, delete.DatabaseID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+func findSchemaLintReportListImpl(ctx context.Context, tx *Tx, find *api.SchemaLintReportFind) ([]*schemaLintReportRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.EnvironmentID; v != nil {
+		where, args = append(where, fmt.Sprintf(`database_id IN (
+			SELECT db.id FROM db
+			INNER JOIN instance ON db.instance_id = instance.id
+			WHERE instance.environment_id = $%d AND instance.row_status = 'NORMAL'
+		)`, len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			status,
+			error_count,
+			warning_count,
+			payload
+		FROM schema_lint_report
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into rawList.
+	var rawList []*schemaLintReportRaw
+	for rows.Next() {
+		var raw schemaLintReportRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.DatabaseID,
+			&raw.Status,
+			&raw.ErrorCount,
+			&raw.WarningCount,
+			&raw.Payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return rawList, nil
+}
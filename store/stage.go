@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -28,7 +29,8 @@ type stageRaw struct {
 	EnvironmentID int
 
 	// Domain specific fields
-	Name string
+	Name    string
+	Rollout api.DeploymentRollout
 }
 
 // toStage creates an instance of Stage based on the stageRaw.
@@ -48,7 +50,8 @@ func (raw *stageRaw) toStage() *api.Stage {
 		EnvironmentID: raw.EnvironmentID,
 
 		// Domain specific fields
-		Name: raw.Name,
+		Name:    raw.Name,
+		Rollout: raw.Rollout,
 	}
 }
 
@@ -65,6 +68,23 @@ func (s *Store) CreateStage(ctx context.Context, create *api.StageCreate) (*api.
 	return stage, nil
 }
 
+// GetStageByID gets an instance of Stage.
+func (s *Store) GetStageByID(ctx context.Context, id int) (*api.Stage, error) {
+	find := &api.StageFind{ID: &id}
+	stageRawList, err := s.findStageRaw(ctx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Stage with ID %d", id)
+	}
+	if len(stageRawList) == 0 {
+		return nil, nil
+	}
+	stage, err := s.composeStage(ctx, stageRawList[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose Stage with stageRaw[%+v]", stageRawList[0])
+	}
+	return stage, nil
+}
+
 // FindStage finds a list of Stage instances.
 func (s *Store) FindStage(ctx context.Context, find *api.StageFind) ([]*api.Stage, error) {
 	stageRawList, err := s.findStageRaw(ctx, find)
@@ -183,16 +203,21 @@ func (s *Store) findStageRaw(ctx context.Context, find *api.StageFind) ([]*stage
 
 // createStageImpl creates a new stage.
 func (*Store) createStageImpl(ctx context.Context, tx *Tx, create *api.StageCreate) (*stageRaw, error) {
+	rollout, err := json.Marshal(create.Rollout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal rollout %+v", create.Rollout)
+	}
 	query := `
 		INSERT INTO stage (
 			creator_id,
 			updater_id,
 			pipeline_id,
 			environment_id,
-			name
+			name,
+			rollout
 		)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, environment_id, name` + `
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, environment_id, name, rollout` + `
 	`
 	var stageRaw stageRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -201,6 +226,7 @@ func (*Store) createStageImpl(ctx context.Context, tx *Tx, create *api.StageCrea
 		create.PipelineID,
 		create.EnvironmentID,
 		create.Name,
+		string(rollout),
 	).Scan(
 		&stageRaw.ID,
 		&stageRaw.CreatorID,
@@ -210,6 +236,7 @@ func (*Store) createStageImpl(ctx context.Context, tx *Tx, create *api.StageCrea
 		&stageRaw.PipelineID,
 		&stageRaw.EnvironmentID,
 		&stageRaw.Name,
+		&stageRaw.Rollout,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -238,7 +265,8 @@ func (*Store) findStageImpl(ctx context.Context, tx *Tx, find *api.StageFind) ([
 			updated_ts,
 			pipeline_id,
 			environment_id,
-			name
+			name,
+			rollout
 		FROM stage
 		WHERE `+strings.Join(where, " AND ")+` ORDER BY id ASC`,
 		args...,
@@ -261,6 +289,7 @@ func (*Store) findStageImpl(ctx context.Context, tx *Tx, find *api.StageFind) ([
 			&stageRaw.PipelineID,
 			&stageRaw.EnvironmentID,
 			&stageRaw.Name,
+			&stageRaw.Rollout,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -38,12 +38,13 @@ type taskRaw struct {
 	TaskCheckRunRawList []*taskCheckRunRaw
 
 	// Domain specific fields
-	Name              string
-	Status            api.TaskStatus
-	Type              api.TaskType
-	Payload           string
-	EarliestAllowedTs int64
-	BlockedBy         []string
+	Name                      string
+	Status                    api.TaskStatus
+	Type                      api.TaskType
+	Payload                   string
+	EarliestAllowedTs         int64
+	MaintenanceWindowOverride bool
+	BlockedBy                 []string
 }
 
 // toTask creates an instance of Task based on the taskRaw.
@@ -66,12 +67,13 @@ func (raw *taskRaw) toTask() *api.Task {
 		DatabaseID: raw.DatabaseID,
 
 		// Domain specific fields
-		Name:              raw.Name,
-		Status:            raw.Status,
-		Type:              raw.Type,
-		Payload:           raw.Payload,
-		EarliestAllowedTs: raw.EarliestAllowedTs,
-		BlockedBy:         raw.BlockedBy,
+		Name:                      raw.Name,
+		Status:                    raw.Status,
+		Type:                      raw.Type,
+		Payload:                   raw.Payload,
+		EarliestAllowedTs:         raw.EarliestAllowedTs,
+		MaintenanceWindowOverride: raw.MaintenanceWindowOverride,
+		BlockedBy:                 raw.BlockedBy,
 	}
 	for _, taskRunRaw := range raw.TaskRunRawList {
 		task.TaskRunList = append(task.TaskRunList, taskRunRaw.toTaskRun())
@@ -404,7 +406,7 @@ func (*Store) createTaskImpl(ctx context.Context, tx *Tx, create *api.TaskCreate
 			earliest_allowed_ts
 		)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts, maintenance_window_override
 	`
 	if create.DatabaseID == nil {
 		row = tx.QueryRowContext(ctx, query,
@@ -435,7 +437,7 @@ func (*Store) createTaskImpl(ctx context.Context, tx *Tx, create *api.TaskCreate
 				earliest_allowed_ts
 			)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-			RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts
+			RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts, maintenance_window_override
 		`
 		row = tx.QueryRowContext(ctx, query,
 			create.CreatorID,
@@ -469,6 +471,7 @@ func (*Store) createTaskImpl(ctx context.Context, tx *Tx, create *api.TaskCreate
 		&taskRaw.Type,
 		&taskRaw.Payload,
 		&taskRaw.EarliestAllowedTs,
+		&taskRaw.MaintenanceWindowOverride,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -532,7 +535,8 @@ func (s *Store) findTaskImpl(ctx context.Context, tx *Tx, find *api.TaskFind) ([
 			status,
 			type,
 			payload,
-			earliest_allowed_ts
+			earliest_allowed_ts,
+			maintenance_window_override
 		FROM task
 		WHERE `+strings.Join(where, " AND ")+` ORDER BY id ASC`,
 		args...,
@@ -561,6 +565,7 @@ func (s *Store) findTaskImpl(ctx context.Context, tx *Tx, find *api.TaskFind) ([
 			&taskRaw.Type,
 			&taskRaw.Payload,
 			&taskRaw.EarliestAllowedTs,
+			&taskRaw.MaintenanceWindowOverride,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -609,6 +614,9 @@ func (*Store) patchTaskImpl(ctx context.Context, tx *Tx, patch *api.TaskPatch) (
 	if v := patch.EarliestAllowedTs; v != nil {
 		set, args = append(set, fmt.Sprintf("earliest_allowed_ts = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.MaintenanceWindowOverride; v != nil {
+		set, args = append(set, fmt.Sprintf("maintenance_window_override = $%d", len(args)+1)), append(args, *v)
+	}
 	args = append(args, patch.ID)
 
 	var taskRaw taskRaw
@@ -617,7 +625,7 @@ func (*Store) patchTaskImpl(ctx context.Context, tx *Tx, patch *api.TaskPatch) (
 		UPDATE task
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts, maintenance_window_override
 	`, len(args)),
 		args...,
 	).Scan(
@@ -635,6 +643,7 @@ func (*Store) patchTaskImpl(ctx context.Context, tx *Tx, patch *api.TaskPatch) (
 		&taskRaw.Type,
 		&taskRaw.Payload,
 		&taskRaw.EarliestAllowedTs,
+		&taskRaw.MaintenanceWindowOverride,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("task not found with ID %d", patch.ID)}
@@ -727,7 +736,7 @@ func (s *Store) patchTaskStatusImpl(ctx context.Context, tx *Tx, patch *api.Task
 		UPDATE task
 		SET `+strings.Join(set, ", ")+`
 		WHERE id in (`+strings.Join(ids, ",")+`) 
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, pipeline_id, stage_id, instance_id, database_id, name, status, type, payload, earliest_allowed_ts, maintenance_window_override
 	`,
 		args...,
 	)
@@ -754,6 +763,7 @@ func (s *Store) patchTaskStatusImpl(ctx context.Context, tx *Tx, patch *api.Task
 			&taskRaw.Type,
 			&taskRaw.Payload,
 			&taskRaw.EarliestAllowedTs,
+			&taskRaw.MaintenanceWindowOverride,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// CreateTaskRunLogList creates the log lines of the task runs in a batch.
+func (s *Store) CreateTaskRunLogList(ctx context.Context, createList []*api.TaskRunLogCreate) error {
+	if len(createList) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if err := createTaskRunLogListImpl(ctx, tx, createList); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// FindTaskRunLog retrieves a list of task run logs in the order of creation based on find.
+func (s *Store) FindTaskRunLog(ctx context.Context, find *api.TaskRunLogFind) ([]*api.TaskRunLog, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findTaskRunLogImpl(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteTaskRunLog deletes the expired task run logs, and returns the number of deleted logs.
+func (s *Store) DeleteTaskRunLog(ctx context.Context, delete *api.TaskRunLogDelete) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	count, err := deleteTaskRunLogImpl(ctx, tx, delete)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, FormatError(err)
+	}
+
+	return count, nil
+}
+
+// createTaskRunLogListImpl creates the task run logs with a single statement.
+func createTaskRunLogListImpl(ctx context.Context, tx *Tx, createList []*api.TaskRunLogCreate) error {
+	var valueList []string
+	var args []interface{}
+	for _, create := range createList {
+		valueList = append(valueList, fmt.Sprintf("($%d, $%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3, len(args)+4))
+		args = append(args, create.CreatedTs, create.TaskRunID, create.Level, create.Message)
+	}
+	query := `
+		INSERT INTO task_run_log (
+			created_ts,
+			task_run_id,
+			level,
+			message
+		)
+		VALUES ` + strings.Join(valueList, ", ")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+func findTaskRunLogImpl(ctx context.Context, tx *Tx, find *api.TaskRunLogFind) ([]*api.TaskRunLog, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+
+	if v := find.TaskRunID; v != nil {
+		where, args = append(where, fmt.Sprintf("task_run_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.AfterID; v != nil {
+		where, args = append(where, fmt.Sprintf("id > $%d", len(args)+1)), append(args, *v)
+	}
+	limit := ""
+	if v := find.Limit; v != nil {
+		limit = fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			task_run_id,
+			level,
+			message
+		FROM task_run_log
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`+limit,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into taskRunLogList.
+	var taskRunLogList []*api.TaskRunLog
+	for rows.Next() {
+		var taskRunLog api.TaskRunLog
+		if err := rows.Scan(
+			&taskRunLog.ID,
+			&taskRunLog.CreatedTs,
+			&taskRunLog.TaskRunID,
+			&taskRunLog.Level,
+			&taskRunLog.Message,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		taskRunLogList = append(taskRunLogList, &taskRunLog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return taskRunLogList, nil
+}
+
+func deleteTaskRunLogImpl(ctx context.Context, tx *Tx, delete *api.TaskRunLogDelete) (int64, error) {
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM task_run_log
+		USING task_run, task, instance
+		WHERE task_run_log.task_run_id = task_run.id
+			AND task_run.task_id = task.id
+			AND task.instance_id = instance.id
+			AND instance.environment_id = $1
+			AND task_run_log.created_ts < $2
+	`, delete.EnvironmentID, delete.CreatedTsBefore)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	return count, nil
+}